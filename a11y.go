@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/chromedp"
 )
 
@@ -34,73 +37,184 @@ func (b *Biloba) A11yOutline() string {
 }
 
 func (b *Biloba) a11yOutline() (string, error) {
+	return b.a11yOutlineOf(nil)
+}
+
+// a11yOutlineOf renders the accessibility tree of the whole document, or - given a selector - of the
+// element matching it and its subtree.
+func (b *Biloba) a11yOutlineOf(selector any) (string, error) {
+	tree, err := b.a11yTreeOf(selector)
+	if err != nil {
+		return "", err
+	}
+	return renderA11yNodes(tree), nil
+}
+
+// a11yTreeOf builds the accessibility tree of the whole document, or of the element matching selector
+// and its subtree.  The element is found in the full tree by its DOM node, rather than with
+// getPartialAXTree, because the partial tree stops at the element's immediate children.
+func (b *Biloba) a11yTreeOf(selector any) ([]*a11yNode, error) {
+	session, err := b.documentSession()
+	if err != nil {
+		return nil, err
+	}
+	return b.a11yTreeIn(session, selector)
+}
+
+// a11yTreeIn is a11yTreeOf for callers that have already resolved b's documentSession.
+func (b *Biloba) a11yTreeIn(session documentSession, selector any) ([]*a11yNode, error) {
+	var backendNodeID cdp.BackendNodeID
+	var err error
+	if selector != nil {
+		backendNodeID, err = b.backendNodeID(session, selector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	nodes, err := fullAXTree(session)
+	if err != nil {
+		return nil, err
+	}
+	if selector == nil {
+		return buildA11yTree(nodes, a11yRoot(nodes)), nil
+	}
+	for _, n := range nodes {
+		if n.BackendDOMNodeID == backendNodeID {
+			return buildA11yTree(nodes, n), nil
+		}
+	}
+	return nil, fmt.Errorf("the element matching selector %v is not in the accessibility tree (is it rendered?)", selector)
+}
+
+// backendNodeID resolves selector in session's document to the DOM node the accessibility tree (and
+// the rest of CDP) knows the element by.
+func (b *Biloba) backendNodeID(session documentSession, selector any) (cdp.BackendNodeID, error) {
+	node, err := b.elementObject(session, selector)
+	if err != nil {
+		return 0, err
+	}
+	if node == nil {
+		return 0, fmt.Errorf("could not find DOM element matching selector: %v", selector)
+	}
+	var backendNodeID cdp.BackendNodeID
+	err = chromedp.Run(session.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		defer runtime.ReleaseObject(node.ObjectID).Do(ctx)
+		described, err := dom.DescribeNode().WithObjectID(node.ObjectID).Do(ctx)
+		if err == nil {
+			backendNodeID = described.BackendNodeID
+		}
+		return err
+	}))
+	return backendNodeID, err
+}
+
+// fullAXTree fetches every accessibility node of session's document.
+func fullAXTree(session documentSession) ([]*accessibility.Node, error) {
 	var nodes []*accessibility.Node
-	err := chromedp.Run(b.Context, chromedp.ActionFunc(func(ctx context.Context) error {
+	err := chromedp.Run(session.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		params := accessibility.GetFullAXTree()
+		if session.frameID != "" {
+			params = params.WithFrameID(session.frameID)
+		}
 		var err error
-		nodes, err = accessibility.GetFullAXTree().Do(ctx)
+		nodes, err = params.Do(ctx)
 		return err
 	}))
-	if err != nil {
-		return "", err
+	return nodes, err
+}
+
+// a11yNode is one line of A11yOutline: a node that carries semantics, with the meaningful nodes
+// beneath it as its children.  source is the CDP node it was built from.
+type a11yNode struct {
+	role     string
+	name     string
+	value    string
+	children []*a11yNode
+	source   *accessibility.Node
+}
+
+func (n *a11yNode) line() string {
+	line := n.role
+	if n.name != "" {
+		line += fmt.Sprintf(" %q", n.name)
 	}
-	return renderA11yTree(nodes), nil
+	if n.value != "" {
+		line += fmt.Sprintf(" (value: %q)", n.value)
+	}
+	return line
 }
 
 func renderA11yTree(nodes []*accessibility.Node) string {
 	if len(nodes) == 0 {
 		return ""
 	}
+	return renderA11yNodes(buildA11yTree(nodes, a11yRoot(nodes)))
+}
+
+// a11yRoot is the first node whose parent we don't have a record of (getFullAXTree returns the tree
+// root first, but we don't rely on ordering).
+func a11yRoot(nodes []*accessibility.Node) *accessibility.Node {
+	if len(nodes) == 0 {
+		return nil
+	}
 	byID := make(map[accessibility.NodeID]*accessibility.Node, len(nodes))
 	for _, n := range nodes {
 		byID[n.NodeID] = n
 	}
-	// The root is the first node whose parent we don't have a record of (getFullAXTree
-	// returns the tree root first, but we don't rely on ordering).
-	var root *accessibility.Node
 	for _, n := range nodes {
 		if n.ParentID == "" || byID[n.ParentID] == nil {
-			root = n
-			break
+			return n
 		}
 	}
+	return nodes[0]
+}
+
+// buildA11yTree turns root and its descendants into a11yNodes.  Ignored nodes (and InlineTextBox
+// nodes, which just mirror their StaticText parent) contribute no semantics, so they are dropped and
+// their children take their place - this flattens away presentational wrappers, and is why an ignored
+// root can come back as several trees.
+func buildA11yTree(nodes []*accessibility.Node, root *accessibility.Node) []*a11yNode {
 	if root == nil {
-		root = nodes[0]
+		return nil
 	}
-
-	out := &strings.Builder{}
-	var walk func(n *accessibility.Node, depth int)
-	walk = func(n *accessibility.Node, depth int) {
-		// Ignored nodes (and InlineTextBox nodes, which just mirror their StaticText parent)
-		// contribute no semantics, so skip the node itself but keep descending into its
-		// children at the same depth - this flattens away presentational wrappers.
-		nextDepth := depth
-		if !n.Ignored && axValueString(n.Role) != "InlineTextBox" {
-			fmt.Fprintf(out, "%s%s\n", strings.Repeat("  ", depth), a11yLine(n))
-			nextDepth = depth + 1
-		}
+	byID := make(map[accessibility.NodeID]*accessibility.Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.NodeID] = n
+	}
+	var build func(n *accessibility.Node) []*a11yNode
+	build = func(n *accessibility.Node) []*a11yNode {
+		children := []*a11yNode{}
 		for _, childID := range n.ChildIDs {
 			if child := byID[childID]; child != nil {
-				walk(child, nextDepth)
+				children = append(children, build(child)...)
 			}
 		}
+		if n.Ignored || axValueString(n.Role) == "InlineTextBox" {
+			return children
+		}
+		role := axValueString(n.Role)
+		if role == "" {
+			role = "none"
+		}
+		return []*a11yNode{{role: role, name: axValueString(n.Name), value: axValueString(n.Value), children: children, source: n}}
 	}
-	walk(root, 0)
-	return out.String()
+	return build(root)
 }
 
-func a11yLine(n *accessibility.Node) string {
-	role := axValueString(n.Role)
-	if role == "" {
-		role = "none"
-	}
-	line := role
-	if name := axValueString(n.Name); name != "" {
-		line += fmt.Sprintf(" %q", name)
+// renderA11yNodes renders trees one indented role/name line per node.
+func renderA11yNodes(trees []*a11yNode) string {
+	out := &strings.Builder{}
+	var walk func(n *a11yNode, depth int)
+	walk = func(n *a11yNode, depth int) {
+		fmt.Fprintf(out, "%s%s\n", strings.Repeat("  ", depth), n.line())
+		for _, child := range n.children {
+			walk(child, depth+1)
+		}
 	}
-	if value := axValueString(n.Value); value != "" {
-		line += fmt.Sprintf(" (value: %q)", value)
+	for _, tree := range trees {
+		walk(tree, 0)
 	}
-	return line
+	return out.String()
 }
 
 func axValueString(v *accessibility.Value) string {
@@ -0,0 +1,487 @@
+package biloba
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+)
+
+/*
+A11yRule names one of the checks [Biloba.A11yViolations] and [Biloba.BeAccessible] run.  Use the
+[A11yRules] namespace to pick them:
+
+	b.A11yViolations("form", biloba.A11yRules.UnlabeledControl, biloba.A11yRules.InvalidARIA)
+
+Read https://onsi.github.io/biloba/#accessibility-audits to learn more
+*/
+type A11yRule string
+
+/*
+A11yRules is the namespace of the rules Biloba's accessibility audit knows.  Every rule runs unless you
+name the ones you want:
+
+  - UnlabeledControl: a form control (textbox, checkbox, combobox, slider, ...) with no accessible name.
+  - ImageAlt: an image with no accessible name - an <img> missing its alt text.  alt="" is fine: it marks the image as decorative.
+  - DuplicateID: an id used by more than one element in the document.
+  - EmptyLink: a link with no accessible name.
+  - EmptyButton: a button with no accessible name.
+  - HeadingOrder: a heading more than one level deeper than the heading before it (an h2 followed by an h4).
+  - InvalidARIA: an unknown role or aria-* attribute, an aria-* value of the wrong type, or an id reference to an element that does not exist.
+
+Read https://onsi.github.io/biloba/#accessibility-audits to learn more
+*/
+var A11yRules = struct {
+	UnlabeledControl A11yRule
+	ImageAlt         A11yRule
+	DuplicateID      A11yRule
+	EmptyLink        A11yRule
+	EmptyButton      A11yRule
+	HeadingOrder     A11yRule
+	InvalidARIA      A11yRule
+}{
+	UnlabeledControl: "unlabeled-control",
+	ImageAlt:         "image-alt",
+	DuplicateID:      "duplicate-id",
+	EmptyLink:        "empty-link",
+	EmptyButton:      "empty-button",
+	HeadingOrder:     "heading-order",
+	InvalidARIA:      "invalid-aria",
+}
+
+// allA11yRules is every rule, in the order reports list them.
+var allA11yRules = []A11yRule{
+	A11yRules.UnlabeledControl,
+	A11yRules.ImageAlt,
+	A11yRules.DuplicateID,
+	A11yRules.EmptyLink,
+	A11yRules.EmptyButton,
+	A11yRules.HeadingOrder,
+	A11yRules.InvalidARIA,
+}
+
+/*
+A11yViolation is one problem the accessibility audit found: the rule it broke, a CSS selector for the
+element that broke it, and what is wrong.
+*/
+type A11yViolation struct {
+	Rule     A11yRule
+	Selector string
+	Message  string
+}
+
+/*
+A11yViolations is what [Biloba.A11yViolations] returns.  Report renders it grouped by rule and
+selector - the same text [Biloba.BeAccessible] fails with.
+*/
+type A11yViolations []A11yViolation
+
+func (v A11yViolations) Report() string {
+	if len(v) == 0 {
+		return "No accessibility violations."
+	}
+	out := &strings.Builder{}
+	if len(v) == 1 {
+		fmt.Fprintf(out, "1 accessibility violation:\n")
+	} else {
+		fmt.Fprintf(out, "%d accessibility violations:\n", len(v))
+	}
+	for _, rule := range allA11yRules {
+		bySelector := map[string][]string{}
+		selectors := []string{}
+		for _, violation := range v {
+			if violation.Rule != rule {
+				continue
+			}
+			if _, ok := bySelector[violation.Selector]; !ok {
+				selectors = append(selectors, violation.Selector)
+			}
+			bySelector[violation.Selector] = append(bySelector[violation.Selector], violation.Message)
+		}
+		if len(selectors) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s\n", rule)
+		for _, selector := range selectors {
+			fmt.Fprintf(out, "  %s\n", selector)
+			for _, message := range bySelector[selector] {
+				fmt.Fprintf(out, "    %s\n", message)
+			}
+		}
+	}
+	return out.String()
+}
+
+/*
+A11yViolations(selector, rules...) audits the element matching selector and everything inside it, and
+returns what it finds.  Pass the tab itself to audit the whole page.  With no rules, every rule in
+[A11yRules] runs:
+
+	violations := b.A11yViolations("#signup")
+	Ω(violations).Should(BeEmpty(), violations.Report())
+
+The audit runs in Go, over the page's accessibility tree and its markup - there is no audit library to
+vendor into your pages.  The accessibility tree reflects computed styles, so content hidden with
+display:none or visibility:hidden is not audited, just as a screen reader would not announce it.  It is a fast, opinionated subset of
+what a full audit tool checks, aimed at the mistakes that creep into every app.  To assert there are
+none, use [Biloba.BeAccessible], which polls.
+
+Read https://onsi.github.io/biloba/#accessibility-audits to learn more
+*/
+func (b *Biloba) A11yViolations(selector any, rules ...A11yRule) A11yViolations {
+	b.gt.Helper()
+	b.guardConfig("A11yViolations")
+	if err := validateA11yRules(rules); err != nil {
+		b.gt.Fatalf("A11yViolations: %s", err.Error())
+		return nil
+	}
+	tab, scope := b, selector
+	if t, ok := selector.(*Biloba); ok {
+		tab, scope = t, nil
+	}
+	violations, err := tab.a11yViolations(scope, rules)
+	if err != nil {
+		b.gt.Fatalf("Failed to audit accessibility:\n%s", err.Error())
+		return nil
+	}
+	return violations
+}
+
+/*
+BeAccessible(rules...) is a Gomega matcher that passes once the accessibility audit (see
+[Biloba.A11yViolations]) finds nothing wrong with the subject.  Apply it to a selector to audit that
+element and its contents, or to the tab to audit the whole page:
+
+	Eventually(b).Should(b.BeAccessible())
+	Eventually("#signup").Should(b.BeAccessible(biloba.A11yRules.UnlabeledControl))
+
+It fails with the audit's report, grouped by rule and selector.
+
+Read https://onsi.github.io/biloba/#accessibility-audits to learn more
+*/
+func (b *Biloba) BeAccessible(rules ...A11yRule) types.GomegaMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("BeAccessible")
+	if err := validateA11yRules(rules); err != nil {
+		b.gt.Fatalf("BeAccessible: %s", err.Error())
+		return nil
+	}
+	data := map[string]any{}
+	return gcustom.MakeMatcher(func(actual any) (bool, error) {
+		tab, selector := b, actual
+		if t, ok := actual.(*Biloba); ok {
+			tab, selector = t, nil
+		} else if _, err := encodeSelector(actual); err != nil {
+			return false, gomega.StopTrying(fmt.Sprintf("BeAccessible takes either a selector (a CSS string, an XPath, or a Locator) to audit one element, or the tab itself to audit the whole page.  Got:\n%s", format.Object(actual, 1)))
+		}
+		data["Subject"] = describeSnapshotSubject(selector)
+		violations, err := tab.a11yViolations(selector, rules)
+		if err != nil {
+			return false, err
+		}
+		data["Report"] = violations.Report()
+		return len(violations) == 0, nil
+	}).WithTemplate("{{if .Failure}}Expected {{.Data.Subject}} to be accessible, but it has {{.Data.Report}}{{else}}Expected {{.Data.Subject}} NOT to be accessible, but the audit found no violations.{{end}}", data)
+}
+
+func validateA11yRules(rules []A11yRule) error {
+	for _, rule := range rules {
+		if !slices.Contains(allA11yRules, rule) {
+			return fmt.Errorf("unknown rule %q - use the rules in biloba.A11yRules", rule)
+		}
+	}
+	return nil
+}
+
+// a11yFacts is what biloba.js's a11yFacts reports: the markup half of the audit.
+type a11yFacts struct {
+	IDs        []string `json:"ids"`
+	Duplicates []struct {
+		ID   string `json:"id"`
+		Path string `json:"path"`
+	} `json:"duplicates"`
+	ARIA []struct {
+		Path  string            `json:"path"`
+		Attrs map[string]string `json:"attrs"`
+	} `json:"aria"`
+}
+
+// a11yViolations runs the audit on the element matching selector, or the whole document when it is
+// nil.  The tree-based rules read the accessibility tree; the markup rules read a11yFacts.
+func (b *Biloba) a11yViolations(selector any, rules []A11yRule) (A11yViolations, error) {
+	if len(rules) == 0 {
+		rules = allA11yRules
+	}
+	session, err := b.documentSession()
+	if err != nil {
+		return nil, err
+	}
+	tree, err := b.a11yTreeIn(session, selector)
+	if err != nil {
+		return nil, err
+	}
+	var resp *bilobaJSResponse
+	if selector == nil {
+		resp = b.runBilobaFunc("a11yFacts")
+	} else {
+		resp = b.runBilobaHandler("a11yFacts", selector)
+	}
+	if resp.Error() != nil {
+		return nil, resp.Error()
+	}
+	facts := a11yFacts{}
+	if err := remarshal(resp.Result, &facts); err != nil {
+		return nil, err
+	}
+
+	violations := A11yViolations{}
+	for _, found := range a11yTreeViolations(tree, rules) {
+		violations = append(violations, A11yViolation{Rule: found.rule, Selector: b.a11ySelectorFor(session, found.node), Message: found.message})
+	}
+	violations = append(violations, a11yMarkupViolations(facts, rules)...)
+	return violations, nil
+}
+
+type a11yTreeViolation struct {
+	rule    A11yRule
+	node    *a11yNode
+	message string
+}
+
+// a11yControlRoles are the roles whose purpose a user cannot know without an accessible name.
+var a11yControlRoles = map[string]bool{
+	"textbox": true, "searchbox": true, "combobox": true, "listbox": true, "checkbox": true, "radio": true,
+	"switch": true, "slider": true, "spinbutton": true, "menuitemcheckbox": true, "menuitemradio": true,
+}
+
+// a11yTreeViolations applies the rules that read the accessibility tree, in document order.
+func a11yTreeViolations(tree []*a11yNode, rules []A11yRule) []a11yTreeViolation {
+	found := []a11yTreeViolation{}
+	report := func(rule A11yRule, n *a11yNode, format string, args ...any) {
+		if slices.Contains(rules, rule) {
+			found = append(found, a11yTreeViolation{rule: rule, node: n, message: fmt.Sprintf(format, args...)})
+		}
+	}
+	lastLevel := 0
+	for _, f := range flattenA11yTree(tree) {
+		n := f.node
+		role := strings.ToLower(n.role)
+		switch {
+		case a11yControlRoles[role] && n.name == "":
+			report(A11yRules.UnlabeledControl, n, "%s has no accessible name - give it a <label>, aria-label, or aria-labelledby", n.role)
+		case (role == "image" || role == "img") && n.name == "":
+			report(A11yRules.ImageAlt, n, `image has no alt text - describe it with alt, or mark it decorative with alt=""`)
+		case role == "link" && n.name == "":
+			report(A11yRules.EmptyLink, n, "link has no accessible name - give it text content or an aria-label")
+		case role == "button" && n.name == "":
+			report(A11yRules.EmptyButton, n, "button has no accessible name - give it text content or an aria-label")
+		case role == "heading":
+			level := a11yNodeLevel(n)
+			if level == 0 {
+				break
+			}
+			if lastLevel > 0 && level > lastLevel+1 {
+				report(A11yRules.HeadingOrder, n, "heading level %d follows level %d - level %d is skipped", level, lastLevel, lastLevel+1)
+			}
+			lastLevel = level
+		}
+	}
+	return found
+}
+
+func a11yNodeLevel(n *a11yNode) int {
+	if n.source == nil {
+		return 0
+	}
+	for _, property := range n.source.Properties {
+		if property.Name == accessibility.PropertyNameLevel {
+			level, _ := strconv.Atoi(axValueString(property.Value))
+			return level
+		}
+	}
+	return 0
+}
+
+// a11ySelectorFor names the element behind an accessibility node with biloba.js's cssPath.  Nodes with
+// no element behind them (a StaticText, say) or that cannot be resolved fall back to their role.
+func (b *Biloba) a11ySelectorFor(session documentSession, n *a11yNode) string {
+	if n.source == nil || n.source.BackendDOMNodeID == 0 {
+		return n.role
+	}
+	path := ""
+	chromedp.Run(session.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		object, err := dom.ResolveNode().WithBackendNodeID(n.source.BackendDOMNodeID).Do(ctx)
+		if err != nil {
+			return err
+		}
+		defer runtime.ReleaseObject(object.ObjectID).Do(ctx)
+		res, exp, err := runtime.CallFunctionOn(`function() { return _biloba.cssPath(this) }`).WithObjectID(object.ObjectID).WithReturnByValue(true).Do(ctx)
+		if err != nil || exp != nil {
+			return err
+		}
+		return json.Unmarshal(res.Value, &path)
+	}))
+	if path == "" {
+		return n.role
+	}
+	return path
+}
+
+// a11yMarkupViolations applies the rules that read markup: duplicate ids and invalid ARIA.
+func a11yMarkupViolations(facts a11yFacts, rules []A11yRule) A11yViolations {
+	violations := A11yViolations{}
+	if slices.Contains(rules, A11yRules.DuplicateID) {
+		for _, duplicate := range facts.Duplicates {
+			violations = append(violations, A11yViolation{Rule: A11yRules.DuplicateID, Selector: duplicate.Path, Message: fmt.Sprintf("id %q is used by more than one element", duplicate.ID)})
+		}
+	}
+	if slices.Contains(rules, A11yRules.InvalidARIA) {
+		ids := map[string]bool{}
+		for _, id := range facts.IDs {
+			ids[id] = true
+		}
+		for _, element := range facts.ARIA {
+			names := make([]string, 0, len(element.Attrs))
+			for name := range element.Attrs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if problem := checkARIAAttribute(name, element.Attrs[name], ids); problem != "" {
+					violations = append(violations, A11yViolation{Rule: A11yRules.InvalidARIA, Selector: element.Path, Message: problem})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// ariaKinds are the value types of the WAI-ARIA 1.2 attributes.  A token kind lists its allowed values;
+// "tokens" kinds take a space-separated list of them.
+var ariaKinds = map[string]string{
+	"aria-atomic": "boolean", "aria-busy": "boolean", "aria-disabled": "boolean", "aria-modal": "boolean",
+	"aria-multiline": "boolean", "aria-multiselectable": "boolean", "aria-readonly": "boolean", "aria-required": "boolean",
+
+	"aria-checked": "token:false true mixed undefined", "aria-pressed": "token:false true mixed undefined",
+	"aria-expanded": "token:false true undefined", "aria-hidden": "token:false true undefined",
+	"aria-grabbed": "token:false true undefined", "aria-selected": "token:false true undefined",
+	"aria-autocomplete": "token:inline list both none", "aria-current": "token:page step location date time true false",
+	"aria-haspopup": "token:false true menu listbox tree grid dialog", "aria-invalid": "token:grammar false spelling true",
+	"aria-live": "token:assertive off polite", "aria-orientation": "token:horizontal vertical undefined",
+	"aria-sort":       "token:ascending descending none other",
+	"aria-dropeffect": "tokens:copy execute link move none popup", "aria-relevant": "tokens:additions all removals text",
+
+	"aria-colcount": "integer", "aria-colindex": "integer", "aria-colspan": "integer", "aria-level": "integer",
+	"aria-posinset": "integer", "aria-rowcount": "integer", "aria-rowindex": "integer", "aria-rowspan": "integer",
+	"aria-setsize":  "integer",
+	"aria-valuemax": "number", "aria-valuemin": "number", "aria-valuenow": "number",
+
+	"aria-activedescendant": "idref", "aria-errormessage": "idref",
+	"aria-controls": "idrefs", "aria-describedby": "idrefs", "aria-details": "idrefs", "aria-flowto": "idrefs",
+	"aria-labelledby": "idrefs", "aria-owns": "idrefs",
+
+	"aria-label": "string", "aria-valuetext": "string", "aria-placeholder": "string", "aria-roledescription": "string",
+	"aria-keyshortcuts": "string", "aria-description": "string", "aria-braillelabel": "string",
+	"aria-brailleroledescription": "string", "aria-colindextext": "string", "aria-rowindextext": "string",
+}
+
+// ariaRoles are the WAI-ARIA 1.2 roles (abstract roles excluded - they are not for authors) plus the
+// graphics and DPUB roles browsers map.
+var ariaRoles = func() map[string]bool {
+	roles := map[string]bool{}
+	for _, role := range strings.Fields(`alert alertdialog application article banner blockquote button caption cell
+		checkbox code columnheader combobox comment complementary contentinfo definition deletion dialog directory
+		document emphasis feed figure form generic grid gridcell group heading img image insertion link list listbox
+		listitem log main mark marquee math menu menubar menuitem menuitemcheckbox menuitemradio meter navigation none
+		note option paragraph presentation progressbar radio radiogroup region row rowgroup rowheader scrollbar search
+		searchbox separator slider spinbutton status strong subscript suggestion superscript switch tab table tablist
+		tabpanel term textbox time timer toolbar tooltip tree treegrid treeitem
+		graphics-document graphics-object graphics-symbol
+		doc-abstract doc-acknowledgments doc-afterword doc-appendix doc-backlink doc-biblioentry doc-bibliography
+		doc-biblioref doc-chapter doc-colophon doc-conclusion doc-cover doc-credit doc-credits doc-dedication
+		doc-endnote doc-endnotes doc-epigraph doc-epilogue doc-errata doc-example doc-footnote doc-foreword
+		doc-glossary doc-glossref doc-index doc-introduction doc-noteref doc-notice doc-pagebreak doc-pagelist
+		doc-part doc-preface doc-prologue doc-pullquote doc-qna doc-subtitle doc-tip doc-toc`) {
+		roles[role] = true
+	}
+	return roles
+}()
+
+var ariaNumber = regexp.MustCompile(`^-?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// checkARIAAttribute returns what is wrong with one role or aria-* attribute, or "" when nothing is.
+// Values are compared the way browsers do: trimmed, and tokens case-insensitively.
+func checkARIAAttribute(name string, value string, ids map[string]bool) string {
+	trimmed := strings.TrimSpace(value)
+	if name == "role" {
+		// role is a fallback list: the first role the browser knows wins, so every role in it must be real
+		for _, role := range strings.Fields(strings.ToLower(trimmed)) {
+			if !ariaRoles[role] {
+				return fmt.Sprintf("role %q is not a WAI-ARIA role", role)
+			}
+		}
+		return ""
+	}
+	kind, known := ariaKinds[name]
+	if !known {
+		return fmt.Sprintf("%s is not a WAI-ARIA attribute", name)
+	}
+	lower := strings.ToLower(trimmed)
+	switch {
+	case kind == "boolean":
+		if lower != "true" && lower != "false" {
+			return fmt.Sprintf(`%s=%q must be "true" or "false"`, name, value)
+		}
+	case strings.HasPrefix(kind, "token:"):
+		allowed := strings.Fields(strings.TrimPrefix(kind, "token:"))
+		if !slices.Contains(allowed, lower) {
+			return fmt.Sprintf("%s=%q must be one of %s", name, value, strings.Join(allowed, ", "))
+		}
+	case strings.HasPrefix(kind, "tokens:"):
+		allowed := strings.Fields(strings.TrimPrefix(kind, "tokens:"))
+		for _, token := range strings.Fields(lower) {
+			if !slices.Contains(allowed, token) {
+				return fmt.Sprintf("%s=%q may only list %s", name, value, strings.Join(allowed, ", "))
+			}
+		}
+	case kind == "integer":
+		if _, err := strconv.Atoi(trimmed); err != nil {
+			return fmt.Sprintf("%s=%q must be an integer", name, value)
+		}
+	case kind == "number":
+		if !ariaNumber.MatchString(trimmed) {
+			return fmt.Sprintf("%s=%q must be a number", name, value)
+		}
+	case kind == "idref", kind == "idrefs":
+		refs := strings.Fields(trimmed)
+		if len(refs) == 0 {
+			return fmt.Sprintf("%s is empty - it must reference an element by id", name)
+		}
+		if kind == "idref" && len(refs) > 1 {
+			return fmt.Sprintf("%s=%q must reference a single id", name, value)
+		}
+		missing := []string{}
+		for _, ref := range refs {
+			if !ids[ref] {
+				missing = append(missing, strconv.Quote(ref))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Sprintf("%s references %s, but no element has that id", name, strings.Join(missing, ", "))
+		}
+	}
+	return ""
+}
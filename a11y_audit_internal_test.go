@@ -0,0 +1,100 @@
+package biloba
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/accessibility"
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like visual_diff_internal_test.go: they exercise the accessibility
+// audit's rules on hand-built trees and markup facts.  No browser.
+
+func heading(level string, name string) *a11yNode {
+	return &a11yNode{role: "heading", name: name, source: &accessibility.Node{
+		Properties: []*accessibility.Property{{Name: accessibility.PropertyNameLevel, Value: &accessibility.Value{Value: []byte(level)}}},
+	}}
+}
+
+func treeRules(tree []*a11yNode, rules ...A11yRule) []string {
+	if len(rules) == 0 {
+		rules = allA11yRules
+	}
+	out := []string{}
+	for _, v := range a11yTreeViolations(tree, rules) {
+		out = append(out, string(v.rule)+": "+v.message)
+	}
+	return out
+}
+
+func TestA11yTreeRulesFlagUnnamedControlsImagesLinksAndButtons(t *testing.T) {
+	g := NewWithT(t)
+	tree := []*a11yNode{ax("RootWebArea", "Page",
+		ax("textbox", ""), ax("textbox", "Email"),
+		ax("image", ""), ax("image", "Logo"),
+		ax("link", ""), ax("link", "Home"),
+		ax("button", ""), ax("button", "Save"),
+	)}
+	g.Expect(treeRules(tree)).To(ConsistOf(
+		HavePrefix("unlabeled-control: textbox has no accessible name"),
+		HavePrefix("image-alt: image has no alt text"),
+		HavePrefix("empty-link: link has no accessible name"),
+		HavePrefix("empty-button: button has no accessible name"),
+	))
+	g.Expect(treeRules(tree, A11yRules.EmptyLink)).To(HaveLen(1))
+}
+
+func TestA11yTreeRulesFlagHeadingLevelSkips(t *testing.T) {
+	g := NewWithT(t)
+	tree := []*a11yNode{ax("RootWebArea", "Page",
+		heading("1", "Title"), heading("2", "Section"), heading("4", "Too deep"), heading("2", "Back up"), heading("3", "Fine"),
+	)}
+	g.Expect(treeRules(tree)).To(Equal([]string{"heading-order: heading level 4 follows level 2 - level 3 is skipped"}))
+}
+
+func TestARIAAttributeChecks(t *testing.T) {
+	g := NewWithT(t)
+	ids := map[string]bool{"hint": true, "label": true}
+	for _, ok := range [][2]string{
+		{"role", "button"}, {"role", "switch checkbox"}, {"aria-hidden", "true"}, {"aria-checked", "Mixed"},
+		{"aria-level", "2"}, {"aria-valuenow", "-0.5"}, {"aria-describedby", "hint label"},
+		{"aria-relevant", "additions text"}, {"aria-label", "anything"}, {"aria-activedescendant", "hint"},
+	} {
+		g.Expect(checkARIAAttribute(ok[0], ok[1], ids)).To(BeEmpty(), "%s=%q", ok[0], ok[1])
+	}
+	for _, bad := range [][3]string{
+		{"role", "buton", `role "buton" is not a WAI-ARIA role`},
+		{"aria-lable", "Save", "aria-lable is not a WAI-ARIA attribute"},
+		{"aria-hidden", "yes", `must be one of false, true, undefined`},
+		{"aria-required", "required", `must be "true" or "false"`},
+		{"aria-level", "two", "must be an integer"},
+		{"aria-valuenow", "50%", "must be a number"},
+		{"aria-describedby", "hint missing", `references "missing", but no element has that id`},
+		{"aria-labelledby", " ", "is empty"},
+		{"aria-activedescendant", "hint label", "must reference a single id"},
+		{"aria-relevant", "additions everything", "may only list"},
+	} {
+		g.Expect(checkARIAAttribute(bad[0], bad[1], ids)).To(ContainSubstring(bad[2]), "%s=%q", bad[0], bad[1])
+	}
+}
+
+func TestA11yReportGroupsByRuleAndSelector(t *testing.T) {
+	g := NewWithT(t)
+	report := A11yViolations{
+		{Rule: A11yRules.InvalidARIA, Selector: "#menu", Message: "aria-lable is not a WAI-ARIA attribute"},
+		{Rule: A11yRules.UnlabeledControl, Selector: "form > input:nth-of-type(2)", Message: "textbox has no accessible name"},
+		{Rule: A11yRules.InvalidARIA, Selector: "#menu", Message: `aria-hidden="yes" must be one of false, true, undefined`},
+	}.Report()
+	g.Expect(report).To(Equal(`3 accessibility violations:
+
+unlabeled-control
+  form > input:nth-of-type(2)
+    textbox has no accessible name
+
+invalid-aria
+  #menu
+    aria-lable is not a WAI-ARIA attribute
+    aria-hidden="yes" must be one of false, true, undefined
+`))
+	g.Expect(A11yViolations{}.Report()).To(Equal("No accessibility violations."))
+}
@@ -0,0 +1,145 @@
+package biloba
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// axQuery is one ByAXRole leaf, as Locator.payload encodes it.
+type axQuery struct {
+	Role     string `json:"role"`
+	Name     string `json:"name"`
+	NameSet  bool   `json:"nameSet"`
+	NameMode string `json:"nameMode"`
+	Key      string `json:"key"`
+}
+
+// axQueriesIn finds the ByAXRole leaves in the encoded selectors among args - at any depth, since
+// an axrole leaf can sit inside an And/Or, a Within scope or a Containing filter, each of which is
+// itself an encoded selector.  Anything without an axrole in it is skipped without being decoded.
+func axQueriesIn(args ...any) []axQuery {
+	queries := []axQuery{}
+	seen := map[string]bool{}
+	var walk func(v any)
+	walk = func(v any) {
+		switch x := v.(type) {
+		case string:
+			if !strings.HasPrefix(x, "a") || !strings.Contains(x, `axrole`) {
+				return
+			}
+			var payload any
+			if json.Unmarshal([]byte(x[1:]), &payload) == nil {
+				walk(payload)
+			}
+		case map[string]any:
+			if x["by"] == "axrole" {
+				var q axQuery
+				if remarshal(x, &q) == nil && !seen[q.Key] {
+					seen[q.Key] = true
+					queries = append(queries, q)
+				}
+				return
+			}
+			for _, child := range x {
+				walk(child)
+			}
+		case []any:
+			for _, child := range x {
+				walk(child)
+			}
+		case []string:
+			for _, child := range x {
+				walk(child)
+			}
+		}
+	}
+	for _, arg := range args {
+		walk(arg)
+	}
+	return queries
+}
+
+// resolveAXQueries answers the ByAXRole leaves among args before biloba.js sees them: it asks Chrome
+// which elements have the role (and name), and hands those elements to biloba.js's locate() under
+// the query's key.  It is a no-op - and costs nothing - when args hold no ByAXRole locator.
+func (b *Biloba) resolveAXQueries(args ...any) error {
+	queries := axQueriesIn(args...)
+	if len(queries) == 0 {
+		return nil
+	}
+	b.ensureBiloba()
+	session, err := b.documentSession()
+	if err != nil {
+		return err
+	}
+	for _, q := range queries {
+		err := b.resolveAXQuery(session, q)
+		if err != nil && strings.Contains(err.Error(), "_biloba is not defined") {
+			b.reloadBiloba()
+			err = b.resolveAXQuery(session, q)
+		}
+		if err != nil {
+			return fmt.Errorf("could not query the accessibility tree for %s:\n%w", q, err)
+		}
+	}
+	return nil
+}
+
+func (q axQuery) String() string {
+	return Locator{by: "axrole", role: q.Role, name: q.Name, nameSet: q.NameSet, nameMode: q.NameMode}.String()
+}
+
+func (b *Biloba) resolveAXQuery(session documentSession, q axQuery) error {
+	var document *runtime.RemoteObject
+	if err := session.evaluate("document", &document); err != nil {
+		return err
+	}
+	return chromedp.Run(session.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		defer runtime.ReleaseObject(document.ObjectID).Do(ctx)
+		params := accessibility.QueryAXTree().WithObjectID(document.ObjectID).WithRole(q.Role)
+		if q.NameSet && q.NameMode != "contains" {
+			params = params.WithAccessibleName(q.Name)
+		}
+		nodes, err := params.Do(ctx)
+		if err != nil {
+			return err
+		}
+		key, err := json.Marshal(q.Key)
+		if err != nil {
+			return err
+		}
+		arguments := []*runtime.CallArgument{{Value: jsontext.Value(key)}}
+		for _, n := range nodes {
+			// queryAXTree includes nodes that are ignored for accessibility - a screen reader never meets them
+			if n.Ignored || n.BackendDOMNodeID == 0 {
+				continue
+			}
+			if q.NameSet && q.NameMode == "contains" && !strings.Contains(axValueString(n.Name), q.Name) {
+				continue
+			}
+			resolve := dom.ResolveNode().WithBackendNodeID(n.BackendDOMNodeID)
+			if session.contextID != 0 {
+				resolve = resolve.WithExecutionContextID(session.contextID)
+			}
+			object, err := resolve.Do(ctx)
+			if err != nil {
+				continue // the node went away between the query and now
+			}
+			defer runtime.ReleaseObject(object.ObjectID).Do(ctx)
+			arguments = append(arguments, &runtime.CallArgument{ObjectID: object.ObjectID})
+		}
+		_, exp, err := runtime.CallFunctionOn(`function(key, ...els) { return _biloba.setAXMatches(key, els) }`).WithObjectID(document.ObjectID).WithArguments(arguments).Do(ctx)
+		if err == nil && exp != nil {
+			err = exp
+		}
+		return err
+	}))
+}
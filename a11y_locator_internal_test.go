@@ -0,0 +1,49 @@
+package biloba
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like a11y_tree_internal_test.go: they check that resolveAXQueries finds
+// every ByAXRole leaf in the encoded selectors it is handed, however deeply nested.  No browser.
+
+func encoded(t *testing.T, selector any) string {
+	t.Helper()
+	enc, err := encodeSelector(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enc
+}
+
+func TestAXQueriesInIgnoresOtherSelectors(t *testing.T) {
+	g := NewWithT(t)
+	b := &Biloba{}
+	g.Expect(axQueriesIn(encoded(t, "#axrole"), encoded(t, b.ByRole("button").WithName("axrole")), 3, []string{"saxrole"})).To(BeEmpty())
+}
+
+func TestAXQueriesInFindsNestedLeaves(t *testing.T) {
+	g := NewWithT(t)
+	b := &Biloba{}
+	save := b.ByAXRole("button").WithName("Save")
+	logo := b.ByAXRole("image").WithNameContains("logo")
+	dialog := b.ByAXRole("dialog")
+	selector := b.ByCSS("li").Containing(save).Or(logo).Within(dialog)
+
+	queries := axQueriesIn(encoded(t, selector), encoded(t, save))
+	g.Expect(queries).To(ConsistOf(
+		axQuery{Role: "button", Name: "Save", NameSet: true, NameMode: "exact", Key: `["button",true,"exact","Save"]`},
+		axQuery{Role: "image", Name: "logo", NameSet: true, NameMode: "contains", Key: `["image",true,"contains","logo"]`},
+		axQuery{Role: "dialog", Key: `["dialog",false,"",""]`},
+	))
+}
+
+func TestAXQueriesInReadsArgumentSlices(t *testing.T) {
+	g := NewWithT(t)
+	b := &Biloba{}
+	queries := axQueriesIn("s#target", []any{encoded(t, b.ByAXRole("link"))})
+	g.Expect(queries).To(HaveLen(1))
+	g.Expect(queries[0].String()).To(Equal("axrole=link"))
+}
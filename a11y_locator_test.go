@@ -0,0 +1,63 @@
+package biloba_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Locators backed by Chrome's accessibility tree", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/ax_locator.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	It("follows aria-labelledby chains the way Chrome does", func() {
+		Expect(b.ByRole("button").WithName("Archive 2 messages")).NotTo(b.Exist())
+		Expect(b.GetProperty(b.ByAXRole("button").WithName("Archive 2 messages"), "id")).To(Equal("labelledby"))
+	})
+
+	It("leaves aria-hidden content out of the name", func() {
+		Expect(b.GetProperty(b.ByAXRole("button").WithName("Delete"), "id")).To(Equal("hidden-glyph"))
+	})
+
+	It("takes names from content slotted into a shadow root", func() {
+		Expect(b.ByRole("button").WithName("Slotted name")).NotTo(b.Exist())
+		Expect(b.GetProperty(b.ByAXRole("button").WithName("Slotted name"), "id")).To(Equal("inner"))
+	})
+
+	It("keeps display: contents elements", func() {
+		Expect(b.GetProperty(b.ByAXRole("button").WithName("Contents"), "id")).To(Equal("contents"))
+	})
+
+	It("skips elements that are not in the accessibility tree", func() {
+		Expect(b.ByAXRole("button").WithName("Hidden")).NotTo(b.Exist())
+	})
+
+	It("uses Chrome's role names", func() {
+		Expect(b.GetProperty(b.ByAXRole("image").WithName("Company logo"), "id")).To(Equal("logo"))
+	})
+
+	It("matches by name substring", func() {
+		Expect(b.GetProperty(b.ByAXRole("button").WithNameContains("messages"), "id")).To(Equal("labelledby"))
+	})
+
+	It("composes with the other locators", func() {
+		Expect(b.ByAXRole("button").WithName("Save")).To(b.HaveCount(2))
+		Expect(b.GetProperty(b.ByAXRole("button").WithName("Save").Within("#toolbar"), "id")).To(Equal("toolbar-save"))
+		Expect(b.GetProperty(b.ByAXRole("button").WithName("Save").Last(), "id")).To(Equal("page-save"))
+		Expect(b.GetProperty(b.ByCSS("section").Containing(b.ByAXRole("button").WithName("Save")), "id")).To(Equal("toolbar"))
+		Expect(b.GetProperty(b.ByCSS("button").And(b.ByAXRole("button").WithName("Delete")), "id")).To(Equal("hidden-glyph"))
+	})
+
+	It("flows through actions", func() {
+		b.Click(b.ByAXRole("button").WithName("Archive 2 messages"))
+		Expect("#clicked").To(b.HaveInnerText("archive"))
+		Eventually(b.ByAXRole("button").WithName("Slotted name")).Should(b.Click())
+		Expect("#clicked").To(b.HaveInnerText("slotted"))
+	})
+
+	It("describes itself in failures", func() {
+		Expect(b.ByAXRole("button").WithName("Save").String()).To(Equal(`axrole=button name="Save"`))
+		Expect(b.ByAXRole("image").WithNameContains("logo").String()).To(Equal(`axrole=image name~"logo"`))
+	})
+})
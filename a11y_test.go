@@ -40,3 +40,112 @@ var _ = Describe("A11yOutline", func() {
 		Expect(len(biloba.CapOutlineForTest("x", 1))).To(Equal(1))
 	})
 })
+
+var _ = Describe("HaveAccessibilityTree", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/accessibility.html")
+		Eventually("#open").Should(b.Exist())
+	})
+
+	It("polls until the subtree under the selector contains the spec", func() {
+		b.Click("#open")
+		Eventually("#confirm").Should(b.HaveAccessibilityTree(`
+			dialog "Delete report.pdf?"
+			  heading /^Delete/
+			  button "Cancel"
+			  button "Delete"
+		`))
+	})
+
+	It("applies to the tab to look at the whole page", func() {
+		Eventually(b).Should(b.HaveAccessibilityTree(`
+			RootWebArea "Accessibility Testpage"
+			  heading "Files"
+			  button "Delete report.pdf"
+		`))
+		Eventually(b).ShouldNot(b.HaveAccessibilityTree(`dialog`))
+	})
+
+	It("explains a mismatch and shows the tree it looked at", func() {
+		b.Click("#open")
+		Eventually("#confirm").Should(b.BeVisible())
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect("#confirm").To(b.HaveAccessibilityTree(`
+			dialog
+			  button "Remove"
+		`))
+		Expect(failure).To(SatisfyAll(
+			ContainSubstring("Expected the accessibility tree of #confirm to contain:\ndialog\n  button \"Remove\"\n"),
+			ContainSubstring("but nothing in the tree matches:\n  button \"Remove\""),
+			ContainSubstring(`  button "Cancel"`),
+		))
+	})
+
+	It("fails immediately on a malformed spec", func() {
+		b.HaveAccessibilityTree(`button Cancel`)
+		ExpectFailures(ContainSubstring("HaveAccessibilityTree was given an invalid spec"))
+	})
+})
+
+var _ = Describe("Accessibility audits", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/a11y_audit.html")
+		Eventually("#broken").Should(b.Exist())
+	})
+
+	Describe("A11yViolations", func() {
+		It("finds nothing wrong with a clean region", func() {
+			Expect(b.A11yViolations("#clean")).To(BeEmpty())
+		})
+
+		It("reports each violation with its rule and a selector for the element", func() {
+			violations := b.A11yViolations("#broken")
+			Expect(violations).To(ContainElements(
+				biloba.A11yViolation{Rule: biloba.A11yRules.HeadingOrder, Selector: "#broken > h4", Message: "heading level 4 follows level 2 - level 3 is skipped"},
+				HaveField("Selector", "#unlabeled"),
+				HaveField("Selector", "#no-alt"),
+				HaveField("Selector", "#broken > a"),
+				HaveField("Selector", "#broken > button"),
+				biloba.A11yViolation{Rule: biloba.A11yRules.DuplicateID, Selector: "#broken > span:nth-of-type(1)", Message: `id "twin" is used by more than one element`},
+				biloba.A11yViolation{Rule: biloba.A11yRules.InvalidARIA, Selector: "#broken > div:nth-of-type(1)", Message: `role "buton" is not a WAI-ARIA role`},
+				HaveField("Message", `aria-labelledby references "nowhere", but no element has that id`),
+			))
+			Expect(violations.Report()).To(SatisfyAll(
+				ContainSubstring("accessibility violations:"),
+				ContainSubstring("unlabeled-control\n  #unlabeled\n    textbox has no accessible name"),
+				ContainSubstring("image-alt\n  #no-alt\n"),
+			))
+		})
+
+		It("runs only the rules it is given", func() {
+			violations := b.A11yViolations("#broken", biloba.A11yRules.EmptyButton)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Rule).To(Equal(biloba.A11yRules.EmptyButton))
+		})
+
+		It("audits the whole page when given the tab", func() {
+			Expect(b.A11yViolations(b)).To(ContainElement(HaveField("Selector", "#unlabeled")))
+		})
+
+		It("fails on an unknown rule", func() {
+			b.A11yViolations("#broken", biloba.A11yRule("colour"))
+			ExpectFailures(ContainSubstring(`unknown rule "colour"`))
+		})
+	})
+
+	Describe("BeAccessible", func() {
+		It("polls until the audit passes", func() {
+			Expect("#fixable").NotTo(b.BeAccessible())
+			b.Run("fixLater()")
+			Eventually("#fixable").Should(b.BeAccessible())
+		})
+
+		It("fails with the report", func() {
+			var failure string
+			g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+			g.Expect("#broken").To(b.BeAccessible(biloba.A11yRules.DuplicateID))
+			Expect(failure).To(Equal("Expected #broken to be accessible, but it has 2 accessibility violations:\n\nduplicate-id\n  #broken > span:nth-of-type(1)\n    id \"twin\" is used by more than one element\n  #broken > span:nth-of-type(2)\n    id \"twin\" is used by more than one element\n"))
+		})
+	})
+})
@@ -0,0 +1,344 @@
+package biloba
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+)
+
+/*
+HaveAccessibilityTree(spec) is a Gomega matcher that passes once the accessibility tree under the
+subject contains the structure spec describes.  spec is written the way [Biloba.A11yOutline] prints the
+tree - one `role "name"` line per node, children indented beneath their parent:
+
+	Eventually("dialog").Should(b.HaveAccessibilityTree(`
+		dialog "Delete file?"
+		  heading /^Delete/
+		  button "Cancel"
+		  button "Delete"
+	`))
+
+It is a partial match, so it survives markup churn: a spec line's children must appear beneath it (at
+any depth, in the order given), and nodes the spec does not mention are allowed anywhere.  A name in
+double quotes must match exactly, a name between slashes is a regular expression, and a line with no
+name matches any name; roles are compared case-insensitively.  A line may also carry a value, as
+A11yOutline prints it: `textbox "Email" (value: "a@b.c")`.
+
+Apply it to a selector to look under that element (the element itself included), or to the tab to look
+at the whole page.  The spec is checked when the matcher is made - a malformed spec fails the spec
+immediately rather than polling.
+
+Read https://onsi.github.io/biloba/#asserting-on-the-accessibility-tree to learn more
+*/
+func (b *Biloba) HaveAccessibilityTree(spec string) types.GomegaMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveAccessibilityTree")
+	roots, err := parseA11ySpec(spec)
+	if err != nil {
+		b.gt.Fatalf("HaveAccessibilityTree was given an invalid spec:\n%s", err.Error())
+		return nil
+	}
+	data := map[string]any{"Spec": renderA11ySpec(roots)}
+	return gcustom.MakeMatcher(func(actual any) (bool, error) {
+		tab, selector, err := a11yTreeSubject(b, actual)
+		if err != nil {
+			return false, err
+		}
+		data["Subject"] = describeSnapshotSubject(selector)
+		tree, err := tab.a11yTreeOf(selector)
+		if err != nil {
+			return false, err
+		}
+		data["Tree"] = renderA11yNodes(tree)
+		if matchA11ySpec(roots, tree) {
+			return true, nil
+		}
+		data["Reason"] = explainA11yMismatch(roots, tree)
+		return false, nil
+	}).WithTemplate("{{if .Failure}}Expected the accessibility tree of {{.Data.Subject}} to contain:\n{{.Data.Spec}}\n{{.Data.Reason}}\n\nThe accessibility tree is:\n{{.Data.Tree}}{{else}}Expected the accessibility tree of {{.Data.Subject}} NOT to contain:\n{{.Data.Spec}}\nbut it does.  The accessibility tree is:\n{{.Data.Tree}}{{end}}", data)
+}
+
+// a11yTreeSubject resolves what HaveAccessibilityTree was applied to: the tab means the whole
+// document, anything else has to be a selector.
+func a11yTreeSubject(b *Biloba, actual any) (*Biloba, any, error) {
+	if tab, ok := actual.(*Biloba); ok {
+		return tab, nil, nil
+	}
+	if _, err := encodeSelector(actual); err != nil {
+		return nil, nil, gomega.StopTrying(fmt.Sprintf("HaveAccessibilityTree takes either a selector (a CSS string, an XPath, or a Locator) to look under one element, or the tab itself to look at the whole page.  Got:\n%s", format.Object(actual, 1)))
+	}
+	return b, actual, nil
+}
+
+// a11ySpecNode is one line of a HaveAccessibilityTree spec.  A nil name or value matches anything.
+type a11ySpecNode struct {
+	source   string
+	role     string
+	name     *a11yTextPattern
+	value    *a11yTextPattern
+	children []*a11ySpecNode
+}
+
+// a11yTextPattern is a spec's name or value: an exact string, or a regular expression.
+type a11yTextPattern struct {
+	exact string
+	re    *regexp.Regexp
+}
+
+func (p *a11yTextPattern) matches(s string) bool {
+	if p == nil {
+		return true
+	}
+	if p.re != nil {
+		return p.re.MatchString(s)
+	}
+	return p.exact == s
+}
+
+func (s *a11ySpecNode) matchesLine(n *a11yNode) bool {
+	return strings.EqualFold(s.role, n.role) && s.name.matches(n.name) && s.value.matches(n.value)
+}
+
+// parseA11ySpec parses an indented spec into its trees.  Indentation is compared as text rather than
+// counted, so tabs and spaces both work so long as they are used consistently: a child's indentation
+// must extend its parent's, and a line that dedents must line up with an earlier line.
+func parseA11ySpec(spec string) ([]*a11ySpecNode, error) {
+	type open struct {
+		indent string
+		node   *a11ySpecNode
+	}
+	roots := []*a11ySpecNode{}
+	stack := []open{}
+	rootIndent := ""
+	for i, raw := range strings.Split(spec, "\n") {
+		text := strings.TrimSpace(raw)
+		if text == "" {
+			continue
+		}
+		indent := raw[:strings.Index(raw, text)]
+		node, err := parseA11ySpecLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d (%s): %w", i+1, text, err)
+		}
+		if len(roots) == 0 {
+			rootIndent = indent
+		}
+		popped := ""
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if len(indent) > len(top.indent) && strings.HasPrefix(indent, top.indent) {
+				break
+			}
+			stack = stack[:len(stack)-1]
+			popped = top.indent
+			if popped == indent {
+				break
+			}
+		}
+		switch {
+		case popped != "" && popped != indent, len(stack) == 0 && indent != rootIndent:
+			return nil, fmt.Errorf("line %d (%s) is not indented in line with any line above it", i+1, text)
+		case len(stack) == 0:
+			roots = append(roots, node)
+		default:
+			parent := stack[len(stack)-1].node
+			parent.children = append(parent.children, node)
+		}
+		stack = append(stack, open{indent: indent, node: node})
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("the spec is empty - it needs at least one role line")
+	}
+	return roots, nil
+}
+
+// parseA11ySpecLine parses `role`, `role "name"` or `role /name/`, optionally followed by
+// `(value: "v")` or `(value: /v/)`.
+func parseA11ySpecLine(text string) (*a11ySpecNode, error) {
+	node := &a11ySpecNode{source: text}
+	role, rest, _ := strings.Cut(text, " ")
+	node.role = role
+	rest = strings.TrimSpace(rest)
+	var err error
+	if rest != "" && !strings.HasPrefix(rest, "(value:") {
+		node.name, rest, err = parseA11yTextPattern(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name: %w", err)
+		}
+		rest = strings.TrimSpace(rest)
+	}
+	if strings.HasPrefix(rest, "(value:") {
+		node.value, rest, err = parseA11yTextPattern(strings.TrimSpace(strings.TrimPrefix(rest, "(value:")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+		rest = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rest, ")") {
+			return nil, fmt.Errorf(`a value must be closed with ")"`)
+		}
+		rest = strings.TrimSpace(rest[1:])
+	}
+	if rest != "" {
+		return nil, fmt.Errorf(`unexpected %q - a name must be "quoted" or a /regular expression/`, rest)
+	}
+	return node, nil
+}
+
+// parseA11yTextPattern parses a Go-quoted string or a /regular expression/ off the front of s,
+// returning what is left.
+func parseA11yTextPattern(s string) (*a11yTextPattern, string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return nil, "", fmt.Errorf("unterminated string %s", s)
+		}
+		exact, _ := strconv.Unquote(quoted)
+		return &a11yTextPattern{exact: exact}, s[len(quoted):], nil
+	case strings.HasPrefix(s, "/"):
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '/':
+				re, err := regexp.Compile(s[1:i])
+				if err != nil {
+					return nil, "", err
+				}
+				return &a11yTextPattern{re: re}, s[i+1:], nil
+			}
+		}
+		return nil, "", fmt.Errorf("unterminated regular expression %s", s)
+	}
+	return nil, "", fmt.Errorf(`expected a "quoted" string or a /regular expression/, got %s`, s)
+}
+
+func renderA11ySpec(roots []*a11ySpecNode) string {
+	out := &strings.Builder{}
+	var walk func(s *a11ySpecNode, depth int)
+	walk = func(s *a11ySpecNode, depth int) {
+		fmt.Fprintf(out, "%s%s\n", strings.Repeat("  ", depth), s.source)
+		for _, child := range s.children {
+			walk(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return out.String()
+}
+
+// a11yFlatNode is one node of a tree laid out in document order; end is the index just past its
+// subtree, so a node's descendants are exactly the entries between it and end.
+type a11yFlatNode struct {
+	node *a11yNode
+	end  int
+}
+
+func flattenA11yTree(trees []*a11yNode) []a11yFlatNode {
+	flat := []a11yFlatNode{}
+	var walk func(n *a11yNode)
+	walk = func(n *a11yNode) {
+		i := len(flat)
+		flat = append(flat, a11yFlatNode{node: n})
+		for _, child := range n.children {
+			walk(child)
+		}
+		flat[i].end = len(flat)
+	}
+	for _, tree := range trees {
+		walk(tree)
+	}
+	return flat
+}
+
+// matchA11ySpec reports whether tree contains the spec: each spec line matches a node, its children
+// match nodes in that node's subtree, and siblings match disjoint subtrees in document order.
+func matchA11ySpec(roots []*a11ySpecNode, tree []*a11yNode) bool {
+	flat := flattenA11yTree(tree)
+	type key struct {
+		spec *a11ySpecNode
+		at   int
+	}
+	// seqKey names one call of sequence: the tail of a sibling list (by the address of its first
+	// element, which is unique to that tail) and the range of flat it must fit in.
+	type seqKey struct {
+		specs    **a11ySpecNode
+		from, to int
+	}
+	// matchesAt and sequence are both memoized: sequence matching backtracks, and without this a deep
+	// spec against a wide tree - or a run of sibling lines against a long list that doesn't fit - would
+	// redo the same work combinatorially often.
+	memo := map[key]bool{}
+	seqMemo := map[seqKey]bool{}
+	var matchesAt func(spec *a11ySpecNode, at int) bool
+	var sequence func(specs []*a11ySpecNode, from int, to int) bool
+	matchesAt = func(spec *a11ySpecNode, at int) bool {
+		k := key{spec, at}
+		if result, ok := memo[k]; ok {
+			return result
+		}
+		result := spec.matchesLine(flat[at].node) && sequence(spec.children, at+1, flat[at].end)
+		memo[k] = result
+		return result
+	}
+	sequence = func(specs []*a11ySpecNode, from int, to int) bool {
+		if len(specs) == 0 {
+			return true
+		}
+		k := seqKey{&specs[0], from, to}
+		if result, ok := seqMemo[k]; ok {
+			return result
+		}
+		result := false
+		for at := from; at < to; at++ {
+			if matchesAt(specs[0], at) && sequence(specs[1:], flat[at].end, to) {
+				result = true
+				break
+			}
+		}
+		seqMemo[k] = result
+		return result
+	}
+	return sequence(roots, 0, len(flat))
+}
+
+// explainA11yMismatch points at the first spec line that matches no node at all - usually the whole
+// story.  When every line matches something, the nesting or the order is what is off.
+func explainA11yMismatch(roots []*a11ySpecNode, tree []*a11yNode) string {
+	flat := flattenA11yTree(tree)
+	var missing *a11ySpecNode
+	var walk func(s *a11ySpecNode)
+	walk = func(s *a11ySpecNode) {
+		if missing != nil {
+			return
+		}
+		found := false
+		for _, f := range flat {
+			if s.matchesLine(f.node) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = s
+			return
+		}
+		for _, child := range s.children {
+			walk(child)
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	if missing != nil {
+		return fmt.Sprintf("but nothing in the tree matches:\n  %s", missing.source)
+	}
+	return "but although every line matches some node, they are not nested and ordered as the spec has them."
+}
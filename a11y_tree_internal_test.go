@@ -0,0 +1,127 @@
+package biloba
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like visual_diff_internal_test.go: they exercise HaveAccessibilityTree's
+// spec parser and partial tree match on hand-built trees.  No browser.
+
+func ax(role string, name string, children ...*a11yNode) *a11yNode {
+	return &a11yNode{role: role, name: name, children: children}
+}
+
+func dialogTree() []*a11yNode {
+	return []*a11yNode{
+		ax("dialog", "Delete file?",
+			ax("generic", "",
+				ax("heading", "Delete report.pdf?", ax("StaticText", "Delete report.pdf?")),
+				ax("paragraph", "", ax("StaticText", "This cannot be undone.")),
+			),
+			ax("group", "",
+				ax("button", "Cancel"),
+				ax("button", "Delete"),
+			),
+		),
+	}
+}
+
+func specMatches(t *testing.T, spec string) bool {
+	t.Helper()
+	roots, err := parseA11ySpec(spec)
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+	return matchA11ySpec(roots, dialogTree())
+}
+
+func TestA11ySpecMatchesAPartialTree(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(specMatches(t, `
+		dialog "Delete file?"
+		  heading /^Delete/
+		  button "Cancel"
+		  button "Delete"
+	`)).To(BeTrue())
+	g.Expect(specMatches(t, `button "Delete"`)).To(BeTrue())
+	g.Expect(specMatches(t, `DIALOG`)).To(BeTrue())
+	g.Expect(specMatches(t, "dialog\n\tgroup\n\t\tbutton \"Cancel\"")).To(BeTrue())
+}
+
+func TestA11ySpecRequiresDocumentOrderAndNesting(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(specMatches(t, `
+		dialog
+		  button "Delete"
+		  button "Cancel"
+	`)).To(BeFalse())
+	g.Expect(specMatches(t, `
+		heading
+		  button "Cancel"
+	`)).To(BeFalse())
+	g.Expect(specMatches(t, `
+		dialog
+		  button "Cancel"
+		  button "Cancel"
+	`)).To(BeFalse())
+}
+
+func TestA11ySpecDoesNotBacktrackCombinatorially(t *testing.T) {
+	g := NewWithT(t)
+	items := []*a11yNode{}
+	for range 100 {
+		items = append(items, ax("listitem", "", ax("StaticText", "item")))
+	}
+	roots, err := parseA11ySpec(`
+		list
+		  listitem
+		  listitem
+		  listitem
+		  listitem
+		  button "Nope"
+	`)
+	g.Expect(err).NotTo(HaveOccurred())
+	start := time.Now()
+	g.Expect(matchA11ySpec(roots, []*a11yNode{ax("list", "", items...)})).To(BeFalse())
+	g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+}
+
+func TestA11ySpecMatchesNamesExactlyUnlessTheyAreRegexps(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(specMatches(t, `heading "Delete"`)).To(BeFalse())
+	g.Expect(specMatches(t, `heading /report\.pdf/`)).To(BeTrue())
+	g.Expect(specMatches(t, `StaticText /cannot be undone/`)).To(BeTrue())
+}
+
+func TestA11ySpecParsesValues(t *testing.T) {
+	g := NewWithT(t)
+	roots, err := parseA11ySpec(`textbox "Email" (value: /@example\.com$/)`)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(matchA11ySpec(roots, []*a11yNode{{role: "textbox", name: "Email", value: "a@example.com"}})).To(BeTrue())
+	g.Expect(matchA11ySpec(roots, []*a11yNode{{role: "textbox", name: "Email", value: "a@example.org"}})).To(BeFalse())
+}
+
+func TestA11ySpecRejectsMalformedSpecs(t *testing.T) {
+	g := NewWithT(t)
+	for spec, message := range map[string]string{
+		"":                    "the spec is empty",
+		`button "Cancel`:      "unterminated string",
+		`button /Can(cel/`:    "invalid name",
+		`button Cancel`:       `expected a "quoted" string`,
+		"dialog\n    a\n  b":  "not indented in line",
+		"  dialog\nbutton":    "not indented in line",
+		`textbox (value: "x"`: `closed with ")"`,
+	} {
+		_, err := parseA11ySpec(spec)
+		g.Expect(err).To(MatchError(ContainSubstring(message)), spec)
+	}
+}
+
+func TestA11yMismatchPointsAtTheMissingLine(t *testing.T) {
+	g := NewWithT(t)
+	roots, _ := parseA11ySpec("dialog\n  button \"Remove\"")
+	g.Expect(explainA11yMismatch(roots, dialogTree())).To(ContainSubstring(`button "Remove"`))
+	roots, _ = parseA11ySpec("dialog\n  button \"Delete\"\n  button \"Cancel\"")
+	g.Expect(explainA11yMismatch(roots, dialogTree())).To(ContainSubstring("not nested and ordered"))
+}
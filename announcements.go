@@ -0,0 +1,140 @@
+package biloba
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gcustom"
+)
+
+/*
+Announcement is one thing a live region announced: its text, its politeness ("polite" or "assertive"),
+a CSS selector for the region, and when the page made it.
+*/
+type Announcement struct {
+	Text       string
+	Politeness string
+	Region     string
+	Time       time.Time
+}
+
+func (a Announcement) String() string {
+	return fmt.Sprintf("[%s] %q (%s)", a.Politeness, a.Text, a.Region)
+}
+
+/*
+Announcements() returns what the page's live regions have announced, oldest first.  A live region is
+an element with aria-live (other than "off"), role="status", role="alert" or role="log":
+
+	b.Click("#save")
+	Eventually(b).Should(b.HaveAnnounced("Saved"))
+	Ω(b.Announcements()).Should(ContainElement(HaveField("Politeness", "polite")))
+
+A screen-reader user learns about "Saved" only from the announcement, and the region usually clears
+itself within a second - too quickly to catch by polling its text.  So biloba.js records announcements
+as they happen, with a MutationObserver: it records a region's text each time something is added to it
+(all of its text for an atomic region, such as role="status" or aria-atomic="true", and just the added
+text otherwise), and an element with role="alert" as it is inserted.  Regions that are not rendered
+announce nothing.
+
+Recording starts when Biloba first touches the document - which any Biloba command does - and the
+record belongs to the document: it starts over when the page navigates, and keeps the latest 1000
+announcements.  Announcements is a one-shot snapshot: it does not poll.
+
+Read https://onsi.github.io/biloba/#live-region-announcements to learn more
+*/
+func (b *Biloba) Announcements() []Announcement {
+	b.gt.Helper()
+	b.guardConfig("Announcements")
+	announcements, err := b.announcements()
+	if err != nil {
+		b.gt.Fatalf("Failed to read announcements:\n%s", err.Error())
+		return nil
+	}
+	return announcements
+}
+
+/*
+HaveAnnounced(expected) is a Gomega matcher that passes once a live region has announced text that
+matches expected - a string must match exactly, or pass a Gomega matcher.  Apply it to the tab:
+
+	Eventually(b).Should(b.HaveAnnounced("Saved"))
+	Eventually(b).Should(b.HaveAnnounced(ContainSubstring("3 results")))
+
+It returns a [ValueMatcher], so you can keep the [Announcement] that matched (the latest one, when
+several do):
+
+	var announcement biloba.Announcement
+	Eventually(b).Should(b.HaveAnnounced("Payment failed").Capture(&announcement))
+	Ω(announcement.Politeness).Should(Equal("assertive"))
+
+See [Biloba.Announcements] for how announcements are recorded.
+
+Read https://onsi.github.io/biloba/#live-region-announcements to learn more
+*/
+func (b *Biloba) HaveAnnounced(expected any) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveAnnounced")
+	matcher := matcherOrEqual(expected)
+	data := map[string]any{"Expected": format.Object(expected, 1)}
+	return capturableResult(gcustom.MakeMatcher(func(actual any) (bool, error) {
+		tab, ok := actual.(*Biloba)
+		if !ok {
+			return false, gomega.StopTrying(fmt.Sprintf("HaveAnnounced must be applied to the tab (Eventually(b).Should(b.HaveAnnounced(...))).  Got:\n%s", format.Object(actual, 1)))
+		}
+		announcements, err := tab.announcements()
+		if err != nil {
+			return false, err
+		}
+		delete(data, "Result")
+		data["Announcements"] = renderAnnouncements(announcements)
+		for i := len(announcements) - 1; i >= 0; i-- {
+			matched, err := matcher.Match(announcements[i].Text)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				data["Result"] = announcements[i]
+				return true, nil
+			}
+		}
+		return false, nil
+	}).WithTemplate("{{if .Failure}}Expected the page to have announced{{else}}Expected the page NOT to have announced{{end}}\n{{.Data.Expected}}\n{{if .Failure}}but {{else}}but it did - {{end}}{{.Data.Announcements}}", data), data)
+}
+
+// announcements reads the record biloba.js keeps of the document's live-region announcements.
+func (b *Biloba) announcements() ([]Announcement, error) {
+	r := b.runBilobaFunc("announcements")
+	if r.Error() != nil {
+		return nil, r.Error()
+	}
+	recorded := []struct {
+		Text       string  `json:"text"`
+		Politeness string  `json:"politeness"`
+		Region     string  `json:"region"`
+		Time       float64 `json:"time"`
+	}{}
+	if err := remarshal(r.Result, &recorded); err != nil {
+		return nil, err
+	}
+	announcements := []Announcement{}
+	for _, a := range recorded {
+		announcements = append(announcements, Announcement{Text: a.Text, Politeness: a.Politeness, Region: a.Region, Time: time.UnixMilli(int64(a.Time))})
+	}
+	return announcements, nil
+}
+
+func renderAnnouncements(announcements []Announcement) string {
+	if len(announcements) == 0 {
+		return "nothing has been announced."
+	}
+	out := &strings.Builder{}
+	out.WriteString("the announcements are:\n")
+	for _, a := range announcements {
+		fmt.Fprintf(out, "  %s\n", a)
+	}
+	return out.String()
+}
@@ -0,0 +1,64 @@
+package biloba_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/onsi/biloba"
+)
+
+var _ = Describe("Live-region announcements", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/announcements.html")
+		Eventually("#status").Should(b.Exist())
+	})
+
+	It("catches an announcement the region has already cleared", func() {
+		b.Click("#save")
+		Eventually("#status").Should(b.HaveInnerText(""))
+		Expect(b).To(b.HaveAnnounced("Saved"))
+		Expect(b.Announcements()).To(HaveExactElements(
+			SatisfyAll(HaveField("Text", "Saving..."), HaveField("Politeness", "polite"), HaveField("Region", "#status")),
+			HaveField("Text", "Saved"),
+		))
+	})
+
+	It("records what is added to a log, not the whole log", func() {
+		b.Click("#add-entry")
+		b.Click("#add-entry")
+		Eventually(b).Should(b.HaveAnnounced("Entry 2"))
+		Expect(b.Announcements()).To(HaveExactElements(HaveField("Text", "Entry 1"), HaveField("Text", "Entry 2")))
+	})
+
+	It("records an inserted alert as assertive", func() {
+		b.Click("#fail")
+		var announcement biloba.Announcement
+		Eventually(b).Should(b.HaveAnnounced(ContainSubstring("failed")).Capture(&announcement))
+		Expect(announcement.Politeness).To(Equal("assertive"))
+		Expect(announcement.Time).To(BeTemporally("~", time.Now(), 5*time.Second))
+	})
+
+	It("ignores regions that are off or not rendered", func() {
+		b.Click("#quiet")
+		Consistently(b, 200*time.Millisecond).ShouldNot(b.HaveAnnounced(ContainSubstring("Not announced")))
+		Expect(b.Announcements()).To(BeEmpty())
+	})
+
+	It("fails with what was announced", func() {
+		b.Click("#add-entry")
+		Eventually(b).Should(b.HaveAnnounced("Entry 1"))
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect(b).To(b.HaveAnnounced("Saved"))
+		Expect(failure).To(Equal("Expected the page to have announced\n    <string>: Saved\nbut the announcements are:\n  [polite] \"Entry 1\" (#log)\n"))
+	})
+
+	It("starts over when the page navigates", func() {
+		b.Click("#save")
+		Eventually(b).Should(b.HaveAnnounced("Saved"))
+		b.Navigate(fixtureServer + "/announcements.html")
+		Expect(b.Announcements()).To(BeEmpty())
+	})
+})
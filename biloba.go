@@ -461,6 +461,21 @@ func BilobaConfigPollTrajectory(enabled ...bool) func(*Biloba) {
 	}
 }
 
+/*
+Pass BilobaConfigFailureMutations to [ConnectToChrome] to control whether Biloba records the DOM mutations in every tab and, on failure, attaches what churned during the last poll.
+
+A spec that flakes on a node that matched and then stopped matching is usually looking at a component that re-mounts over and over.  With this on, Biloba keeps a document-wide mutation recorder running in every page and, when a spec fails, attaches the mutations since the last poll began: counts by node and attribute, the elements that were re-mounted, and the tail of the timeline.  It needs BilobaConfigPollTrajectory, which knows when the last poll began.
+
+It is off by default - the recorder observes every mutation of every page; BilobaConfigFailureMutations() turns it on.
+
+Read https://onsi.github.io/biloba/#recording-mutations to learn more
+*/
+func BilobaConfigFailureMutations(enabled ...bool) func(*Biloba) {
+	return func(b *Biloba) {
+		b.failureMutations = boolArg(enabled)
+	}
+}
+
 /*
 Pass BilobaConfigFailureScreenshotsSize to [ConnectToChrome] to set the size for the screenshots generated on failure
 */
@@ -567,6 +582,37 @@ func BilobaConfigScreenshotChannelTolerance(delta int) func(*Biloba) {
 	}
 }
 
+/*
+Pass BilobaConfigCPUThrottle to [ConnectToChrome] to throttle every tab's CPU by rate (see [Biloba.ThrottleCPU]) - a "slow lane" run of the whole suite:
+
+	b = biloba.ConnectToChrome(GinkgoT(), biloba.BilobaConfigCPUThrottle(4))
+
+The rate must be at least 1 (no throttling); ConnectToChrome fails otherwise.  The BILOBA_CPU_THROTTLE environment variable does the same thing at runtime (this option wins if both are set), which is the easy way to add a slow lane to CI without touching the suite.
+
+Read https://onsi.github.io/biloba/#cpu-throttling to learn more
+*/
+func BilobaConfigCPUThrottle(rate float64) func(*Biloba) {
+	return func(b *Biloba) {
+		b.suiteCPUThrottleRate = rate
+		b.suiteCPUThrottleSet = true
+	}
+}
+
+/*
+Pass BilobaConfigStrictSelectors to [ConnectToChrome] to make every tab strict about selectors: a single-element action or getter (Click, GetValue, HaveInnerText, ...) whose selector matches more than one element fails, listing the matches, instead of quietly using the first:
+
+	b = biloba.ConnectToChrome(GinkgoT(), biloba.BilobaConfigStrictSelectors())
+
+It is off by default.  A view can opt in or out on its own with [Biloba.Strict].
+
+Read https://onsi.github.io/biloba/#strict-selectors to learn more
+*/
+func BilobaConfigStrictSelectors(enabled ...bool) func(*Biloba) {
+	return func(b *Biloba) {
+		b.strictSelectors = boolArg(enabled)
+	}
+}
+
 /*
 Call ConnectToChrome(GinkgoT()) to connect to a Chrome browser
 
@@ -616,6 +662,21 @@ func ConnectToChrome(ginkgoT GinkgoTInterface, options ...BilobaConfigOption) *B
 		}
 	}
 	b.updateScreenshots = b.truthyEnv("BILOBA_UPDATE_SCREENSHOTS")
+	if b.suiteCPUThrottleSet {
+		if b.suiteCPUThrottleRate < 1 {
+			ginkgoT.Fatalf("BilobaConfigCPUThrottle must be a number of at least 1, got %v", b.suiteCPUThrottleRate)
+			return nil
+		}
+	} else {
+		if value, set := os.LookupEnv("BILOBA_CPU_THROTTLE"); set && value != "" {
+			rate, err := strconv.ParseFloat(value, 64)
+			if err != nil || rate < 1 {
+				ginkgoT.Fatalf("BILOBA_CPU_THROTTLE must be a number of at least 1, got %q", value)
+				return nil
+			}
+			b.suiteCPUThrottleRate = rate
+		}
+	}
 
 	if b.ChromeConnection.WebSocketURL == "" {
 		var cc ChromeConnection
@@ -669,6 +730,12 @@ func ConnectToChrome(ginkgoT GinkgoTInterface, options ...BilobaConfigOption) *B
 		return nil
 	}
 
+	// Slow the root tab down to the suite's rate (see BilobaConfigCPUThrottle); a no-op when unthrottled.
+	if err := b.applySuiteCPUThrottle(); err != nil {
+		ginkgoT.Fatalf("failed to throttle CPU: %w", err)
+		return nil
+	}
+
 	b.downloadDir = b.gt.TempDir()
 	b.setUpListeners()
 
@@ -796,6 +863,11 @@ type Biloba struct {
 	pollingInterval *time.Duration
 	pollingCtx      context.Context
 
+	// strictSelectors is the suite's BilobaConfigStrictSelectors, read through b.root; strict is a
+	// Strict() view's override of it (nil means "inherit").  Like realistic, strict rides the shallow clone.
+	strictSelectors bool
+	strict          *bool
+
 	downloadDir     string
 	downloads       map[string]*Download
 	downloadHistory map[string]time.Time
@@ -820,6 +892,7 @@ type Biloba struct {
 	failureScreenshots             bool // default true
 	failureOutlines                bool // default false
 	failureOutlinesSet             bool // whether the suite set failureOutlines explicitly
+	failureMutations               bool // default false; read through b.root
 	progressReportScreenshots      bool // default true
 	inlineScreenshots              bool // default true (subject to terminal support)
 	inlineScreenshotsSet           bool // whether the suite set inlineScreenshots explicitly
@@ -837,14 +910,33 @@ type Biloba struct {
 	screenshotTolerance screenshotTolerance
 	updateScreenshots   bool
 
-	// colorSchemeEmulated records whether this TARGET currently carries an emulated
-	// prefers-color-scheme (see InColorSchemes).  Unlike the freeze stylesheet, which lives in the
-	// page and dies with a navigation, emulation.SetEmulatedMedia is a target-level override that
-	// outlives Navigate - so Prepare() clears it, but only when this flag says there is something to
-	// clear (Prepare runs before every spec and an unconditional CDP round trip there is not free).
-	// It is a pointer so the shallow clone-with-a-flag views (Realistic() and friends) share the
-	// tab's one flag instead of each getting a copy that the tab never sees.
-	colorSchemeEmulated *bool
+	// media is the emulated media on this TARGET (see EmulateMedia, InColorSchemes and
+	// InMediaVariants).  Unlike the freeze stylesheet, which lives in the page and dies with a
+	// navigation, emulation.SetEmulatedMedia is a target-level override that outlives Navigate - so
+	// Prepare() clears it.  It is a pointer so the shallow clone-with-a-flag views (Realistic() and
+	// friends) share the tab's one state instead of each getting a copy that the tab never sees.
+	media *mediaState
+
+	// clock holds the fake clock InstallClock put on this target, if any.  Like media the
+	// clock's new-document script outlives Navigate, so Prepare() uninstalls one whose DeferCleanup
+	// never ran.
+	clock *clockSlot
+
+	// suiteCPUThrottleRate is the suite's CPU throttling rate (BilobaConfigCPUThrottle or
+	// BILOBA_CPU_THROTTLE), read through b.root; cpuThrottle is the rate this TARGET is actually at.  Like
+	// media it is a pointer shared by the clone-with-a-flag views, and Prepare() puts it
+	// back to the suite's rate when a spec moved it.
+	suiteCPUThrottleRate float64
+	suiteCPUThrottleSet  bool // whether the suite passed BilobaConfigCPUThrottle
+	cpuThrottle          *float64
+
+	// frame scopes this view to an iframe's document (see Frame); nil for the tab itself.
+	// frameContexts tracks the main world of each frame this tab renders in-process, and frameTargets
+	// holds the sessions Biloba attached to its cross-origin frames.  Both are shared by every view of
+	// the tab, like media.
+	frame         *frameScope
+	frameContexts *frameContexts
+	frameTargets  *frameTargets
 
 	// pollTrajectory opts a suite into recording the (elapsed, value) trajectory of polled reads and
 	// attaching the most-recent series on failure (see BilobaConfigPollTrajectory).  Off by default.
@@ -879,6 +971,7 @@ func (b *Biloba) GomegaString() string {
 }
 
 func newBiloba(ginkgoT GinkgoTInterface) *Biloba {
+	unthrottled := 1.0
 	b := &Biloba{
 		gt:               ginkgoT,
 		lock:             &sync.Mutex{},
@@ -893,7 +986,11 @@ func newBiloba(ginkgoT GinkgoTInterface) *Biloba {
 		pollTrajectory:            true,
 		probes:                    &probeRecorder{},
 		occlusions:                &occlusionRecorder{},
-		colorSchemeEmulated:       new(bool),
+		media:                     &mediaState{},
+		clock:                     &clockSlot{},
+		cpuThrottle:               &unthrottled,
+		frameContexts:             newFrameContexts(),
+		frameTargets:              &frameTargets{byFrame: map[cdp.FrameID]*frameTarget{}},
 	}
 	return b
 }
@@ -973,7 +1070,7 @@ func (b *Biloba) Prepare() {
 	// or an occluded click carried over from the previous spec would diagnose the wrong spec.
 	b.resetPollDiagnostics()
 
-	if b.failureScreenshots || b.failureOutlines {
+	if b.failureScreenshots || b.failureOutlines || b.failureMutations {
 		b.gt.DeferCleanup(b.attachFailureArtifactsIfFailed)
 	}
 	if b.progressReportScreenshots {
@@ -987,7 +1084,8 @@ func (b *Biloba) Prepare() {
 				fmt.Println(b.gt.Fi(1, b.gt.RenderTimeline()))
 
 				fmt.Println(b.gt.F("{{red}}{{bold}}Biloba will now sleep so you can interact with the browser.  Hit ^C when you're done to shut down the suite{{/}}"))
-				<-ctx.Done()
+				fmt.Println(b.gt.F("{{red}}{{bold}}While you're there, Alt-click any element to print locators for it{{/}}"))
+				b.PickLocators(ctx)
 			}
 		})
 	}
@@ -1213,8 +1311,19 @@ func (b *Biloba) attachFailureArtifactsIfFailed() {
 				if detached := tab.probes.renderDetachedNode(); detached != "" {
 					b.gt.AddReportEntryVisibilityFailureOrVerbose("Selector matched, then stopped matching"+suffix, detached)
 				}
+				// ...and a selector that never matched gets the step-by-step account of why, if it
+				// still matches nothing now
+				if explanation, ok := tab.explainNeverMatched(); ok {
+					b.gt.AddReportEntryVisibilityFailureOrVerbose(fmt.Sprintf("Why %q matched nothing%s", explanation.Selector, suffix), explanation.String())
+				}
 				if trajectory := tab.probes.render(); trajectory != "" {
-					b.gt.AddReportEntryVisibilityFailureOrVerbose("Poll trajectory"+suffix, trajectory)
+					b.gt.AddReportEntryVisibilityFailureOrVerbose("Poll trajectory"+tab.cpuThrottleNote()+suffix, trajectory)
+				}
+				// what churned while that poll ran is usually why the node went away
+				if start := tab.probes.windowStart(); b.failureMutations && !start.IsZero() {
+					if churn := tab.churnReport(start); churn != "" {
+						b.gt.AddReportEntryVisibilityFailureOrVerbose(fmt.Sprintf("DOM mutations during the last poll (+%ss)%s", roundDuration(time.Since(start)), suffix), churn)
+					}
 				}
 			}
 			if occluded := tab.occlusions.render(); occluded != "" {
@@ -1305,6 +1414,9 @@ func (b *Biloba) registerTabFor(c context.Context, cancel context.CancelFunc) *B
 	newG.root = b.root
 	newG.pollTrajectory = b.root.pollTrajectory
 	newG.close = cancel
+	// A spawned tab may already have loaded its frames: listening before the probe attaches means
+	// Runtime.enable's report of the existing execution contexts is heard too.
+	chromedp.ListenTarget(newG.Context, newG.frameContexts.handle)
 
 	// Probe the target to force chromedp to attach (Runtime.Enable et al.).  Two failure modes need
 	// different handling:
@@ -1369,6 +1481,10 @@ func (b *Biloba) registerTabFor(c context.Context, cancel context.CancelFunc) *B
 		cancel()
 		return nil
 	}
+	if err := newG.applySuiteCPUThrottle(); err != nil {
+		cancel()
+		return nil
+	}
 	newG.setUpListeners()
 
 	b.root.lock.Lock()
@@ -1399,6 +1515,9 @@ func (b *Biloba) ensureBiloba() {
 
 func (b *Biloba) reloadBiloba() {
 	b.run(bilobaJS)
+	if b.root != nil && b.root.failureMutations {
+		b.run("_biloba.startChurnRecorder()")
+	}
 	b.lock.Lock()
 	b.bilobaIsInstalled = true
 	b.lock.Unlock()
@@ -131,66 +131,102 @@ if (!window["_biloba"]) {
         if (state === "selected") return el.selected === true || el.getAttribute("aria-selected") === "true"
         return false
     }
-    let locate = (q) => {
-        // 1. candidate pool, piercing open shadow roots. `within` scopes to descendants of the
-        // scope element(s); an unresolved scope matches nothing.
-        let pool
-        if (q.within) {
-            let scopes = selEach(q.within)
-            if (!scopes.length) return []
-            pool = collectElements(document).filter(el => scopes.some(s => s !== el && s.contains(el)))
-        } else {
-            pool = collectElements(document)
-        }
-        // 2. base match set. and/or intersect/union operand sets (preserving pool's document order);
-        // the leaf kinds filter the pool by their predicate.
-        let matched
+    // the positional Locator filters: does box (of el) stand in the relation to abox (of anchor a)?
+    // The directional ones mirror geometry.go's BeRightOf & co. and also ask for overlap on the other
+    // axis - the same row, or the same column.
+    let laidOutBox = (el) => { let rect = el.getBoundingClientRect(); return (rect.width > 0 || rect.height > 0) ? rect : null }
+    let overlapsV = (x, y) => x.top < y.bottom && y.top < x.bottom
+    let overlapsH = (x, y) => x.left < y.right && y.left < x.right
+    let positional = {
+        rightOf: (box, abox) => box.left >= abox.right && overlapsV(box, abox),
+        leftOf: (box, abox) => box.right <= abox.left && overlapsV(box, abox),
+        above: (box, abox) => box.bottom <= abox.top && overlapsH(box, abox),
+        below: (box, abox) => box.top >= abox.bottom && overlapsH(box, abox),
+        near: (box, abox, f, el, a) => {
+            if (el.contains(a) || a.contains(el)) return false
+            let dx = Math.max(0, abox.left - box.right, box.left - abox.right)
+            let dy = Math.max(0, abox.top - box.bottom, box.top - abox.bottom)
+            return Math.hypot(dx, dy) <= f.maxPx
+        },
+    }
+    // axMatches holds, per ByAXRole query, the elements Chrome's accessibility tree matched
+    let axMatches = {}
+    b.setAXMatches = (key, els) => { axMatches[key] = els; return r() }
+    // locateBase is step 2 of locate(): the base match set.  and/or intersect/union operand sets
+    // (preserving pool's document order); the leaf kinds filter the pool by their predicate.
+    let locateBase = (q, pool) => {
         if (q.by === "and") {
             let sets = q.operands.map(op => new Set(selEach(op)))
-            matched = pool.filter(el => sets.every(s => s.has(el)))
+            return pool.filter(el => sets.every(s => s.has(el)))
         } else if (q.by === "or") {
             let sets = q.operands.map(op => new Set(selEach(op)))
-            matched = pool.filter(el => sets.some(s => s.has(el)))
+            return pool.filter(el => sets.some(s => s.has(el)))
         } else if (q.by === "css") {
-            matched = pool.filter(el => el.matches(q.value))
+            return pool.filter(el => el.matches(q.value))
         } else if (q.by === "role") {
-            matched = pool.filter(el => roleOf(el) === q.role && (!q.nameSet || matchText(accessibleName(el), q.name, q.nameMode)))
+            return pool.filter(el => roleOf(el) === q.role && (!q.nameSet || matchText(accessibleName(el), q.name, q.nameMode)))
+        } else if (q.by === "axrole") {
+            // Go resolved these through Chrome's accessibility tree just before this call (setAXMatches)
+            let found = axMatches[q.key] || []
+            return q.within ? found.filter(el => pool.includes(el)) : found
         } else if (q.by === "label") {
-            matched = pool.filter(el => el.matches("input,select,textarea,button,[contenteditable],[role]") && matchText(accessibleName(el), q.value, q.valueMode))
+            return pool.filter(el => el.matches("input,select,textarea,button,[contenteditable],[role]") && matchText(accessibleName(el), q.value, q.valueMode))
         } else if (q.by === "text") {
             let m = pool.filter(el => matchText(normText(el.textContent), q.value, q.valueMode))
-            matched = m.filter(el => !m.some(other => other !== el && el.contains(other))) // smallest matching element
+            return m.filter(el => !m.some(other => other !== el && el.contains(other))) // smallest matching element
         } else if (q.by === "placeholder") {
-            matched = pool.filter(el => (el.tagName === "INPUT" || el.tagName === "TEXTAREA") && attrText(el, "placeholder") != null && matchText(attrText(el, "placeholder"), q.value, q.valueMode))
+            return pool.filter(el => (el.tagName === "INPUT" || el.tagName === "TEXTAREA") && attrText(el, "placeholder") != null && matchText(attrText(el, "placeholder"), q.value, q.valueMode))
         } else if (q.by === "alttext") {
-            matched = pool.filter(el => attrText(el, "alt") != null && matchText(attrText(el, "alt"), q.value, q.valueMode))
+            return pool.filter(el => attrText(el, "alt") != null && matchText(attrText(el, "alt"), q.value, q.valueMode))
         } else if (q.by === "title") {
-            matched = pool.filter(el => attrText(el, "title") != null && matchText(attrText(el, "title"), q.value, q.valueMode))
+            return pool.filter(el => attrText(el, "title") != null && matchText(attrText(el, "title"), q.value, q.valueMode))
         } else if (q.by === "testid") {
-            matched = pool.filter(el => el.getAttribute(q.attr || "data-testid") === q.value)
-        } else {
-            matched = []
+            return pool.filter(el => el.getAttribute(q.attr || "data-testid") === q.value)
         }
-        // 3. filters: visible-text and has-descendant, each optionally negated.
-        if (q.filters) for (let f of q.filters) {
-            if (f.kind === "containsText") {
-                matched = matched.filter(el => matchText(normText(el.textContent), f.value, f.mode) !== f.negate)
-            } else if (f.kind === "contains") {
-                let targets = selEach(f.selector)
-                matched = matched.filter(el => targets.some(t => t !== el && el.contains(t)) !== f.negate)
-            } else if (f.kind === "within") {
-                let scopes = selEach(f.selector)
-                matched = matched.filter(el => scopes.some(s => s !== el && s.contains(el)) !== f.negate)
-            }
+        return []
+    }
+    // locateFilter is step 3 of locate(): one visible-text, has-descendant, scope or positional
+    // filter, each optionally negated.
+    let locateFilter = (f, matched) => {
+        if (f.kind === "containsText") {
+            return matched.filter(el => matchText(normText(el.textContent), f.value, f.mode) !== f.negate)
+        } else if (f.kind === "contains") {
+            let targets = selEach(f.selector)
+            return matched.filter(el => targets.some(t => t !== el && el.contains(t)) !== f.negate)
+        } else if (f.kind === "within") {
+            let scopes = selEach(f.selector)
+            return matched.filter(el => scopes.some(s => s !== el && s.contains(el)) !== f.negate)
+        } else if (positional[f.kind]) {
+            let anchors = selEach(f.selector).map(a => [a, laidOutBox(a)]).filter(([a, box]) => box)
+            return matched.filter(el => {
+                let box = laidOutBox(el)
+                return box && anchors.some(([a, abox]) => a !== el && positional[f.kind](box, abox, f, el, a))
+            })
         }
+        return matched
+    }
+    let locateNth = (q, matched) => {
+        let i = q.nth === -1 ? matched.length - 1 : q.nth
+        return (i >= 0 && i < matched.length) ? [matched[i]] : []
+    }
+    let locate = (q) => {
+        // 1. candidate pool, piercing open shadow roots. `within` scopes to descendants of the
+        // scope element(s); an unresolved scope matches nothing.
+        let pool
+        if (q.within) {
+            let scopes = selEach(q.within)
+            if (!scopes.length) return []
+            pool = collectElements(document).filter(el => scopes.some(s => s !== el && s.contains(el)))
+        } else {
+            pool = collectElements(document)
+        }
+        // 2. base match set, 3. filters.
+        let matched = locateBase(q, pool)
+        if (q.filters) for (let f of q.filters) matched = locateFilter(f, matched)
         // 4. heading level, 5. ARIA states, 6. ordinal.
         if (q.level != null) matched = matched.filter(el => headingLevel(el) === q.level)
         if (q.states) for (let st of q.states) matched = matched.filter(el => stateHolds(el, st))
-        if (q.nthSet) {
-            let i = q.nth === -1 ? matched.length - 1 : q.nth
-            return (i >= 0 && i < matched.length) ? [matched[i]] : []
-        }
-        return matched
+        return q.nthSet ? locateNth(q, matched) : matched
     }
 
     let sel = (s) => {
@@ -202,6 +238,7 @@ if (!window["_biloba"]) {
                 let ns = locate(JSON.parse(s.slice(1)))
                 return ns.length ? ns[0] : null
             }
+            if (s.charAt(0) == "p") return pinnedNode(s.slice(1))
             let css = s.slice(1)
             if (css.includes(">>>")) {
                 let [root, last] = pierceRoot(css)
@@ -222,6 +259,11 @@ if (!window["_biloba"]) {
             if (s.charAt(0) == "a") {
                 return locate(JSON.parse(s.slice(1)))
             }
+            if (s.charAt(0) == "p") {
+                let n = pinnedNode(s.slice(1))
+                if (!n) throw new PinGone(s.slice(1))
+                return [n]
+            }
             let css = s.slice(1)
             if (css.includes(">>>")) {
                 let [root, last] = pierceRoot(css)
@@ -238,15 +280,49 @@ if (!window["_biloba"]) {
     let withFound = (result, found) => { result.found = found; return result }
     // ann renders a selector the way a failure message should show it: the "s"/"x"/"a" encoding prefix
     // dropped, introduced by a colon so it reads as a trailing clause.
-    let ann = (s) => (typeof s == "string" ? ": " + s.slice(1) : "")
+    let ann = (s) => (typeof s == "string" ? ": " + (s.charAt(0) == "p" ? pinDescription(s.slice(1)) : s.slice(1)) : "")
     // notFound is THE missing-element error - the one one() raises - factored out so the hand-rolled
     // multi-selector probes can raise the identical message.  label names WHICH selector went missing
     // when a handler takes more than one ("other "/"container "), so the failure points somewhere.
-    let notFound = (s, label) => rErr("could not find DOM element matching " + (label || "") + "selector" + ann(s))
+    let notFound = (s, label) => isPin(s) ? pinGone(s.slice(1)) : rErr("could not find DOM element matching " + (label || "") + "selector" + ann(s))
+    // strict is set for the duration of a call made through strictly() - a Strict() view's call.  one()
+    // and poll() then refuse a selector that matches more than one element, rather than taking the first.
+    let strict = false
+    // invoke is how Go calls a handler.  selEach throws PinGone for a detached pin wherever it meets
+    // one - as a locator's Within or Containing scope, deep inside a handler - and invoke turns it
+    // into the pin's error, so the handle fails at once rather than leaving a scope that matches nothing.
+    let pinGoneCaught = (e) => {
+        if (e instanceof PinGone) return pinGone(e.id)
+        throw e
+    }
+    b.invoke = (name, ...args) => {
+        try {
+            let result = b[name](...args)
+            return result instanceof Promise ? result.catch(pinGoneCaught) : result
+        } catch (e) {
+            return pinGoneCaught(e)
+        }
+    }
+    b.strictly = (name, ...args) => {
+        strict = true
+        try { return b.invoke(name, ...args) } finally { strict = false }
+    }
+    let strictMatchLimit = 10
+    let ambiguous = (s) => {
+        if (!strict || typeof s != "string") return null
+        let ns = selEach(s)
+        if (ns.length < 2) return null
+        let describe = (n) => { let d = focusDescription(n); return d.startsWith("css=") ? d : d + " (css=" + b.cssPath(n) + ")" }
+        let lines = ns.slice(0, strictMatchLimit).map((n, i) => "\n  " + (i + 1) + ". " + describe(n))
+        if (ns.length > strictMatchLimit) lines.push("\n  ...and " + (ns.length - strictMatchLimit) + " more")
+        return rErr("selector matched " + ns.length + " elements, but a strict view requires exactly one" + ann(s) + lines.join(""))
+    }
     let one = (...chain) => (s, ...args) => {
         let n = sel(s)
         let errAnnotation = ann(s)
         if (!n) return withFound(notFound(s), false)
+        let amb = ambiguous(s)
+        if (amb) return withFound(amb, true)
         for (let i = 0; i < chain.length - 1; i++) {
             let r = chain[i](n, ...args)
             if (!r.success) return withFound(!!r.error ? r : rErr(r.guard + errAnnotation), true)
@@ -275,7 +351,9 @@ if (!window["_biloba"]) {
     // ShouldNot(<matcher>) pass instantly against a selector that never matches - a vacuous pass.
     let poll = (...chain) => (s, ...args) => {
         let n = sel(s)
-        if (!n) return { success: false, found: false } // not found -> retry, NOT an error
+        if (!n) return isPin(s) ? withFound(notFound(s), false) : { success: false, found: false } // not found -> retry, NOT an error (a pin never comes back)
+        let amb = ambiguous(s)
+        if (amb) return withFound(amb, true)
         let errAnnotation = ann(s)
         for (let i = 0; i < chain.length - 1; i++) {
             let r = chain[i](n, ...args)
@@ -295,8 +373,13 @@ if (!window["_biloba"]) {
         n.dispatchEvent(new Event('input', { bubbles: true }))
         n.dispatchEvent(new Event('change', { bubbles: true }))
     }
-    b.exists = s => r(!!sel(s))
-    b.count = each(ns => rRes(ns.length))
+    // exists reports found only when the element is missing: a miss feeds the never-matched
+    // explanation on failure, while a hit left out can't make a passing ShouldNot(Exist()) read as
+    // a detached node.
+    b.exists = s => { let n = sel(s); return n ? r(true) : withFound(r(false), false) }
+    // a detached pin counts as 0 - Exist(), HasElement and Count still answer for the handle itself
+    let countEach = each(ns => rRes(ns.length))
+    b.count = (s, ...args) => isPin(s) ? rRes(pinnedNode(s.slice(1)) ? 1 : 0) : countEach(s, ...args)
     // distinctCountByAttr backs HaveDistinctCount: the number of DISTINCT values the named attribute
     // takes across all matches (elements lacking the attribute collapse into one `null` bucket).  Use
     // it to dedupe transient double-painted nodes keyed by a stable data-* attribute.
@@ -538,11 +621,41 @@ if (!window["_biloba"]) {
         if (!top) return r(false, "DOM element is not hittable at its center point")
         return r(composedContains(n, top), "DOM element is obscured by another element")
     })
+    // toViewport translates a point in view's own viewport into TOP-LEVEL viewport coordinates (where
+    // CDP mouse events and page.CaptureScreenshot live) by walking up the frameElement chain, and
+    // reports the top-level viewport's size and scroll alongside.  The walk stops at a cross-origin
+    // boundary, where frameElement is null: past that point only Biloba knows where the frame sits, so
+    // a Frame() view leaves the frame's origin on its window (_bilobaFrameOrigin) and without it the
+    // point is untranslatable.
+    let toViewport = (view, x, y) => {
+        try {
+            while (view && view.frameElement) {
+                let fe = view.frameElement, fr = fe.getBoundingClientRect()
+                x += fr.left + fe.clientLeft
+                y += fr.top + fe.clientTop
+                view = view.parent
+            }
+            if (view.parent === view) {
+                return { x: x, y: y, translatable: true, vw: view.innerWidth, vh: view.innerHeight, sx: view.scrollX, sy: view.scrollY }
+            }
+            let o = view._bilobaFrameOrigin
+            if (o) return { x: x + o.x, y: y + o.y, translatable: true, vw: o.vw, vh: o.vh, sx: o.sx, sy: o.sy }
+        } catch (e) { }
+        return { x: x, y: y, translatable: false, vw: window.innerWidth, vh: window.innerHeight, sx: window.scrollX, sy: window.scrollY }
+    }
+    // frameOrigin backs Frame(): where an iframe's content box starts, in top-level viewport
+    // coordinates, for the frame's document to translate its own points through.  null if the iframe
+    // itself cannot be translated.
+    b.frameOrigin = (n) => {
+        let rect = n.getBoundingClientRect()
+        let o = toViewport(n.ownerDocument.defaultView, rect.left + n.clientLeft, rect.top + n.clientTop)
+        return o.translatable ? { x: o.x, y: o.y, vw: o.vw, vh: o.vh, sx: o.sx, sy: o.sy } : null
+    }
     // measurePoint reports an element's centroid in TOP-LEVEL viewport coordinates (where CDP mouse
     // events live), plus whether that point is in the viewport, is hittable (the element/descendant
     // is topmost there), and whether the element is enabled.  It does NOT scroll - callers scroll
-    // first.  Coordinates from inside a same-origin iframe are translated by walking up the
-    // frameElement chain; the hit-test runs in the element's own document with its local coords.
+    // first.  Coordinates from inside an iframe are translated with toViewport; the hit-test runs in
+    // the element's own document with its local coords.
     let measurePoint = (n) => {
         let doc = n.ownerDocument, view = doc.defaultView
         let rect = n.getBoundingClientRect()
@@ -556,37 +669,20 @@ if (!window["_biloba"]) {
         let ly = inLocalViewport ? (vy0 + vy1) / 2 : rect.top + rect.height / 2
         let top = inLocalViewport ? hitTest(doc, lx, ly) : null
         let hittable = !!top && composedContains(n, top)
-        let cx = lx, cy = ly, translatable = inLocalViewport
-        try {
-            while (view && view.frameElement) {
-                let fe = view.frameElement, fr = fe.getBoundingClientRect()
-                cx += fr.left + fe.clientLeft
-                cy += fr.top + fe.clientTop
-                view = view.parent
-            }
-        } catch (e) { translatable = false } // cross-origin frame: cannot translate
-        let inViewport = translatable && cx >= 0 && cy >= 0 && cx <= window.innerWidth && cy <= window.innerHeight
-        return { x: cx, y: cy, inViewport: inViewport, hittable: hittable, enabled: !n.disabled }
+        let p = toViewport(view, lx, ly)
+        let inViewport = inLocalViewport && p.translatable && p.x >= 0 && p.y >= 0 && p.x <= p.vw && p.y <= p.vh
+        return { x: p.x, y: p.y, inViewport: inViewport, hittable: hittable, enabled: !n.disabled }
     }
     // docBox reports an element's rectangle in CSS pixels relative to the TOP-LEVEL document (so x/y
-    // already include page scroll).  Like measurePoint it walks the frameElement chain so an element
-    // inside a same-origin iframe is translated to top-level page coordinates; the final
-    // +scrollX/+scrollY converts the top-level viewport rect into document coordinates.  This is the
-    // coordinate space page.CaptureScreenshot clips in, which is why both the element-capture clip and
-    // the visual-regression mask rectangles are measured with it.
+    // already include page scroll).  Like measurePoint it translates an element inside an iframe with
+    // toViewport (falling back to local coordinates when it cannot); the final +scrollX/+scrollY
+    // converts the top-level viewport rect into document coordinates.  This is the coordinate space
+    // page.CaptureScreenshot clips in, which is why both the element-capture clip and the
+    // visual-regression mask rectangles are measured with it.
     let docBox = (n) => {
         let rect = n.getBoundingClientRect()
-        let left = rect.left, top = rect.top, view = n.ownerDocument.defaultView
-        try {
-            while (view && view.frameElement) {
-                let fe = view.frameElement, fr = fe.getBoundingClientRect()
-                left += fr.left + fe.clientLeft
-                top += fr.top + fe.clientTop
-                view = view.parent
-            }
-        } catch (e) { } // cross-origin frame: cannot translate; fall back to local coordinates
-        let top0 = view || window
-        return { x: left + top0.scrollX, y: top + top0.scrollY, width: rect.width, height: rect.height }
+        let p = toViewport(n.ownerDocument.defaultView, rect.left, rect.top)
+        return { x: p.x + p.sx, y: p.y + p.sy, width: rect.width, height: rect.height }
     }
     // describeNode names an element the way a failure message should: the tag, plus whichever of id
     // and class actually narrow it down.  Enough for a reader to find the node in their own markup.
@@ -637,17 +733,8 @@ if (!window["_biloba"]) {
     // false, which keeps the old always-expand behaviour for the case we cannot reason about.
     let fullyInViewport = (n) => {
         let rect = n.getBoundingClientRect()
-        let left = rect.left, top = rect.top, view = n.ownerDocument.defaultView
-        try {
-            while (view && view.frameElement) {
-                let fe = view.frameElement, fr = fe.getBoundingClientRect()
-                left += fr.left + fe.clientLeft
-                top += fr.top + fe.clientTop
-                view = view.parent
-            }
-            let top0 = view || window
-            return left >= 0 && top >= 0 && left + rect.width <= top0.innerWidth && top + rect.height <= top0.innerHeight
-        } catch (e) { return false } // cross-origin frame: cannot translate, so assume it is not
+        let p = toViewport(n.ownerDocument.defaultView, rect.left, rect.top)
+        return p.translatable && p.x >= 0 && p.y >= 0 && p.x + rect.width <= p.vw && p.y + rect.height <= p.vh
     }
     // boundingBox reports the first matching element's clip rectangle for page.CaptureScreenshot,
     // along with the clipping ancestor (if any) that would leave part of that rectangle unpainted and
@@ -714,10 +801,15 @@ if (!window["_biloba"]) {
     // viewport center, waits for its box to stop moving (two consecutive animation frames with the
     // same rect - bounded so a perpetually-animating element can't hang), then returns measurePoint.
     // Async (returns a Promise); invoked with awaitPromise on the Go side.
+    // nextFrame waits on a REAL animation frame even when InstallClock has frozen the page's own
+    // requestAnimationFrame - the stability waits below would otherwise never see a second frame.
+    let nextFrame = (cb) => ((window._bilobaClock && window._bilobaClock.native.requestAnimationFrame) || window.requestAnimationFrame)(cb)
     b.scrollToStablePoint = (s) => {
         let ann = (typeof s == "string" ? ": " + s.slice(1) : "")
         let n = sel(s)
-        if (!n) return Promise.resolve(rErr("could not find DOM element matching selector" + ann))
+        if (!n) return Promise.resolve(notFound(s))
+        let amb = ambiguous(s)
+        if (amb) return Promise.resolve(amb)
         if (!b.isVisible(n).success) return Promise.resolve(rErr("DOM element is not visible" + ann))
         n.scrollIntoView({ block: "center", inline: "center" })
         return new Promise(resolve => {
@@ -726,27 +818,19 @@ if (!window["_biloba"]) {
                 let bx = n.getBoundingClientRect()
                 let k = [bx.left, bx.top, bx.width, bx.height].join(",")
                 if (k === prev || frames++ > 30) resolve(rRes(measurePoint(n)))
-                else { prev = k; requestAnimationFrame(check) }
+                else { prev = k; nextFrame(check) }
             }
-            requestAnimationFrame(check)
+            nextFrame(check)
         })
     }
     // measureCorner reports an element's top-left corner in TOP-LEVEL viewport coordinates (where CDP
-    // mouse events live), plus whether the element is enabled.  Like measurePoint it walks the
-    // frameElement chain so a corner inside a same-origin iframe is translated to top-level coords.
-    // Callers add their own (offsetX, offsetY) and check the resulting point against the viewport.
+    // mouse events live), plus whether the element is enabled.  Like measurePoint it translates a
+    // corner inside an iframe with toViewport.  Callers add their own (offsetX, offsetY) and check the
+    // resulting point against the viewport.
     let measureCorner = (n) => {
         let rect = n.getBoundingClientRect()
-        let left = rect.left, top = rect.top, view = n.ownerDocument.defaultView, translatable = true
-        try {
-            while (view && view.frameElement) {
-                let fe = view.frameElement, fr = fe.getBoundingClientRect()
-                left += fr.left + fe.clientLeft
-                top += fr.top + fe.clientTop
-                view = view.parent
-            }
-        } catch (e) { translatable = false } // cross-origin frame: cannot translate
-        return { left: left, top: top, translatable: translatable, enabled: !n.disabled, innerWidth: window.innerWidth, innerHeight: window.innerHeight }
+        let p = toViewport(n.ownerDocument.defaultView, rect.left, rect.top)
+        return { left: p.x, top: p.y, translatable: p.translatable, enabled: !n.disabled, innerWidth: p.vw, innerHeight: p.vh }
     }
     // scrollToStableCorner backs ClickAt in realistic mode: it scrolls the element to the viewport
     // center, waits for its box to stop moving (same stability wait as scrollToStablePoint), then
@@ -754,7 +838,9 @@ if (!window["_biloba"]) {
     b.scrollToStableCorner = (s) => {
         let ann = (typeof s == "string" ? ": " + s.slice(1) : "")
         let n = sel(s)
-        if (!n) return Promise.resolve(rErr("could not find DOM element matching selector" + ann))
+        if (!n) return Promise.resolve(notFound(s))
+        let amb = ambiguous(s)
+        if (amb) return Promise.resolve(amb)
         if (!b.isVisible(n).success) return Promise.resolve(rErr("DOM element is not visible" + ann))
         n.scrollIntoView({ block: "center", inline: "center" })
         return new Promise(resolve => {
@@ -763,9 +849,9 @@ if (!window["_biloba"]) {
                 let bx = n.getBoundingClientRect()
                 let k = [bx.left, bx.top, bx.width, bx.height].join(",")
                 if (k === prev || frames++ > 30) resolve(rRes(measureCorner(n)))
-                else { prev = k; requestAnimationFrame(check) }
+                else { prev = k; nextFrame(check) }
             }
-            requestAnimationFrame(check)
+            nextFrame(check)
         })
     }
     // scrollToAndPointAt backs realistic ClickEach: scroll+measure the index-th match (no stability
@@ -779,19 +865,27 @@ if (!window["_biloba"]) {
     // inputKind classifies a form control so the realistic track can decide how to drive it.
     b.inputKind = one(n => {
         let t = n.type
+        if (editorOf(n)) return rRes("editor")
         if (t === "checkbox") return rRes("checkbox")
         if (t === "radio") return rRes("radio")
         if (t === "select-one" || t === "select-multiple") return rRes("select")
         return rRes("text")
     })
     b.blur = one(n => r(n.blur()))
-    b.node = (s) => sel(s)
+    b.node = (s) => {
+        let amb = ambiguous(s)
+        if (amb) throw new Error(amb.error)
+        return sel(s)
+    }
     b.clickEach = each(ns => {
         ns.forEach(n => b.click(n))
         return r()
     })
     let getValueImpl = (n) => {
-        if (n.type == "checkbox") {
+        let host = editorOf(n)
+        if (host) {
+            return rRes(editorText(host))
+        } else if (n.type == "checkbox") {
             return rRes(n.checked)
         } else if (n.type == "radio") {
             let selected = [...document.querySelectorAll(`input[type="radio"][name="${n.name}"]`)].find(o => o.checked)
@@ -809,6 +903,11 @@ if (!window["_biloba"]) {
     b.setValue = one(b.isVisible, b.isEnabled, (n, v) => {
         // a ValueLabel argument arrives as {__biloba_value_label: "..."}; labelOf unwraps it (or returns null)
         let labelOf = (val) => (val && typeof val == "object" && "__biloba_value_label" in val) ? val.__biloba_value_label : null
+        if (editorOf(n)) {
+            // an editor ignores a value set behind its back: Go enters the text through the editor
+            if (typeof v != "string") return rErr("Contenteditable editors only accept string values")
+            return rRes("editor")
+        }
         if (labelOf(v) !== null && n.type != "select-one") {
             return rErr(`ValueLabel is only supported for <select> elements`)
         }
@@ -1107,7 +1206,302 @@ if (!window["_biloba"]) {
         return rRes(n.compareDocumentPosition(o))
     }
 
-    b.outline = () => {
+    // ---- page visibility / window focus emulation ------------------------------------------------
+    // A headless tab is always visible, and Biloba's focus emulation (applyFocusEmulation) keeps it
+    // permanently focused, so neither state can change on its own.  emulatePageState fakes both on this
+    // document: visibilityState/hidden/hasFocus() report the emulated state, and the events a real tab
+    // switch fires are dispatched in the order Chrome fires them - focus leaves before the page hides
+    // and comes back after it shows.  The overrides are own properties on document, so deleting them
+    // is all it takes to hand the real values back.
+    let pageState = null
+    let dispatchFocusChange = (focused) => {
+        let active = document.activeElement
+        let onElement = active && active !== document.body && active !== document.documentElement
+        if (focused) window.dispatchEvent(new FocusEvent("focus"))
+        if (onElement) {
+            active.dispatchEvent(new FocusEvent(focused ? "focus" : "blur"))
+            active.dispatchEvent(new FocusEvent(focused ? "focusin" : "focusout", { bubbles: true, composed: true }))
+        }
+        if (!focused) window.dispatchEvent(new FocusEvent("blur"))
+    }
+    b.emulatePageState = (hidden, focused) => {
+        if (!pageState) {
+            pageState = { hidden: document.hidden, focused: document.hasFocus() }
+            Object.defineProperty(document, "visibilityState", { configurable: true, get: () => pageState.hidden ? "hidden" : "visible" })
+            Object.defineProperty(document, "hidden", { configurable: true, get: () => pageState.hidden })
+            Object.defineProperty(document, "hasFocus", { configurable: true, writable: true, value: () => pageState.focused })
+        }
+        if (hidden === null) hidden = pageState.hidden // a blur leaves visibility where it was
+        if (pageState.focused && !focused) {
+            pageState.focused = false
+            dispatchFocusChange(false)
+        }
+        if (pageState.hidden !== hidden) {
+            pageState.hidden = hidden
+            document.dispatchEvent(new Event("visibilitychange", { bubbles: true }))
+        }
+        if (!pageState.focused && focused) {
+            pageState.focused = true
+            dispatchFocusChange(true)
+        }
+        if (!hidden && focused) {
+            delete document.visibilityState
+            delete document.hidden
+            delete document.hasFocus
+            pageState = null
+        }
+        return rRes(true)
+    }
+
+    // cssPath names an element with a CSS selector that finds it again: its unique id if it has one,
+    // otherwise a tag (and :nth-of-type, when siblings share the tag) chain up to an ancestor that
+    // does, or to <body>
+    b.cssPath = (el) => {
+        let parts = []
+        while (el && el.nodeType === Node.ELEMENT_NODE) {
+            if (el.id && el.ownerDocument.querySelectorAll("#" + CSS.escape(el.id)).length === 1) {
+                parts.unshift("#" + CSS.escape(el.id))
+                break
+            }
+            let tag = el.tagName.toLowerCase()
+            let parent = el.parentElement
+            if (tag === "html" || tag === "body" || !parent) {
+                parts.unshift(tag)
+                break
+            }
+            let same = [...parent.children].filter(c => c.tagName === el.tagName)
+            parts.unshift(same.length > 1 ? `${tag}:nth-of-type(${same.indexOf(el) + 1})` : tag)
+            el = parent
+        }
+        return parts.join(" > ")
+    }
+    // a11yFacts gathers the markup facts the accessibility audit's DOM rules need (the rest come from
+    // the accessibility tree): the document's ids, the elements in scope that share one, and the
+    // elements in scope that carry a role or aria-* attributes
+    b.a11yFacts = (s) => {
+        let scope = s === undefined ? document.documentElement : sel(s)
+        if (!scope) return notFound(s)
+        let counts = {}
+        for (const el of document.querySelectorAll("[id]")) counts[el.id] = (counts[el.id] || 0) + 1
+        let duplicates = [], aria = []
+        for (const el of [scope, ...scope.querySelectorAll("*")]) {
+            if (el.id && counts[el.id] > 1) duplicates.push({ id: el.id, path: b.cssPath(el) })
+            let attrs = {}
+            for (const a of el.attributes) if (a.name === "role" || a.name.startsWith("aria-")) attrs[a.name] = a.value
+            if (Object.keys(attrs).length) aria.push({ path: b.cssPath(el), attrs })
+        }
+        return rRes({ ids: Object.keys(counts), duplicates, aria })
+    }
+
+    // tab-order walks: tabOrderStart parks focus on a throwaway tabindex=-1 sentinel just before the
+    // scope - sequential navigation starts from the focused element, so the first Tab lands on the
+    // scope's first tabbable element - and tabOrderStep reports where each Tab (sent from Go as a real
+    // key event) landed, and whether the walk is over.  The elements visited are kept so
+    // tabOrderMatches can compare them against selectors.
+    let tabWalk = null
+    let deepActive = () => {
+        let a = document.activeElement
+        while (a && a.shadowRoot && a.shadowRoot.activeElement) a = a.shadowRoot.activeElement
+        return a
+    }
+    let focusDescription = (el) => {
+        let role = roleOf(el), name = accessibleName(el)
+        if (role && name) return "role=" + role + " name=" + JSON.stringify(name)
+        return "css=" + b.cssPath(el)
+    }
+    b.tabOrderStart = (s) => {
+        let scope = s === undefined ? document.body : sel(s)
+        if (!scope) return notFound(s)
+        let sentinel = document.createElement("span")
+        sentinel.tabIndex = -1
+        if (scope === document.body) scope.prepend(sentinel)
+        else scope.before(sentinel)
+        tabWalk = { scope, sentinel, restore: deepActive(), seen: [] }
+        sentinel.focus({ preventScroll: true })
+        return r()
+    }
+    b.tabOrderStep = () => {
+        let el = deepActive()
+        if (tabWalk.sentinel && el === tabWalk.sentinel) return rRes({ stop: "nothing is focusable" })
+        tabWalk.sentinel?.remove()
+        if (!el || el === document.body || el === document.documentElement) return rRes({ stop: "focus left the page" })
+        if (tabWalk.scope !== document.body && !composedContains(tabWalk.scope, el)) return rRes({ stop: "focus left the scope for " + focusDescription(el) })
+        if (tabWalk.seen.includes(el)) return rRes({ stop: "focus cycled back to " + focusDescription(el) })
+        tabWalk.seen.push(el)
+        return rRes({ focused: focusDescription(el) })
+    }
+    b.tabOrderMatches = (...ss) => rRes(ss.map((s, i) => i < tabWalk.seen.length && selEach(s).includes(tabWalk.seen[i])))
+    b.tabOrderEnd = () => {
+        if (!tabWalk) return r()
+        tabWalk.sentinel?.remove()
+        let restore = tabWalk.restore
+        if (restore && restore !== document.body && restore.isConnected) restore.focus({ preventScroll: true })
+        else deepActive()?.blur()
+        tabWalk = null
+        return r()
+    }
+
+    // focus traps: focusTrapStart begins a tab-order walk from wherever focus already is inside the
+    // modal (no sentinel - a trap is walked from the inside), and focusHistory remembers the elements
+    // that recently got focus or a click, newest last, so focusTrapInvoker can name the element that
+    // opened the modal and focusTrapClosed can tell whether focus went back to it
+    let focusHistory = []
+    let remember = (el, focused) => {
+        if (!el || el === document.body || el === document.documentElement) return
+        focusHistory.push({ el, focused })
+        if (focusHistory.length > 50) focusHistory.shift()
+    }
+    document.addEventListener("focusin", e => remember(e.composedPath()[0], true), true)
+    document.addEventListener("click", e => {
+        let t = e.composedPath()[0]
+        remember(t.closest ? t.closest("a[href], button, input, select, textarea, summary, [tabindex]") || t : null, false)
+    }, true)
+    let trapInvoker = null
+    b.focusTrapStart = one(n => {
+        let el = deepActive()
+        if (!el || !composedContains(n, el)) return rRes({ outside: (el && el !== document.body) ? focusDescription(el) : "" })
+        tabWalk = { scope: n, sentinel: null, restore: el, seen: [el] }
+        return rRes({ start: focusDescription(el) })
+    })
+    b.focusTrapInvoker = one(n => {
+        trapInvoker = null
+        for (let i = focusHistory.length - 1; i >= 0; i--) {
+            let el = focusHistory[i].el
+            if (el.isConnected && !composedContains(n, el)) {
+                trapInvoker = el
+                break
+            }
+        }
+        if (!trapInvoker) return rRes({})
+        return rRes({ invoker: focusDescription(trapInvoker), hadFocus: focusHistory.some(h => h.el === trapInvoker && h.focused) })
+    })
+    b.focusTrapClosed = (s) => {
+        let el = deepActive()
+        let focused = (el && el !== document.body && el !== document.documentElement) ? focusDescription(el) : "the page"
+        return rRes({ closed: !b.isVisible(s).success, focused, returned: !!trapInvoker && el === trapInvoker })
+    }
+
+    // live-region announcements: a MutationObserver over the whole document records what a screen
+    // reader would announce - a live region's text when it changes (all of it for an atomic region,
+    // just what was added otherwise) and any role=alert element as it is inserted.  Regions clear
+    // themselves within a second or so, which is why this records rather than reads.  The observer
+    // runs on every page, so a mutation outside any live region is skipped before any text is read
+    // (innerText forces a layout), and only the latest maxAnnouncements are kept.
+    const LIVE_REGION = "[aria-live], [role=status], [role=alert], [role=log]"
+    let maxAnnouncements = 1000
+    let announcements = []
+    let politenessOf = (region) => {
+        let live = region.getAttribute("aria-live")
+        if (live) return live
+        return region.getAttribute("role") === "alert" ? "assertive" : "polite"
+    }
+    let isAtomic = (region) => {
+        let atomic = region.getAttribute("aria-atomic")
+        if (atomic) return atomic === "true"
+        let role = region.getAttribute("role")
+        return role === "status" || role === "alert"
+    }
+    let announce = (region, text) => {
+        text = normText(text)
+        if (text === "" || politenessOf(region) === "off" || !region.checkVisibility()) return
+        announcements.push({ text, politeness: politenessOf(region), region: b.cssPath(region), time: Date.now() })
+        if (announcements.length > maxAnnouncements) announcements.splice(0, announcements.length - maxAnnouncements)
+    }
+    new MutationObserver((records) => {
+        let changed = new Map()
+        for (const record of records) {
+            let target = record.target.nodeType === Node.ELEMENT_NODE ? record.target : record.target.parentElement
+            let region = target && target.closest(LIVE_REGION)
+            if (!region) {
+                for (const node of record.addedNodes) {
+                    if (node.nodeType === Node.ELEMENT_NODE && node.matches("[role=alert]")) announce(node, node.innerText)
+                }
+                continue
+            }
+            let added = changed.get(region) || []
+            changed.set(region, added)
+            if (record.type === "characterData") added.push(record.target.textContent)
+            for (const node of record.addedNodes) {
+                if (node.nodeType === Node.ELEMENT_NODE && node.matches("[role=alert]")) announce(node, node.innerText)
+                else added.push(node.nodeType === Node.ELEMENT_NODE ? node.innerText : node.textContent)
+            }
+        }
+        for (const [region, added] of changed) {
+            if (added.length) announce(region, isAtomic(region) ? region.innerText : added.join(" "))
+        }
+    }).observe(document, { childList: true, characterData: true, subtree: true })
+    b.announcements = () => rRes(announcements)
+
+    // contrastInfo gathers what the contrast check needs about one element's text: its colour, the
+    // background-color layers behind it (nearest first, down to the first opaque one - or the canvas),
+    // the opacity of every element down to and including the one that owns that layer, and its font
+    // size and weight.  Colours come back as sRGB
+    // [r, g, b, a] by painting them, so oklch(), color-mix() and friends resolve like anything else.
+    // An ancestor with a background-image ends the walk: the Go side samples a screenshot instead.
+    let colorCanvas = null
+    let rgbaOf = (color) => {
+        if (!colorCanvas) {
+            colorCanvas = document.createElement("canvas")
+            colorCanvas.width = colorCanvas.height = 1
+        }
+        let ctx = colorCanvas.getContext("2d", { willReadFrequently: true })
+        ctx.clearRect(0, 0, 1, 1)
+        ctx.fillStyle = color
+        ctx.fillRect(0, 0, 1, 1)
+        let d = ctx.getImageData(0, 0, 1, 1).data
+        return [d[0], d[1], d[2], d[3] / 255]
+    }
+    let canvasColor = () => {
+        let scheme = getComputedStyle(document.documentElement).colorScheme || ""
+        let dark = scheme.includes("dark") && (!scheme.includes("light") || matchMedia("(prefers-color-scheme: dark)").matches)
+        return dark ? [18, 18, 18, 1] : [255, 255, 255, 1]
+    }
+    let ownText = (n) => {
+        if (n.tagName === "INPUT" || n.tagName === "TEXTAREA") return normText(n.value)
+        return normText([...n.childNodes].filter(c => c.nodeType === Node.TEXT_NODE).map(c => c.textContent).join(" "))
+    }
+    let contrastInfo = (n) => {
+        let style = getComputedStyle(n)
+        let layers = [], opacity = 1, image = false
+        for (let el = n; el; el = el.parentElement) {
+            let s = getComputedStyle(el)
+            opacity *= parseFloat(s.opacity)
+            if (s.backgroundImage !== "none") {
+                image = true
+                break
+            }
+            let bg = rgbaOf(s.backgroundColor)
+            if (bg[3] > 0) layers.push(bg)
+            if (bg[3] >= 1) break
+        }
+        if (!image && (layers.length === 0 || layers[layers.length - 1][3] < 1)) layers.push(canvasColor())
+        return {
+            path: b.cssPath(n), text: ownText(n).slice(0, 40), fg: rgbaOf(style.color), opacity, layers, image,
+            fontSize: parseFloat(style.fontSize), fontWeight: parseInt(style.fontWeight, 10) || 400,
+        }
+    }
+    b.contrastOf = one(n => rRes(contrastInfo(n)))
+    // contrastReport covers every rendered element in scope that has text of its own
+    b.contrastReport = one(n => rRes([n, ...n.querySelectorAll("*")].filter(el => el.checkVisibility({ visibilityProperty: true }) && ownText(el) !== "").map(contrastInfo)))
+    // hideTextForSampling makes the text under an element transparent so a screenshot of it shows only
+    // what is behind the text; showSampledText puts it back
+    b.hideTextForSampling = one(n => {
+        let style = document.createElement("style")
+        style.id = "__biloba_contrast_sample"
+        style.textContent = "[data-biloba-contrast-sample], [data-biloba-contrast-sample] * { color: transparent !important; -webkit-text-fill-color: transparent !important; text-shadow: none !important; text-decoration-color: transparent !important; caret-color: transparent !important }"
+        document.documentElement.appendChild(style)
+        n.setAttribute("data-biloba-contrast-sample", "")
+        return r()
+    })
+    b.showSampledText = () => {
+        document.getElementById("__biloba_contrast_sample")?.remove()
+        for (const el of document.querySelectorAll("[data-biloba-contrast-sample]")) el.removeAttribute("data-biloba-contrast-sample")
+        return r()
+    }
+
+    // outline renders the document body - or, given a selector, the element matching it and its
+    // subtree - as indented text
+    b.outline = (s) => {
         const PRUNE_TAGS = new Set(["script", "style", "svg"])
         const SELF_CLOSING = new Set(["area","base","br","col","embed","hr","img","input","link","meta","param","source","track","wbr"])
         const serializeAttrs = (el) => {
@@ -1130,10 +1524,759 @@ if (!window["_biloba"]) {
             for (const child of node.childNodes) children += walk(child, depth + 1)
             return open + "\n" + children + indent + "</" + tag + ">\n"
         }
+        if (s !== undefined) {
+            const n = sel(s)
+            if (!n) return notFound(s)
+            return rRes(walk(n, 0))
+        }
         let out = ""
         for (const child of document.body.childNodes) out += walk(child, 0)
         return rRes(out)
     }
 
+    // ---- ExplainSelector ----------------------------------------------------------------------
+    // editDistance is the Levenshtein distance, capped in length so a paragraph of text stays cheap.
+    let editDistance = (x, y) => {
+        x = x.slice(0, 100), y = y.slice(0, 100)
+        let prev = Array.from({ length: y.length + 1 }, (_, j) => j)
+        for (let i = 1; i <= x.length; i++) {
+            let cur = [i]
+            for (let j = 1; j <= y.length; j++) {
+                cur[j] = Math.min(prev[j] + 1, cur[j - 1] + 1, prev[j - 1] + (x[i - 1] === y[j - 1] ? 0 : 1))
+            }
+            prev = cur
+        }
+        return prev[y.length]
+    }
+    // nearestTo ranks candidates by how close they come to target - those that contain target, or are
+    // contained by it, first (by the characters they add or drop), then the rest by edit distance - and
+    // keeps the top three.
+    let nearestTo = (target, candidates) => {
+        let t = target.toLowerCase()
+        let scored = [...new Set(candidates.filter(c => c && c.length <= 200))].map(c => {
+            let lc = c.toLowerCase(), contains = lc.includes(t) || t.includes(lc)
+            return [c, contains ? 0 : 1, contains ? Math.abs(lc.length - t.length) : editDistance(t, lc)]
+        })
+        scored.sort((a, b) => a[1] - b[1] || a[2] - b[2])
+        return scored.slice(0, 3).map(([c]) => c)
+    }
+    // what to suggest when a value-matching locator matches nothing: the values it could have matched
+    let nearestCandidates = {
+        label: ["labels", (q, pool) => pool.filter(el => el.matches("input,select,textarea,button,[contenteditable],[role]")).map(accessibleName)],
+        text: ["texts", (q, pool) => pool.filter(el => el.childElementCount === 0).map(el => normText(el.textContent))],
+        placeholder: ["placeholders", (q, pool) => pool.map(el => attrText(el, "placeholder"))],
+        alttext: ["alt texts", (q, pool) => pool.map(el => attrText(el, "alt"))],
+        title: ["titles", (q, pool) => pool.map(el => attrText(el, "title"))],
+        testid: ["test ids", (q, pool) => pool.map(el => el.getAttribute(q.attr || "data-testid"))],
+    }
+    // explainLocate replays locate() a stage at a time, counting what is left after each: the base
+    // match set (for a named role, the role and then the name), the Within scope, each filter, the
+    // heading level, each state, and the ordinal.  Go labels the stages from the Locator, in this order.
+    // Scoping is applied after the base match rather than before, which leaves every count the same.
+    let explainLocate = (q) => {
+        let steps = []
+        let pool = collectElements(document)
+        let step = (matched, extra) => { steps.push(Object.assign({ count: matched.length }, extra)); return matched }
+        let matched
+        if (q.by === "role" && q.nameSet) {
+            let roled = step(pool.filter(el => roleOf(el) === q.role))
+            matched = step(locateBase(q, pool))
+            if (!matched.length) steps[steps.length - 1].nearest = nearestTo(q.name, roled.map(accessibleName)), steps[steps.length - 1].nearestOf = "names"
+        } else {
+            matched = step(locateBase(Object.assign({}, q, { within: null }), pool))
+            let candidates = nearestCandidates[q.by]
+            if (!matched.length && candidates) steps[steps.length - 1].nearest = nearestTo(q.value, candidates[1](q, pool)), steps[steps.length - 1].nearestOf = candidates[0]
+        }
+        if (q.within) {
+            let scopes = selEach(q.within)
+            matched = step(matched.filter(el => scopes.some(s => s !== el && s.contains(el))), { anchors: scopes.length })
+        }
+        if (q.filters) for (let f of q.filters) {
+            let extra = f.selector ? { anchors: selEach(f.selector).length } : {}
+            matched = step(locateFilter(f, matched), extra)
+        }
+        if (q.level != null) matched = step(matched.filter(el => headingLevel(el) === q.level))
+        if (q.states) for (let st of q.states) matched = step(matched.filter(el => stateHolds(el, st)))
+        if (q.nthSet) step(locateNth(q, matched))
+        return steps
+    }
+    // prefixCuts returns the prefixes of a CSS selector or an XPath at which it can be cut and still
+    // parse - before each top-level "/" step or "[" predicate of an XPath, before each top-level
+    // combinator or simple selector of CSS - ending with the whole thing.  A top-level union ("," or
+    // "|") isn't cut at all.
+    let prefixCuts = (text, xpath) => {
+        let cuts = [], depth = 0, quote = null
+        for (let i = 0; i < text.length; i++) {
+            let c = text[i]
+            if (quote) { if (c === quote) quote = null; continue }
+            if (c === "'" || c === '"') { quote = c; continue }
+            if (c === "[" || c === "(") { if (depth === 0 && i > 0 && c === "[") cuts.push(i); depth++; continue }
+            if (c === "]" || c === ")") { depth--; continue }
+            if (depth > 0) continue
+            if (c === (xpath ? "|" : ",")) return [text]
+            if (xpath) {
+                if (c === "/" && i > 0 && text[i - 1] !== "/") cuts.push(i)
+            } else if (".#:".includes(c) && i > 0 && text[i - 1] !== ":" && !" >+~".includes(text[i - 1])) {
+                cuts.push(i)
+            } else if (" >+~".includes(c) && i > 0 && !" >+~".includes(text[i - 1])) {
+                cuts.push(i)
+            }
+        }
+        let prefixes = cuts.map(i => text.slice(0, i).trim()).filter(p => p && !/[\/>+~]$/.test(p))
+        prefixes.push(text.trim())
+        return [...new Set(prefixes)]
+    }
+    let explainCSS = (css) => {
+        let prefixes = css.includes(">>>")
+            ? css.split(">>>").map((_, i, segs) => segs.slice(0, i + 1).join(">>>").trim())
+            : prefixCuts(css, false)
+        let steps = []
+        for (let p of prefixes) {
+            try { steps.push({ label: p, count: selEach("s" + p).length }) } catch (e) { }
+        }
+        return steps
+    }
+    let explainXPath = (xpath) => {
+        let steps = []
+        for (let p of prefixCuts(xpath, true)) {
+            try { steps.push({ label: p, count: document.evaluate(p, document, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null).snapshotLength }) } catch (e) { }
+        }
+        return steps
+    }
+    b.explainSelector = (s) => {
+        if (s.charAt(0) == "a") return rRes(explainLocate(JSON.parse(s.slice(1))))
+        if (s.charAt(0) == "x") return rRes(explainXPath(s.slice(1)))
+        return rRes(explainCSS(s.slice(1)))
+    }
+
+    // ---- FillForm / GetFormValues ----------------------------------------------------------------
+    // formControls are the value-carrying controls in a form (or any container): its inputs, selects
+    // and textareas, less the buttons.
+    let formControls = (form) => collectElements(form).filter(el => el.matches("input,select,textarea") && !["button", "submit", "reset", "image"].includes(el.type))
+    // radioGroupLabel names the group a radio belongs to: the legend of its fieldset, or the
+    // accessible name of its role=radiogroup container
+    let radioGroupLabel = (el) => {
+        let group = el.closest("fieldset,[role=radiogroup]")
+        if (!group) return ""
+        if (group.tagName !== "FIELDSET") return accessibleName(group)
+        let legend = [...group.children].find(c => c.tagName === "LEGEND")
+        return legend ? normText(legend.textContent) : ""
+    }
+    // formField resolves one FillForm key the way ByLabel would - a control whose accessible name is
+    // key - and then, failing that, as the label of a radio group, and then as a control's name
+    // attribute.  It returns the radios of a group, or the one control.
+    let formField = (controls, key) => {
+        let found = controls.filter(el => el.type !== "radio" && accessibleName(el) === key)
+        if (!found.length) {
+            let radios = controls.filter(el => el.type === "radio" && radioGroupLabel(el) === key)
+            if (!radios.length) radios = controls.filter(el => el.type === "radio" && el.name === key)
+            if (radios.length) return { radios: radios }
+            found = controls.filter(el => accessibleName(el) === key)
+        }
+        if (!found.length) found = controls.filter(el => el.name === key)
+        if (found.length > 1) return { error: `${found.length} fields are labelled "${key}"` }
+        if (!found.length) {
+            let labels = [...new Set(controls.map(el => el.type === "radio" ? (radioGroupLabel(el) || el.name) : (accessibleName(el) || el.name)).filter(Boolean))]
+            return { error: `there is no field labelled "${key}" - the fields are: ${labels.map(l => JSON.stringify(l)).join(", ")}` }
+        }
+        return { control: found[0] }
+    }
+    // optionValue turns an option's visible label into a ValueLabel, so a <select> is filled by what
+    // the user sees; anything else is left for setValue to match against option values
+    let optionValue = (select, v) => (typeof v == "string" && [...select.options].some(o => o.text == v)) ? { __biloba_value_label: v } : v
+    // formTarget resolves one FillForm key to the control to set and the value to SetValue on it, or to
+    // the problem with the key
+    let formTarget = (form, key, v) => {
+        let field = formField(formControls(form), key)
+        if (field.error) return { problem: field.error }
+        let el = field.control
+        if (field.radios) {
+            el = field.radios.find(r => accessibleName(r) === v) || field.radios.find(r => r.value === v)
+            if (!el) return { problem: `"${key}" has no option ${JSON.stringify(v)} - the options are: ${field.radios.map(r => JSON.stringify(accessibleName(r) || r.value)).join(", ")}` }
+            v = el.value
+        } else if (el.type === "radio") {
+            if (v !== true) return { problem: `"${key}" is a radio button: it can only be set to true` }
+            v = el.value
+        } else if (el.type === "select-one") {
+            v = optionValue(el, v)
+        } else if (el.type === "select-multiple" && Array.isArray(v)) {
+            v = v.map(x => optionValue(el, x))
+        } else if (typeof v == "number") {
+            v = String(v)
+        }
+        return { el: el, value: v }
+    }
+    // planFormFill resolves every field FillForm was handed before anything is set, and returns the
+    // keys in the document order of their controls - or the first problem with a key
+    b.planFormFill = one((form, fields) => {
+        let plan = []
+        for (let key of Object.keys(fields)) {
+            let target = formTarget(form, key, fields[key])
+            if (target.problem) return rRes({ problem: target.problem })
+            plan.push({ el: target.el, key: key })
+        }
+        plan.sort((x, y) => x.el === y.el ? 0 : (x.el.compareDocumentPosition(y.el) & Node.DOCUMENT_POSITION_FOLLOWING ? -1 : 1))
+        return rRes({ keys: plan.map(p => p.key) })
+    })
+    // pinFormField resolves one key again, just before FillForm sets it - setting an earlier field may
+    // have re-rendered the form or added fields to it - and pins the control, so it is set wherever it
+    // lives, shadow roots included.  It returns the value to SetValue on the pin.
+    b.pinFormField = one((form, key, v, id, description) => {
+        let target = formTarget(form, key, v)
+        if (target.problem) return rErr(target.problem)
+        pinNode(id, target.el, description, null)
+        return rRes(target.value)
+    })
+    // getFormValues keys each control's value (as GetValue reads it) by its accessible name and by its
+    // name attribute; a radio group is keyed by its group label and name
+    b.getFormValues = poll((form) => {
+        let values = {}
+        for (let el of formControls(form)) {
+            let value = getValueImpl(el).result
+            let keys = el.type === "radio" ? [radioGroupLabel(el), el.name] : [accessibleName(el), el.name]
+            for (let k of keys) if (k) values[k] = value
+        }
+        return rRes(values)
+    })
+
+    // ---- SelectOption ---------------------------------------------------------------------------
+    // optionWidget classifies what SelectOption was pointed at: a native <select>, or an ARIA listbox
+    // or combobox
+    let optionWidget = (el) => el.tagName === "SELECT" ? "select" : ({ listbox: "listbox", combobox: "combobox" })[roleOf(el)]
+    // popupOf finds the listbox a widget's options live in: the listbox itself, the one a combobox
+    // names in aria-controls (or aria-owns), one inside the combobox, or else the only listbox showing
+    let popupOf = (el) => {
+        if (roleOf(el) === "listbox") return el
+        let isListbox = (n) => n && roleOf(n) === "listbox"
+        let root = el.getRootNode().getElementById ? el.getRootNode() : document
+        for (let attr of ["aria-controls", "aria-owns"]) {
+            for (let id of (el.getAttribute(attr) || "").split(/\s+/).filter(Boolean)) {
+                let n = root.getElementById(id)
+                if (isListbox(n)) return n
+                let inner = n && collectElements(n).find(isListbox)
+                if (inner) return inner
+            }
+        }
+        let inner = collectElements(el).find(n => n !== el && isListbox(n))
+        if (inner) return inner
+        let showing = collectElements(document).filter(n => isListbox(n) && b.isVisible(n).success)
+        return showing.length === 1 ? showing[0] : null
+    }
+    let optionLabel = (o) => o.tagName === "OPTION" ? normText(o.text) : accessibleName(o)
+    let ariaOptions = (listbox) => collectElements(listbox).filter(n => roleOf(n) === "option")
+    let pickOption = (options, spec) => "index" in spec ? options[spec.index] : options.find(o => optionLabel(o) === spec.label)
+    let describeOption = (spec) => "index" in spec ? `at index ${spec.index}` : `labelled ${JSON.stringify(spec.label)}`
+    b.optionWidget = one(n => {
+        let kind = optionWidget(n)
+        return kind ? rRes(kind) : rErr("element is not a <select>, or an element with role listbox or combobox")
+    })
+    // selectOptions selects the options specs describe in a native <select> - in a <select multiple>,
+    // exactly those - and fires the events setValue does
+    b.selectOptions = one(b.isVisible, b.isEnabled, (n, specs) => {
+        let options = [...n.options], chosen = []
+        for (let spec of specs) {
+            let o = pickOption(options, spec)
+            if (!o) return rErr(`Select input does not have an option ${describeOption(spec)} - the options are: ${options.map(o => JSON.stringify(optionLabel(o))).join(", ")}`)
+            if (o.disabled) return rErr(`The ${JSON.stringify(optionLabel(o))} option is not enabled`)
+            chosen.push(o)
+        }
+        if (!n.multiple && chosen.length !== 1) return rErr(`A single-select input takes one option - got ${chosen.length}`)
+        n.focus()
+        if (n.multiple) options.forEach(o => o.selected = chosen.includes(o))
+        else n.selectedIndex = options.indexOf(chosen[0])
+        n.dispatchEvent(new Event('input', { bubbles: true }))
+        n.dispatchEvent(new Event('change', { bubbles: true }))
+        n.blur()
+        return r()
+    })
+    let isMultiselectable = (list) => list.getAttribute("aria-multiselectable") === "true"
+    let isSelectedOption = (o) => o.getAttribute("aria-selected") === "true"
+    // findOption locates an option of an ARIA listbox or combobox, reporting whether its popup is open
+    // and whether it takes several options and, if the option is there, a CSS path to click it by - or,
+    // when it is already selected in a multi-select listbox (where a click would deselect it), that
+    // there is nothing to click
+    b.findOption = one((n, spec) => {
+        let list = popupOf(n)
+        if (!list || !b.isVisible(list).success) return rRes({ open: false })
+        let options = ariaOptions(list), o = pickOption(options, spec), multiple = isMultiselectable(list)
+        let selected = !!o && multiple && isSelectedOption(o)
+        return rRes({ open: true, multiple: multiple, path: o ? b.cssPath(o) : "", selected: selected, options: options.map(optionLabel) })
+    })
+    // unnamedSelectedOption finds an option of a multi-select listbox that is selected but not among
+    // specs, returning a CSS path to click it by - so the options SelectOption names become the whole
+    // selection, as in a <select multiple> - or "" when there is none
+    b.unnamedSelectedOption = one((n, specs) => {
+        let list = popupOf(n)
+        if (!list || !b.isVisible(list).success || !isMultiselectable(list)) return rRes("")
+        let options = ariaOptions(list), named = specs.map(spec => pickOption(options, spec))
+        let o = options.find(o => isSelectedOption(o) && !named.includes(o))
+        return rRes(o ? b.cssPath(o) : "")
+    })
+    // selectedOptionLabels reads the labels of a widget's selected options: a <select>'s selected
+    // options, a listbox's aria-selected ones, and a combobox's aria-selected options - or, with its
+    // popup gone, the value or text it shows
+    b.selectedOptionLabels = one(n => {
+        let kind = optionWidget(n)
+        if (kind === "select") return rRes([...n.selectedOptions].map(optionLabel))
+        if (!kind) return rErr("element is not a <select>, or an element with role listbox or combobox")
+        let list = popupOf(n)
+        let selected = list ? ariaOptions(list).filter(o => o.getAttribute("aria-selected") === "true").map(optionLabel) : []
+        if (selected.length || kind === "listbox") return rRes(selected)
+        let shown = normText(n.tagName === "INPUT" ? n.value : n.textContent)
+        return rRes(shown ? [shown] : [])
+    })
+
+    // ---- GetTable -------------------------------------------------------------------------------
+    // tableOf reads an HTML <table>, or an ARIA grid/table/treegrid, into headers and rows of cell
+    // text, with colspan/rowspan (aria-colspan/aria-rowspan) resolved by repeating a spanning cell's
+    // text in every slot it covers.  Rows that aren't rendered are skipped.  The header rows are the
+    // leading rows made entirely of header cells (<th> or columnheader); several header rows combine
+    // into one header per column, "Q1 / Jan".
+    let ariaTableRoles = ["grid", "table", "treegrid"]
+    let tableOf = (el) => {
+        let rows, cellsOf, span
+        if (el.tagName === "TABLE") {
+            rows = [...el.rows]
+            cellsOf = (row) => [...row.cells]
+            span = (cell, attr) => cell[attr === "col" ? "colSpan" : "rowSpan"]
+        } else if (ariaTableRoles.includes(roleOf(el))) {
+            let owner = (n) => n.parentElement.closest(ariaTableRoles.map(r => `[role=${r}]`).join(",") + ",table")
+            rows = collectElements(el).filter(n => n !== el && roleOf(n) === "row" && owner(n) === el)
+            cellsOf = (row) => collectElements(row).filter(n => n !== row && ["gridcell", "cell", "columnheader", "rowheader"].includes(roleOf(n)) && n.parentElement.closest("[role=row],tr") === row)
+            span = (cell, attr) => parseInt(cell.getAttribute("aria-" + attr + "span")) || 1
+        } else {
+            return rErr("element is not a <table>, or an element with role grid, table or treegrid")
+        }
+        rows = rows.filter(row => row.getClientRects().length > 0)
+        let isHeader = (cell) => cell.tagName === "TH" || roleOf(cell) === "columnheader"
+        let grid = [], headerRows = 0, counting = true
+        rows.forEach((row, r) => {
+            let cells = cellsOf(row)
+            if (counting && cells.length && cells.every(isHeader)) headerRows++
+            else counting = false
+            grid[r] = grid[r] || []
+            let c = 0
+            for (let cell of cells) {
+                while (grid[r][c] !== undefined) c++
+                let text = normText(cell.innerText), cs = Math.max(1, span(cell, "col")), rs = span(cell, "row")
+                if (rs <= 0) rs = rows.length - r
+                for (let i = 0; i < rs && r + i < rows.length; i++) {
+                    grid[r + i] = grid[r + i] || []
+                    for (let j = 0; j < cs; j++) grid[r + i][c + j] = text
+                }
+                c += cs
+            }
+        })
+        let width = Math.max(0, ...grid.map(row => row.length))
+        grid = grid.map(row => Array.from({ length: width }, (_, i) => row[i] === undefined ? "" : row[i]))
+        let headers = null
+        if (headerRows > 0) {
+            headers = Array.from({ length: width }, (_, i) => {
+                let parts = []
+                for (let row of grid.slice(0, headerRows)) if (row[i] && parts[parts.length - 1] !== row[i]) parts.push(row[i])
+                return parts.join(" / ")
+            })
+        }
+        return rRes({ headers: headers, rows: grid.slice(headerRows) })
+    }
+    b.getTable = one(tableOf)
+
+    // ---- SuggestLocators ------------------------------------------------------------------------
+    // suggestionLimit keeps names and texts that read as a paragraph out of the suggestions - a locator
+    // that long is brittle in its own way.
+    const suggestionLimit = 80
+    let scopeRoles = ["dialog", "alertdialog", "form", "region", "navigation", "main", "banner", "contentinfo", "complementary", "search", "group", "tabpanel", "table", "list", "listitem", "row", "article"]
+    // leafQueries are the Locator leaves that could name el, most robust first: its test id, its role
+    // and accessible name, the label of a form control, its text, and its placeholder, alt text and
+    // title.
+    let leafQueries = (el, attr) => {
+        let qs = [], fits = (v) => v && v.length <= suggestionLimit
+        let testID = el.getAttribute(attr)
+        if (testID) qs.push({ by: "testid", value: testID, valueMode: "exact", attr: attr })
+        let role = roleOf(el), name = accessibleName(el)
+        if (role && fits(name)) qs.push({ by: "role", role: role, nameSet: true, name: name, nameMode: "exact" })
+        if (el.matches("input,select,textarea") && fits(name)) qs.push({ by: "label", value: name, valueMode: "exact" })
+        let text = normText(el.textContent)
+        if (fits(text) && !el.matches("input,select,textarea")) qs.push({ by: "text", value: text, valueMode: "exact" })
+        for (let [by, a] of [["placeholder", "placeholder"], ["alttext", "alt"], ["title", "title"]]) {
+            let v = attrText(el, a)
+            if (fits(v)) qs.push({ by: by, value: v, valueMode: "exact" })
+        }
+        if (role && !name) qs.push({ by: "role", role: role })
+        return qs
+    }
+    let findsOnly = (q, el) => { let ns = locate(q); return ns.length === 1 && ns[0] === el }
+    // suggestFor ranks the Locators that find el and nothing else.  A leaf that is ambiguous on its own
+    // is scoped Within the closest ancestor that has a unique locator of its own (a test id, or a named
+    // dialog, form, region...); an element no locator pins down gets its CSS path as a last resort.
+    let suggestFor = (el, attr) => {
+        let leaves = leafQueries(el, attr), out = leaves.filter(q => findsOnly(q, el))
+        if (!out.length) {
+            let scope = null
+            for (let a = el.parentElement; a && a !== document.body && !scope; a = a.parentElement) {
+                let qs = leafQueries(a, attr).filter(q => q.by === "testid" || (q.by === "role" && q.nameSet && scopeRoles.includes(q.role)))
+                scope = qs.find(q => findsOnly(q, a)) || null
+            }
+            if (scope) {
+                let within = "a" + JSON.stringify(scope)
+                out = leaves.map(q => Object.assign({}, q, { within: within })).filter(q => findsOnly(q, el)).map(q => Object.assign(q, { within: scope }))
+            }
+        }
+        if (!out.length) out.push({ by: "css", value: b.cssPath(el) })
+        return out
+    }
+    b.suggestLocators = one((n, attr) => rRes(suggestFor(n, attr)))
+    // the locator picker: an Alt-click anywhere on the page is swallowed and remembered, and takePicked
+    // hands back the suggestions for the element it landed on (installing the listener, on this
+    // document, the first time it is asked)
+    let picked = null, pickerInstalled = false
+    b.takePicked = (attr) => {
+        if (!pickerInstalled) {
+            pickerInstalled = true
+            window.addEventListener("click", (e) => {
+                if (!e.altKey) return
+                e.preventDefault()
+                e.stopPropagation()
+                picked = e.target
+                let outline = picked.style.outline
+                picked.style.outline = "2px solid magenta"
+                setTimeout(() => { e.target.style.outline = outline }, 600)
+            }, true)
+        }
+        let n = picked
+        picked = null
+        return rRes(n && n.isConnected ? suggestFor(n, attr) : null)
+    }
+
+    // ---- Rich-text editors ----------------------------------------------------------------------
+    // editorOf finds the contenteditable host n stands for: the outermost editable ancestor of an
+    // editable n, or else the first editable host inside n - so the wrapper an editor library mounts
+    // into works as well as the editable itself.  Form controls are never editors.
+    let editorOf = (n) => {
+        if (n.matches("input,select,textarea,button")) return null
+        if (!n.isContentEditable) n = n.querySelector('[contenteditable]:not([contenteditable="false"])')
+        if (!n || !n.isContentEditable) return null
+        while (n.parentElement && n.parentElement.isContentEditable) n = n.parentElement
+        return n
+    }
+    let isBlock = (el) => {
+        let d = getComputedStyle(el).display
+        return d !== "contents" && !d.startsWith("inline")
+    }
+    // a whitespace-only text node beside block elements is markup formatting, not text the user sees
+    let isFormatting = (t) => !/\S/.test(t.data) && [...t.parentNode.children].some(isBlock)
+    // editorText reads an editor the way its user reads it: one line per block, <br> as a line break
+    // (less the placeholder <br> editors put at the end of a block), non-breaking spaces as spaces
+    let editorText = (host) => {
+        let lines = [], line = null
+        let flush = () => {
+            if (line !== null) lines.push(line.replace(/\n$/, ""))
+            line = null
+        }
+        let walk = (n) => {
+            for (let c of n.childNodes) {
+                if (c.nodeType === Node.TEXT_NODE) {
+                    if (!isFormatting(c)) line = (line || "") + c.data.replace(/\u00a0/g, " ")
+                } else if (c.nodeType === Node.ELEMENT_NODE && getComputedStyle(c).display !== "none") {
+                    if (c.tagName === "BR") line = (line || "") + "\n"
+                    else if (isBlock(c)) { flush(); line = ""; walk(c); flush() }
+                    else walk(c)
+                }
+            }
+        }
+        walk(host)
+        flush()
+        return lines.join("\n")
+    }
+    // caretRange is the range a caret spec - {at: "start" | "end" | "all" | "after", text} - names in
+    // host, or null when the text to place it after isn't there
+    let caretRange = (host, caret) => {
+        let texts = []
+        let walker = document.createTreeWalker(host, NodeFilter.SHOW_TEXT)
+        while (walker.nextNode()) if (!isFormatting(walker.currentNode)) texts.push(walker.currentNode)
+        let range = document.createRange()
+        if (caret.at === "all") {
+            range.selectNodeContents(host)
+            return range
+        }
+        if (caret.at === "after") {
+            let i = texts.map(t => t.data).join("").indexOf(caret.text)
+            if (i < 0) return null
+            let end = i + caret.text.length
+            for (let t of texts) {
+                if (end <= t.length) {
+                    range.setStart(t, end)
+                    return range
+                }
+                end -= t.length
+            }
+        }
+        let t = caret.at === "start" ? texts[0] : texts[texts.length - 1]
+        if (t) {
+            range.setStart(t, caret.at === "start" ? 0 : t.length)
+        } else {
+            // an empty editor: the caret goes in its first block
+            let leaf = host
+            while (leaf.firstElementChild && leaf.firstElementChild.tagName !== "BR") leaf = leaf.firstElementChild
+            range.setStart(leaf, 0)
+        }
+        range.collapse(true)
+        return range
+    }
+    let describeCaret = (caret) => caret.at === "after" ? `after ${JSON.stringify(caret.text)}` : `at the ${caret.at}`
+    let placeCaretImpl = one(b.isVisible, b.isEnabled, (n, caret) => {
+        let host = editorOf(n)
+        if (!host) {
+            if (!caret) return rRes(false)
+            if (!n.matches("input,textarea")) return rErr("can only place the caret in an <input>, a <textarea> or a contenteditable editor")
+            let v = n.value, at = caret.at === "start" ? 0 : v.length
+            if (caret.at === "after") {
+                let i = v.indexOf(caret.text)
+                if (i < 0) return rErr(`cannot place the caret ${describeCaret(caret)}: the value is ${JSON.stringify(v)}`)
+                at = i + caret.text.length
+            }
+            try {
+                if (caret.at === "all") n.select()
+                else n.setSelectionRange(at, at)
+            } catch (e) {
+                return rErr(`cannot place the caret in an <input type="${n.type}">`)
+            }
+            return rRes(false)
+        }
+        let range = caretRange(host, caret || { at: "end" })
+        if (!range) return rErr(`cannot place the caret ${describeCaret(caret)}: the editor's text is ${JSON.stringify(editorText(host))}`)
+        host.focus()
+        let selection = window.getSelection()
+        selection.removeAllRanges()
+        selection.addRange(range)
+        return rRes(true)
+    })
+    // placeCaret focuses the editor n stands for and places its caret, resolving true; for an <input>
+    // or <textarea> it places the caret only when asked to, and resolves false.  Async: an editor
+    // syncs its own selection on selectionchange, so it gets a frame to see the new caret before
+    // anything is typed at it.
+    b.placeCaret = (s, caret) => {
+        let result = placeCaretImpl(s, caret)
+        if (!result.result) return Promise.resolve(result)
+        return new Promise(resolve => nextFrame(() => resolve(result)))
+    }
+    let editorGetter = (read) => one(n => {
+        let host = editorOf(n)
+        if (!host) return rErr("DOM element is not contenteditable, and contains no contenteditable editor")
+        return rRes(read(host))
+    })
+    b.editorText = editorGetter(editorText)
+    b.editorHTML = editorGetter(host => host.innerHTML.trim())
+
+    // ---- Pin ------------------------------------------------------------------------------------
+    // pins holds the nodes ElementHandles are bound to, by pin id: the node (weakly - a detached node
+    // is left for the garbage collector), how to describe it, the selector that pinned it, and - once
+    // the observer sees it leave the document - when it was detached.  live holds the ids whose nodes
+    // have not been seen to leave, which is all the observer has to check.  Pins last as long as this
+    // document: a navigation starts a fresh _biloba with none.
+    let pins = new Map()
+    let live = new Set()
+    let pinObserver = null
+    let realNow = () => (window._bilobaClock ? window._bilobaClock.native.Date : Date).now()
+    let stampDetached = () => {
+        let now = realNow()
+        for (let id of live) {
+            let p = pins.get(id), n = p.node.deref()
+            if (n && n.isConnected) continue
+            p.detachedAt = now
+            live.delete(id)
+        }
+    }
+    let isPin = (s) => typeof s == "string" && s.charAt(0) == "p"
+    // PinGone is what selEach throws for a pin whose node is not in the page; see invoke
+    class PinGone {
+        constructor(id) { this.id = id }
+    }
+    // selEachLive is selEach for the observers that re-run a selector after the call that set them up
+    // has returned: there is no one to report a detached pin to, and it simply matches nothing
+    let selEachLive = (s) => {
+        try { return selEach(s) } catch (e) { if (e instanceof PinGone) return []; throw e }
+    }
+    let pinnedNode = (id) => {
+        let p = pins.get(id)
+        let n = p && p.node.deref()
+        return n && n.isConnected ? n : null
+    }
+    let pinDescription = (id) => pins.has(id) ? `pinned(${pins.get(id).description})` : id
+    // pinGone is the error for a pin whose node is not in the page.  gone tells Go to stop polling:
+    // the node will not come back.
+    let pinGone = (id) => {
+        let p = pins.get(id)
+        if (!p) return { error: `the pinned element ${id} is not in this document - it was pinned in another tab or frame, or before the page navigated`, gone: true }
+        // a node removed inside a shadow root escapes the document observer; it is stamped when noticed
+        stampDetached()
+        let stamp = new Date(p.detachedAt).toISOString().slice(11, 23) + " UTC"
+        let after = ((p.detachedAt - p.pinnedAt) / 1000).toFixed(2) + "s after it was pinned"
+        let now = null
+        try { now = p.selector === null ? null : sel(p.selector) } catch (e) { if (!(e instanceof PinGone)) throw e }
+        if (now && now !== p.node.deref()) {
+            return { error: `the pinned element (${p.description}) was replaced at ${stamp}, ${after} - its selector now matches a different element: ${focusDescription(now)}`, gone: true }
+        }
+        return { error: `the pinned element (${p.description}) was detached at ${stamp}, ${after}`, gone: true }
+    }
+    // pinNode binds id to n.  selector is what pinned it, for telling a replaced node from a removed
+    // one - null when nothing did.
+    let pinNode = (id, n, description, selector) => {
+        pins.set(id, { node: new WeakRef(n), description: description, selector: selector, pinnedAt: realNow(), detachedAt: null })
+        live.add(id)
+        if (!pinObserver) {
+            pinObserver = new MutationObserver((records) => {
+                if (live.size && records.some(m => m.removedNodes.length)) stampDetached()
+            })
+            pinObserver.observe(document, { childList: true, subtree: true })
+        }
+    }
+    b.pin = poll((n, id, description, selector) => {
+        pinNode(id, n, description, selector)
+        return r()
+    })
+
+    // ---- Watch ----------------------------------------------------------------------------------
+    // watches records, by watch id, every appearance, disappearance and text change of the elements
+    // a selector matches.  It re-checks after every DOM change rather than sampling, so an element that
+    // is up for a single frame is still seen.  present maps each element currently matching (and
+    // visible) to how it was described and what it said.  A watch keeps its first maxWatchEvents
+    // events; past that it keeps only appearances (up to twice the cap), so text that keeps ticking
+    // can't crowd out a later appearance, and counts what it dropped.  A MutationObserver on the
+    // document doesn't see inside shadow roots, so the watch observes every open shadow root too - the
+    // ones there when it starts, and the ones that arrive with added elements.
+    let watches = new Map()
+    let maxWatchEvents = 1000
+    b.startWatching = (s, id) => {
+        selEach(s) // a detached pin fails here, rather than watching nothing
+        let started = realNow()
+        let w = { events: [], dropped: 0, present: new Map(), observer: null }
+        let record = (kind, seen) => {
+            if (w.events.length >= maxWatchEvents && (kind !== "appeared" || w.events.length >= 2 * maxWatchEvents)) {
+                w.dropped++
+                return
+            }
+            w.events.push({ kind: kind, at: realNow() - started, element: seen.element, text: seen.text })
+        }
+        let check = () => {
+            let now = new Map()
+            for (let n of selEachLive(s)) {
+                if (!b.isVisible(n).success) continue
+                let previous = w.present.get(n)
+                let text = normText(n.innerText)
+                now.set(n, { element: previous ? previous.element : focusDescription(n), text: text })
+            }
+            for (let [n, seen] of now) {
+                let previous = w.present.get(n)
+                if (!previous) record("appeared", seen)
+                else if (previous.text !== seen.text) record("text", seen)
+            }
+            for (let [n, seen] of w.present) if (!now.has(n)) record("disappeared", seen)
+            w.present = now
+        }
+        let observed = new WeakSet()
+        let observe = (root) => {
+            if (observed.has(root)) return
+            observed.add(root)
+            w.observer.observe(root, { childList: true, subtree: true, attributes: true, characterData: true })
+        }
+        let observeShadowRoots = (root) => {
+            for (let el of collectElements(root)) if (el.shadowRoot) observe(el.shadowRoot)
+        }
+        check()
+        w.observer = new MutationObserver((records) => {
+            for (let m of records) for (let n of m.addedNodes) if (n.nodeType === 1) observeShadowRoots(n)
+            check()
+        })
+        observe(document)
+        observeShadowRoots(document)
+        watches.set(id, w)
+        return r()
+    }
+    let watchNotRunning = () => rErr("this watch is not in this document - the page has navigated since StartWatching")
+    b.watchEvents = (id) => watches.has(id) ? rRes({ events: watches.get(id).events, dropped: watches.get(id).dropped }) : watchNotRunning()
+    b.stopWatching = (id) => {
+        if (!watches.has(id)) return watchNotRunning()
+        watches.get(id).observer.disconnect()
+        return r()
+    }
+
+    // ---- RecordMutations ------------------------------------------------------------------------
+    // A mutation recorder keeps a timeline of the DOM mutations that touch the elements a selector
+    // matches (every mutation, for the document-wide recorder behind the failure artifact): when, what
+    // kind, which node, which attribute, and which elements were added and removed.  Only the latest
+    // maxMutationEntries are kept; total counts them all.  describeMutated names a node cheaply - tag,
+    // id and classes - since it runs for every mutation.
+    let maxMutationEntries = 5000
+    let describeMutated = (n) => {
+        if (n.nodeType !== Node.ELEMENT_NODE) return n.parentElement ? describeMutated(n.parentElement) : n.nodeName.toLowerCase()
+        let d = n.tagName.toLowerCase()
+        if (n.id) d += "#" + n.id
+        for (let c of [...n.classList].slice(0, 3)) d += "." + c
+        return d
+    }
+    let describeMutatedElements = (nodes) => [...nodes].filter(x => x.nodeType === Node.ELEMENT_NODE).slice(0, 5).map(describeMutated)
+    let newMutationRecorder = (s) => {
+        let rec = { started: realNow(), entries: [], total: 0, observer: null }
+        // previous holds what the selector matched after the last batch, so a mutation that removes a
+        // match is still counted in the batch that removes it - and then the match is let go
+        let previous = s === null ? [] : selEach(s)
+        rec.observer = new MutationObserver((mutations) => {
+            let matches = null, touching = null
+            if (s !== null) {
+                let current = selEachLive(s)
+                matches = new Set([...previous, ...current])
+                previous = current
+                // touching holds the nodes this batch added or removed that are, or hold, a match: a
+                // match's ancestors are walked up to them, once per batch rather than once per mutation
+                let moved = new Set()
+                for (let m of mutations) for (let x of [...m.addedNodes, ...m.removedNodes]) moved.add(x)
+                touching = new Set()
+                for (let n of matches) for (let a = n; a; a = a.parentNode) if (moved.has(a)) touching.add(a)
+            }
+            let inMatch = (x) => { for (let n = x; n; n = n.parentNode) if (matches.has(n)) return true; return false }
+            let now = realNow()
+            for (let m of mutations) {
+                if (s !== null && !inMatch(m.target) && ![...m.addedNodes, ...m.removedNodes].some(x => touching.has(x))) continue
+                rec.total++
+                rec.entries.push({ t: now, kind: m.type, node: describeMutated(m.target), attribute: m.attributeName || "", added: describeMutatedElements(m.addedNodes), removed: describeMutatedElements(m.removedNodes) })
+            }
+            if (rec.entries.length > maxMutationEntries) rec.entries.splice(0, rec.entries.length - maxMutationEntries)
+        })
+        rec.observer.observe(document, { childList: true, subtree: true, attributes: true, characterData: true })
+        return rec
+    }
+    // readMutations reports a recorder's entries from since (epoch ms) on, timed from since
+    let readMutations = (rec, since) => {
+        let entries = rec.entries.filter(e => e.t >= since).map(e => Object.assign({}, e, { at: e.t - since, t: undefined }))
+        return { total: rec.total, entries: entries }
+    }
+    let mutationLogs = new Map()
+    b.recordMutations = (s, id) => {
+        mutationLogs.set(id, newMutationRecorder(s))
+        return r()
+    }
+    let mutationLogGone = () => rErr("this mutation log is not in this document - the page has navigated since RecordMutations")
+    b.mutationLog = (id) => mutationLogs.has(id) ? rRes(readMutations(mutationLogs.get(id), mutationLogs.get(id).started)) : mutationLogGone()
+    b.stopRecordingMutations = (id) => {
+        if (!mutationLogs.has(id)) return mutationLogGone()
+        mutationLogs.get(id).observer.disconnect()
+        return r()
+    }
+    // churn is the document-wide recorder behind BilobaConfigFailureMutations; churnSince reads what
+    // it saw in the last elapsed ms, with total counting only those.  The window is measured on the
+    // page's clock, since the Go side's may not agree with it (a remote or containerised Chrome).
+    let churn = null
+    b.startChurnRecorder = () => {
+        if (!churn) churn = newMutationRecorder(null)
+        return r()
+    }
+    b.churnSince = (elapsed) => {
+        if (!churn) return rRes(null)
+        let read = readMutations(churn, realNow() - elapsed)
+        read.total = read.entries.length
+        return rRes(read)
+    }
+
     window["_biloba"] = b
 }
\ No newline at end of file
@@ -0,0 +1,187 @@
+package biloba
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+//go:embed clock.js
+var clockJS string
+
+// clockCallJS dispatches to the clock installed in the current document.  "sync" just reads the time.
+const clockCallJS = `(name, ...args) => {
+	let c = window._bilobaClock
+	if (!c) return { error: "the clock is not installed in this document" }
+	if (name === "sync") return { success: true, result: c.now() }
+	return c[name](...args)
+}`
+
+// clockSlot holds the fake clock installed on a TARGET.  It is a pointer on Biloba for the same reason
+// media is: the clone-with-a-flag views (Realistic() and friends) must share the tab's one slot, so
+// that Prepare() sees a clock installed through any of them.
+type clockSlot struct {
+	installed *Clock
+}
+
+/*
+Clock is a fake clock installed on a tab with [Biloba.InstallClock].  While it is installed the page's Date, performance.now, setTimeout, setInterval and requestAnimationFrame are driven by the spec rather than by real time.
+
+Read https://onsi.github.io/biloba/#controlling-time to learn more
+*/
+type Clock struct {
+	b        *Biloba
+	scriptID page.ScriptIdentifier
+	now      time.Time
+	running  bool
+}
+
+/*
+InstallClock(startTime) installs a fake clock on this tab and returns a [Clock] to drive it.  From then on Date and performance.now are frozen at startTime, and setTimeout, setInterval and requestAnimationFrame callbacks only fire when the spec moves time forward:
+
+	clock := b.InstallClock(time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC))
+	b.Navigate(fixtureServer + "/session.html")
+	clock.Advance(29 * time.Minute)
+	Eventually("#expiry-banner").Should(b.BeVisible())
+
+The clock is installed before any page script runs - in the current document and in every document the tab loads afterwards - so call InstallClock before Navigate if the page schedules timers while it loads.  A navigation carries the clock's current time over to the new document; pending timers, like everything else on the old page, do not survive it.
+
+Biloba's own waits (polling, realistic-mode scrolling, screenshot settling) run on real time and are unaffected.  A DeferCleanup is automatically registered to uninstall the clock when the spec ends.  Only one clock can be installed on a tab at a time.
+
+Read https://onsi.github.io/biloba/#controlling-time to learn more
+*/
+func (b *Biloba) InstallClock(startTime time.Time) *Clock {
+	b.gt.Helper()
+	b.guardConfig("InstallClock")
+	if b.clock.installed != nil {
+		b.gt.Fatalf("InstallClock: this tab already has a clock installed - use the Clock it returned")
+		return nil
+	}
+	c := &Clock{b: b, now: startTime}
+	if err := c.register(true); err != nil {
+		b.gt.Fatalf("Failed to install clock:\n%s", err.Error())
+		return nil
+	}
+	b.clock.installed = c
+	b.gt.DeferCleanup(func() {
+		if b.clock.installed == c {
+			if err := c.uninstall(); err != nil {
+				b.gt.Fatalf("Failed to uninstall clock:\n%s", err.Error())
+			}
+		}
+	})
+	return c
+}
+
+/*
+Advance(d) moves the clock forward by d, firing every timer, interval and animation frame that falls due along the way in the order a real browser would fire them.  Date and performance.now read the timer's due time while its callback runs.  Advancing through hours with a live animation loop or a busy interval is fine; it fails only if a timer keeps rescheduling itself with no delay (after 1000 timers without the clock moving).
+*/
+func (c *Clock) Advance(d time.Duration) {
+	c.b.gt.Helper()
+	c.call("Advance", "advance", float64(d)/float64(time.Millisecond))
+}
+
+/*
+RunAllTimers() fires pending timers, in order, until none are left - moving the clock forward to each one's due time.  Timers scheduled by a callback are run too.  It fails if the queue never drains (after 1000 timers), which is what an interval or a self-rescheduling animation loop does: use [Clock.Advance] for those.
+*/
+func (c *Clock) RunAllTimers() {
+	c.b.gt.Helper()
+	c.call("RunAllTimers", "runAll")
+}
+
+/*
+SetTime(t) sets the page's wall-clock time (Date) to t without firing any timers - just like changing the system clock on a real machine.  Use it to jump a "5 minutes ago" label forward; use [Clock.Advance] when the page's timers should run.
+*/
+func (c *Clock) SetTime(t time.Time) {
+	c.b.gt.Helper()
+	c.call("SetTime", "setTime", float64(t.UnixMilli()))
+}
+
+/*
+Resume() lets the clock run on real time again, starting from wherever the spec left it: time flows and timers fire as they fall due.  [Clock.Advance] and [Clock.SetTime] still work on a resumed clock.
+*/
+func (c *Clock) Resume() {
+	c.b.gt.Helper()
+	c.running = true
+	c.call("Resume", "resume")
+}
+
+/*
+Now() returns the page's current (fake) wall-clock time.
+*/
+func (c *Clock) Now() time.Time {
+	c.b.gt.Helper()
+	c.call("Now", "sync")
+	return c.now
+}
+
+// call runs one of clock.js's operations in the page and then re-registers the new-document script
+// at the clock's new time.  Re-registering is what makes a navigation carry the clock forward: the
+// next document starts where this one left off, rather than back at InstallClock's startTime.
+func (c *Clock) call(method string, name string, args ...any) {
+	c.b.gt.Helper()
+	result := &bilobaJSResponse{}
+	if _, err := c.b.RunErr(c.b.JSFunc(clockCallJS).Invoke(append([]any{name}, args...)...), result); err != nil {
+		result.Err = err.Error()
+	}
+	if result.Err != "" {
+		c.b.gt.Fatalf("Clock.%s failed:\n%s", method, result.Err)
+		return
+	}
+	if ms, ok := result.Result.(float64); ok {
+		c.now = time.UnixMilli(int64(ms))
+	}
+	if name == "sync" {
+		return
+	}
+	if err := c.register(false); err != nil {
+		c.b.gt.Fatalf("Clock.%s failed:\n%s", method, err.Error())
+	}
+}
+
+// register (re)places the script that installs the clock in every new document.  The first
+// registration also runs immediately in the documents that already exist.  A running clock is
+// anchored to real time so the next document can add on however long the navigation took.
+func (c *Clock) register(runImmediately bool) error {
+	config := map[string]any{"now": c.now.UnixMilli(), "running": c.running}
+	if c.running {
+		config["anchor"] = time.Now().UnixMilli()
+	}
+	encoded, _ := json.Marshal(config)
+	source := clockJS + "\nwindow._bilobaClock.install(" + string(encoded) + ");"
+	return chromedp.Run(c.b.Context, chromedp.ActionFunc(func(ctx context.Context) error {
+		if c.scriptID != "" {
+			if err := page.RemoveScriptToEvaluateOnNewDocument(c.scriptID).Do(ctx); err != nil {
+				return err
+			}
+		}
+		id, err := page.AddScriptToEvaluateOnNewDocument(source).WithRunImmediately(runImmediately).Do(ctx)
+		c.scriptID = id
+		return err
+	}))
+}
+
+// uninstall removes the new-document script and restores the page's real timers.  Timers still pending
+// on the fake clock are dropped, not rescheduled: uninstall runs at teardown, on the way to a fresh page.
+func (c *Clock) uninstall() error {
+	c.b.clock.installed = nil
+	err := chromedp.Run(c.b.Context, page.RemoveScriptToEvaluateOnNewDocument(c.scriptID))
+	c.b.RunErr(`window._bilobaClock && window._bilobaClock.uninstall()`)
+	return err
+}
+
+// uninstallLeakedClock is Prepare()'s belt and braces for a clock whose DeferCleanup never ran (an
+// InstallClock in a BeforeSuite, say).  The new-document script is a TARGET-level registration that
+// survives Navigate, so left alone it would freeze time in every later spec on the reused root tab.
+func (b *Biloba) uninstallLeakedClock() {
+	if b.clock == nil || b.clock.installed == nil {
+		return
+	}
+	if err := b.clock.installed.uninstall(); err != nil {
+		b.gt.Printf("Failed to uninstall a leaked clock during Prepare():\n  %s\n", err.Error())
+	}
+}
@@ -0,0 +1,179 @@
+if (!window["_bilobaClock"]) {
+    let c = {}
+    // native holds the real timer functions so that Biloba's own in-page helpers (which wait on
+    // animation frames) keep working while the page's clock is frozen - and so uninstall can put
+    // them back.
+    let native = {
+        Date: window.Date,
+        setTimeout: window.setTimeout.bind(window),
+        clearTimeout: window.clearTimeout.bind(window),
+        setInterval: window.setInterval.bind(window),
+        clearInterval: window.clearInterval.bind(window),
+        requestAnimationFrame: window.requestAnimationFrame.bind(window),
+        cancelAnimationFrame: window.cancelAnimationFrame.bind(window),
+        performanceNow: window.performance.now.bind(window.performance),
+    }
+    c.native = native
+    let rErr = (err) => { return { error: err } }
+    let rRes = (res) => { return { success: true, result: res } }
+
+    // Two separate notions of time, the same split a real machine has.  ticks is the monotonic clock
+    // that drives timers and performance.now(); it only ever moves forward, and only when the spec
+    // says so.  offset turns ticks into wall-clock time for Date.  SetTime moves the wall clock
+    // without firing a single timer (that is what changing the system clock does to a real page),
+    // while Advance moves both.
+    let ticks = 0, offset = 0, perfBase = 0
+    let timers = new Map(), nextId = 1, seq = 0
+    let driver = null, lastReal = 0
+    let frameMs = 16
+
+    let wallNow = () => ticks + offset
+    c.now = () => wallNow()
+
+    let schedule = (cb, args, delay, interval, raf) => {
+        if (typeof cb === "string") {
+            let code = cb
+            cb = () => (0, eval)(code)
+        }
+        if (typeof cb !== "function") return 0
+        let id = nextId++
+        delay = Math.max(0, Number(delay) || 0)
+        let at = ticks + delay
+        // an animation frame lands on the next frame boundary, like the compositor's vsync would
+        if (raf) at = (Math.floor(ticks / frameMs) + 1) * frameMs
+        timers.set(id, { id: id, cb: cb, args: args, at: at, interval: interval ? Math.max(1, delay) : 0, raf: raf, seq: seq++ })
+        return id
+    }
+    let cancel = (id) => { timers.delete(id) }
+
+    let earliest = (limit) => {
+        let best = null
+        for (let t of timers.values()) {
+            if (limit !== undefined && t.at > limit) continue
+            if (!best || t.at < best.at || (t.at === best.at && t.seq < best.seq)) best = t
+        }
+        return best
+    }
+
+    // fire runs one timer at its own due time.  A callback that throws must not stop the clock: the
+    // error is rethrown on a real task so it still reaches the console (and Biloba's console-error
+    // capture), exactly as an uncaught error in a real timer would.
+    let fire = (t) => {
+        ticks = Math.max(ticks, t.at)
+        if (t.interval) {
+            t.at += t.interval
+            t.seq = seq++
+        } else {
+            timers.delete(t.id)
+        }
+        try {
+            if (t.raf) t.cb(ticks + perfBase)
+            else t.cb.apply(window, t.args)
+        } catch (e) {
+            native.setTimeout(() => { throw e })
+        }
+    }
+
+    // sameTickLimit bounds how many timers one Advance fires at a single instant.  A long Advance
+    // through a live animation loop or a busy interval fires a great many timers, but each moves the
+    // clock; a timer that keeps rescheduling itself with no delay never does, and would otherwise
+    // spin forever inside one Runtime.evaluate and wedge the tab.
+    let sameTickLimit = 1000
+
+    c.advance = (ms) => {
+        let target = ticks + Math.max(0, ms)
+        let instant = null, atInstant = 0
+        for (let t = earliest(target); t; t = earliest(target)) {
+            let at = Math.max(ticks, t.at)
+            if (at !== instant) {
+                instant = at
+                atInstant = 0
+            }
+            if (++atInstant > sameTickLimit) return rErr("gave up after firing " + sameTickLimit + " timers without the clock moving while advancing it: a timer keeps rescheduling itself with no delay")
+            fire(t)
+        }
+        ticks = target
+        return rRes(wallNow())
+    }
+
+    c.runAll = () => {
+        let fired = 0
+        for (let t = earliest(); t; t = earliest()) {
+            if (++fired > 1000) return rErr("gave up after firing 1000 timers: the timer queue never drains (a setInterval, or a timer or animation frame that reschedules itself, keeps adding work).  Use Advance to move the clock by a fixed amount instead")
+            fire(t)
+        }
+        return rRes(wallNow())
+    }
+
+    c.setTime = (ms) => {
+        offset = ms - ticks
+        return rRes(wallNow())
+    }
+
+    // resume hands the clock back to real time, starting from wherever the spec left it: a native
+    // interval advances the fake clock by however much real time has passed, firing anything that
+    // falls due along the way.
+    c.resume = () => {
+        if (!driver) {
+            lastReal = native.performanceNow()
+            driver = native.setInterval(() => {
+                let real = native.performanceNow()
+                c.advance(real - lastReal)
+                lastReal = real
+            }, frameMs)
+        }
+        return rRes(wallNow())
+    }
+
+    c.install = (config) => {
+        let start = config.now
+        // a running clock re-registered for the next document picks up where the previous one was,
+        // plus however long the navigation took
+        if (config.running && config.anchor) start += native.Date.now() - config.anchor
+        ticks = 0
+        offset = start
+        perfBase = native.performanceNow()
+
+        let NativeDate = native.Date
+        let FakeDate = function (...args) {
+            if (!new.target) return new NativeDate(wallNow()).toString()
+            if (args.length === 0) return new NativeDate(wallNow())
+            return new NativeDate(...args)
+        }
+        FakeDate.prototype = NativeDate.prototype
+        FakeDate.now = () => wallNow()
+        FakeDate.parse = NativeDate.parse
+        FakeDate.UTC = NativeDate.UTC
+        FakeDate.toString = () => NativeDate.toString()
+
+        window.Date = FakeDate
+        window.setTimeout = (cb, delay, ...args) => schedule(cb, args, delay, false, false)
+        window.clearTimeout = cancel
+        window.setInterval = (cb, delay, ...args) => schedule(cb, args, delay, true, false)
+        window.clearInterval = cancel
+        window.requestAnimationFrame = (cb) => schedule(cb, [], 0, false, true)
+        window.cancelAnimationFrame = cancel
+        window.performance.now = () => ticks + perfBase
+
+        if (config.running) c.resume()
+        return rRes(wallNow())
+    }
+
+    c.uninstall = () => {
+        if (driver) native.clearInterval(driver)
+        driver = null
+        timers.clear()
+        window.Date = native.Date
+        window.setTimeout = native.setTimeout
+        window.clearTimeout = native.clearTimeout
+        window.setInterval = native.setInterval
+        window.clearInterval = native.clearInterval
+        window.requestAnimationFrame = native.requestAnimationFrame
+        window.cancelAnimationFrame = native.cancelAnimationFrame
+        window.performance.now = native.performanceNow
+        delete window["_bilobaClock"]
+        return rRes(true)
+    }
+
+    window["_bilobaClock"] = c
+}
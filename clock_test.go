@@ -0,0 +1,130 @@
+package biloba_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InstallClock", func() {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/clock.html")
+		Eventually("#hello").Should(b.Exist())
+	})
+
+	Context("when the clock is installed before the page loads", func() {
+		BeforeEach(func() {
+			b.InstallClock(start)
+			b.Navigate(fixtureServer + "/clock.html")
+			Eventually("#hello").Should(b.Exist())
+		})
+
+		It("freezes Date at the start time", func() {
+			Eventually("#loaded-at").Should(b.HaveInnerText("2026-01-01T09:00:00.000Z"))
+			Consistently("#countdown", 200*time.Millisecond).Should(b.HaveInnerText("10"))
+		})
+	})
+
+	It("fires timers and intervals in order as the clock is advanced", func() {
+		clock := b.InstallClock(start)
+		b.Navigate(fixtureServer + "/clock.html")
+		Eventually("#countdown").Should(b.HaveInnerText("10"))
+
+		clock.Advance(3 * time.Second)
+		Eventually("#countdown").Should(b.HaveInnerText("7"))
+		Ω(clock.Now()).Should(BeTemporally("==", start.Add(3*time.Second)))
+
+		Ω("#expiry-banner").ShouldNot(b.BeVisible())
+		clock.Advance(30 * time.Minute)
+		Eventually("#expiry-banner").Should(b.BeVisible())
+		Ω("#countdown").Should(b.HaveInnerText("0"))
+
+		b.Run("schedule([300, 100, 200])")
+		clock.Advance(250 * time.Millisecond)
+		Ω(b.Run("fired")).Should(Equal([]any{100.0, 200.0}))
+	})
+
+	It("runs animation frames only when the clock moves", func() {
+		clock := b.InstallClock(start)
+		b.Navigate(fixtureServer + "/clock.html")
+		Consistently("#frames", 200*time.Millisecond).Should(b.HaveInnerText("0"))
+		clock.Advance(32 * time.Millisecond)
+		Ω("#frames").Should(b.HaveInnerText("2"))
+	})
+
+	It("can run every pending timer", func() {
+		clock := b.InstallClock(start)
+		b.Navigate(fixtureServer + "/clock.html")
+		b.Run("clearInterval(countdown)")
+		b.Run("schedule([5000, 1000])")
+		clock.RunAllTimers()
+		Ω(b.Run("fired")).Should(Equal([]any{1000.0, 5000.0}))
+		Ω("#expiry-banner").Should(b.BeVisible())
+	})
+
+	It("fails when the timer queue never drains", func() {
+		clock := b.InstallClock(start)
+		b.Navigate(fixtureServer + "/clock.html")
+		b.Run("setInterval(() => {}, 10)")
+		clock.RunAllTimers()
+		ExpectFailures(ContainSubstring("Clock.RunAllTimers failed:\ngave up after firing 1000 timers"))
+	})
+
+	It("advances through long spans with live animation loops and intervals", func() {
+		clock := b.InstallClock(start)
+		b.Navigate(fixtureServer + "/clock.html")
+		b.Run("startLoops()")
+		clock.Advance(30 * time.Minute)
+		Ω(b.Run("loopFrames")).Should(BeNumerically("~", 30*60*1000/16, 2))
+		Ω(b.Run("loopTicks")).Should(Equal(18000.0))
+		Ω("#expiry-banner").Should(b.BeVisible())
+	})
+
+	It("fails when a timer keeps rescheduling itself without the clock moving", func() {
+		clock := b.InstallClock(start)
+		b.Navigate(fixtureServer + "/clock.html")
+		b.Run("spin()")
+		clock.Advance(time.Second)
+		ExpectFailures(ContainSubstring("Clock.Advance failed:\ngave up after firing 1000 timers without the clock moving"))
+	})
+
+	It("moves the wall clock without firing timers with SetTime", func() {
+		clock := b.InstallClock(start)
+		b.Navigate(fixtureServer + "/clock.html")
+		Eventually("#ago").Should(b.HaveInnerText("0 minutes ago"))
+
+		clock.SetTime(start.Add(5 * time.Minute))
+		Ω("#countdown").Should(b.HaveInnerText("10"))
+		clock.Advance(time.Second)
+		Ω("#ago").Should(b.HaveInnerText("5 minutes ago"))
+		Ω("#countdown").Should(b.HaveInnerText("9"))
+	})
+
+	It("carries the clock's time across a navigation", func() {
+		clock := b.InstallClock(start)
+		clock.Advance(time.Hour)
+		b.Navigate(fixtureServer + "/clock.html")
+		Eventually("#loaded-at").Should(b.HaveInnerText("2026-01-01T10:00:00.000Z"))
+	})
+
+	It("lets time flow again after Resume", func() {
+		clock := b.InstallClock(start)
+		b.Navigate(fixtureServer + "/clock.html")
+		clock.Resume()
+		Eventually("#countdown").Should(b.HaveInnerText("9"))
+		Ω(clock.Now()).Should(BeTemporally(">", start))
+	})
+
+	It("refuses to install a second clock", func() {
+		b.InstallClock(start)
+		b.InstallClock(start)
+		ExpectFailures(ContainSubstring("this tab already has a clock installed"))
+	})
+
+	It("does not leak the clock into the next spec", func() {
+		Ω(b.Run("typeof window._bilobaClock")).Should(Equal("undefined"))
+	})
+})
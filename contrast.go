@@ -0,0 +1,325 @@
+package biloba
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gcustom"
+)
+
+/*
+ContrastResult is the colour contrast of one element's text, as [Biloba.ContrastReport] and
+[Biloba.HaveSufficientContrast] measure it.  Foreground and Background are the effective colours - the
+text colour after opacity, over the composited backgrounds behind it - in the canonical "rgb(...)" form
+[Biloba.NormalizeColor] produces, so they compare with [Biloba.MatchColor].  Ratio is the WCAG contrast
+ratio between them.  LargeText is WCAG's "large scale" text (24px, or 18.66px and bold), which needs
+less contrast.  Sampled means a background image sits behind the text, so Background is the pixel of
+the screenshot that contrasts worst with it.
+*/
+type ContrastResult struct {
+	Selector   string
+	Text       string
+	Foreground string
+	Background string
+	Ratio      float64
+	LargeText  bool
+	Sampled    bool
+}
+
+// Passes reports whether the result meets level: "AA" or "AAA".  Any other level fails.
+func (r ContrastResult) Passes(level string) bool {
+	required, err := requiredContrast(level, r.LargeText)
+	return err == nil && r.Ratio >= required
+}
+
+func (r ContrastResult) String() string {
+	notes := []string{}
+	if r.LargeText {
+		notes = append(notes, "large text")
+	}
+	if r.Sampled {
+		notes = append(notes, "background sampled from a screenshot")
+	}
+	out := fmt.Sprintf("%s %q: %s on %s is %s:1", r.Selector, r.Text, r.Foreground, r.Background, formatContrastRatio(r.Ratio))
+	if len(notes) > 0 {
+		out += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return out
+}
+
+/*
+ContrastReport is what [Biloba.ContrastReport] returns: one [ContrastResult] per element with text.
+Failing narrows it to the results that miss a level, and String renders one result per line.
+*/
+type ContrastReport []ContrastResult
+
+func (r ContrastReport) Failing(level string) ContrastReport {
+	failing := ContrastReport{}
+	for _, result := range r {
+		if !result.Passes(level) {
+			failing = append(failing, result)
+		}
+	}
+	return failing
+}
+
+func (r ContrastReport) String() string {
+	out := &strings.Builder{}
+	for _, result := range r {
+		fmt.Fprintf(out, "%s\n", result)
+	}
+	return out.String()
+}
+
+/*
+ContrastReport(selector) measures the colour contrast of the text in the element matching selector
+and everything inside it - every rendered element that has text of its own.  Pass the tab itself to
+cover the whole page:
+
+	report := b.ContrastReport("#settings")
+	Ω(report.Failing("AA")).Should(BeEmpty(), report.Failing("AA").String())
+
+Colours are resolved the way the browser paints them: the text's computed colour and the opacity of
+the elements it sits in, composited over every background-color behind it down to the first opaque one
+(or the page canvas, which is dark under a dark color-scheme).  Where a background image or gradient
+is behind the text, Biloba hides the text, screenshots the element, and takes the pixel that contrasts
+worst.
+
+ContrastReport is a one-shot snapshot: it does not poll.  To wait for one element to reach a level, use
+[Biloba.HaveSufficientContrast].
+
+Read https://onsi.github.io/biloba/#colour-contrast to learn more
+*/
+func (b *Biloba) ContrastReport(selector any) ContrastReport {
+	b.gt.Helper()
+	b.guardConfig("ContrastReport")
+	tab := b
+	if t, ok := selector.(*Biloba); ok {
+		tab, selector = t, "body"
+	}
+	r := tab.runBilobaHandler("contrastReport", selector)
+	if r.Error() != nil {
+		b.gt.Fatalf("Failed to measure contrast:\n%s", r.Error().Error())
+		return nil
+	}
+	infos := []contrastInfo{}
+	if err := remarshal(r.Result, &infos); err != nil {
+		b.gt.Fatalf("Failed to measure contrast:\n%s", err.Error())
+		return nil
+	}
+	report := ContrastReport{}
+	for _, info := range infos {
+		result, err := tab.resolveContrast(info)
+		if err != nil {
+			b.gt.Fatalf("Failed to measure contrast:\n%s", err.Error())
+			return nil
+		}
+		report = append(report, result)
+	}
+	return report
+}
+
+/*
+HaveSufficientContrast(level) is a Gomega matcher that passes once the text of the element matching
+the selector it is applied to meets WCAG contrast level "AA" (4.5:1, or 3:1 for large text) or "AAA"
+(7:1, or 4.5:1 for large text):
+
+	Eventually("#save").Should(b.HaveSufficientContrast("AA"))
+
+	b.EmulateMedia(biloba.MediaFeatures{ColorScheme: "dark"})
+	Eventually(".banner h2").Should(b.HaveSufficientContrast("AAA"))
+
+Colours are resolved as [Biloba.ContrastReport] resolves them.  The failure message has both colours
+and the ratio.  It returns a [ValueMatcher], so you can keep the [ContrastResult] it measured:
+
+	var result biloba.ContrastResult
+	Eventually("#save").Should(b.HaveSufficientContrast("AA").Capture(&result))
+
+Read https://onsi.github.io/biloba/#colour-contrast to learn more
+*/
+func (b *Biloba) HaveSufficientContrast(level string) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveSufficientContrast")
+	level = strings.ToUpper(level)
+	if _, err := requiredContrast(level, false); err != nil {
+		b.gt.Fatalf("HaveSufficientContrast: %s", err.Error())
+		return nil
+	}
+	data := map[string]any{"Level": level}
+	return capturableResult(gcustom.MakeMatcher(func(selector any) (bool, error) {
+		if _, err := encodeSelector(selector); err != nil {
+			return false, gomega.StopTrying(fmt.Sprintf("HaveSufficientContrast must be applied to a selector (a CSS string, an XPath, or a Locator).  Got:\n%s", format.Object(selector, 1)))
+		}
+		r := b.runBilobaHandler("contrastOf", selector)
+		if r.Error() != nil {
+			return false, r.Error()
+		}
+		info := contrastInfo{}
+		if err := remarshal(r.Result, &info); err != nil {
+			return false, err
+		}
+		result, err := b.resolveContrast(info)
+		if err != nil {
+			return false, err
+		}
+		required, _ := requiredContrast(level, result.LargeText)
+		data["Subject"] = describeSnapshotSubject(selector)
+		data["Result"] = result
+		data["Required"] = formatContrastRatio(required)
+		data["Ratio"] = formatContrastRatio(result.Ratio)
+		data["Size"] = map[bool]string{true: "large", false: "normal"}[result.LargeText]
+		return result.Ratio >= required, nil
+	}).WithTemplate("{{if .Failure}}Expected {{.Data.Subject}} to have {{.Data.Level}} contrast - at least {{.Data.Required}}:1 for {{.Data.Size}} text - but {{.Data.Result.Foreground}} on {{.Data.Result.Background}} is {{.Data.Ratio}}:1{{if .Data.Result.Sampled}} (the background is an image; that is its worst pixel){{end}}{{else}}Expected {{.Data.Subject}} NOT to have {{.Data.Level}} contrast, but {{.Data.Result.Foreground}} on {{.Data.Result.Background}} is {{.Data.Ratio}}:1{{end}}", data), data)
+}
+
+// requiredContrast is the WCAG 2 minimum contrast ratio for level.
+func requiredContrast(level string, largeText bool) (float64, error) {
+	switch {
+	case level == "AA" && largeText:
+		return 3, nil
+	case level == "AA", level == "AAA" && largeText:
+		return 4.5, nil
+	case level == "AAA":
+		return 7, nil
+	}
+	return 0, fmt.Errorf("unknown contrast level %q - use \"AA\" or \"AAA\"", level)
+}
+
+// formatContrastRatio truncates rather than rounds, so a ratio just short of 4.5 never prints as 4.50.
+func formatContrastRatio(ratio float64) string {
+	return fmt.Sprintf("%.2f", math.Floor(ratio*100+1e-9)/100)
+}
+
+// contrastInfo is what biloba.js's contrastOf and contrastReport report about an element's text.
+// Colours are sRGB [r, g, b, a] with channels 0-255 and alpha 0-1; layers runs from the background
+// nearest the text down to an opaque one.
+type contrastInfo struct {
+	Path       string       `json:"path"`
+	Text       string       `json:"text"`
+	FG         [4]float64   `json:"fg"`
+	Opacity    float64      `json:"opacity"`
+	Layers     [][4]float64 `json:"layers"`
+	Image      bool         `json:"image"`
+	FontSize   float64      `json:"fontSize"`
+	FontWeight float64      `json:"fontWeight"`
+}
+
+// resolveContrast turns what biloba.js measured into a ContrastResult, screenshotting the element
+// when there is a background image to sample.
+func (b *Biloba) resolveContrast(info contrastInfo) (ContrastResult, error) {
+	result := ContrastResult{
+		Selector:  info.Path,
+		Text:      info.Text,
+		LargeText: info.FontSize >= 24 || (info.FontSize >= 18.66 && info.FontWeight >= 700),
+		Sampled:   info.Image,
+	}
+	fg := info.FG
+	fg[3] *= info.Opacity
+	if !info.Image {
+		bg := compositeLayers(info.Layers)
+		text := composite(fg, bg)
+		result.Foreground, result.Background, result.Ratio = rgbString(text), rgbString(bg), contrastRatio(text, bg)
+		return result, nil
+	}
+	pixels, err := b.backgroundPixels(info.Path)
+	if err != nil {
+		return ContrastResult{}, err
+	}
+	text, bg, ratio := worstContrast(fg, pixels)
+	result.Foreground, result.Background, result.Ratio = rgbString(text), rgbString(bg), ratio
+	return result, nil
+}
+
+// backgroundPixels screenshots the element at path with its text hidden, and returns a grid of
+// samples from it - enough to find the worst spot on a gradient without visiting every pixel.
+func (b *Biloba) backgroundPixels(path string) ([][4]float64, error) {
+	if r := b.runBilobaHandler("hideTextForSampling", path); r.Error() != nil {
+		return nil, r.Error()
+	}
+	img, _, _, err := b.elementScreenshot(path)
+	b.runBilobaFunc("showSampledText")
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := decodeToNRGBA(img)
+	if err != nil {
+		return nil, err
+	}
+	return samplePixels(decoded, 48), nil
+}
+
+// samplePixels takes up to perSide x perSide evenly spaced opaque pixels from img.
+func samplePixels(img *image.NRGBA, perSide int) [][4]float64 {
+	bounds := img.Bounds()
+	stepX, stepY := max(1, bounds.Dx()/perSide), max(1, bounds.Dy()/perSide)
+	pixels := [][4]float64{}
+	for y := stepY / 2; y < bounds.Dy(); y += stepY {
+		for x := stepX / 2; x < bounds.Dx(); x += stepX {
+			c := img.NRGBAAt(x, y)
+			pixels = append(pixels, [4]float64{float64(c.R), float64(c.G), float64(c.B), 1})
+		}
+	}
+	return pixels
+}
+
+// worstContrast composites fg over each background pixel and returns the pair with the lowest ratio.
+func worstContrast(fg [4]float64, pixels [][4]float64) ([4]float64, [4]float64, float64) {
+	var worstText, worstBg [4]float64
+	worst := math.Inf(1)
+	for _, bg := range pixels {
+		text := composite(fg, bg)
+		if ratio := contrastRatio(text, bg); ratio < worst {
+			worst, worstText, worstBg = ratio, text, bg
+		}
+	}
+	return worstText, worstBg, worst
+}
+
+// compositeLayers flattens background layers (nearest first, the last one opaque) into one colour.
+func compositeLayers(layers [][4]float64) [4]float64 {
+	bg := [4]float64{255, 255, 255, 1}
+	for i := len(layers) - 1; i >= 0; i-- {
+		bg = composite(layers[i], bg)
+	}
+	return bg
+}
+
+// composite paints the (possibly translucent) colour top over the opaque colour bottom.
+func composite(top [4]float64, bottom [4]float64) [4]float64 {
+	a := top[3]
+	return [4]float64{
+		top[0]*a + bottom[0]*(1-a),
+		top[1]*a + bottom[1]*(1-a),
+		top[2]*a + bottom[2]*(1-a),
+		1,
+	}
+}
+
+// relativeLuminance is WCAG 2's relative luminance of an sRGB colour.
+func relativeLuminance(c [4]float64) float64 {
+	channel := func(v float64) float64 {
+		v /= 255
+		if v <= 0.04045 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c[0]) + 0.7152*channel(c[1]) + 0.0722*channel(c[2])
+}
+
+// contrastRatio is WCAG 2's contrast ratio, from 1 (no contrast) to 21 (black on white).
+func contrastRatio(a [4]float64, b [4]float64) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+func rgbString(c [4]float64) string {
+	return fmt.Sprintf("rgb(%d, %d, %d)", int(math.Round(c[0])), int(math.Round(c[1])), int(math.Round(c[2])))
+}
@@ -0,0 +1,96 @@
+package biloba
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like visual_diff_internal_test.go: they exercise the WCAG arithmetic
+// behind HaveSufficientContrast and ContrastReport.  No browser.
+
+var (
+	black = [4]float64{0, 0, 0, 1}
+	white = [4]float64{255, 255, 255, 1}
+)
+
+func TestContrastRatioMatchesWCAG(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(contrastRatio(black, white)).To(BeNumerically("~", 21, 0.001))
+	g.Expect(contrastRatio(white, black)).To(BeNumerically("~", 21, 0.001))
+	g.Expect(contrastRatio(white, white)).To(BeNumerically("~", 1, 0.001))
+	g.Expect(contrastRatio([4]float64{118, 118, 118, 1}, white)).To(BeNumerically("~", 4.54, 0.01))
+	g.Expect(contrastRatio([4]float64{153, 153, 153, 1}, white)).To(BeNumerically("~", 2.85, 0.01))
+}
+
+func TestCompositingFlattensTranslucentLayers(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(composite([4]float64{0, 0, 0, 0.5}, white)).To(Equal([4]float64{127.5, 127.5, 127.5, 1}))
+	g.Expect(compositeLayers([][4]float64{{255, 0, 0, 0.5}, {0, 0, 255, 1}})).To(Equal([4]float64{127.5, 0, 127.5, 1}))
+	g.Expect(compositeLayers([][4]float64{{0, 0, 0, 0.25}, {0, 0, 0, 0.5}, white})).To(Equal([4]float64{95.625, 95.625, 95.625, 1}))
+}
+
+func TestOpacityFadesTextOverAnOpaqueBackground(t *testing.T) {
+	g := NewWithT(t)
+	var b *Biloba // resolving without a background image needs no browser
+	result, err := b.resolveContrast(contrastInfo{FG: black, Opacity: 0.2, Layers: [][4]float64{white}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Foreground).To(Equal("rgb(204, 204, 204)"))
+	g.Expect(result.Ratio).To(BeNumerically("~", 1.61, 0.01))
+}
+
+func TestRequiredContrastByLevelAndSize(t *testing.T) {
+	g := NewWithT(t)
+	for _, c := range []struct {
+		level    string
+		large    bool
+		required float64
+	}{{"AA", false, 4.5}, {"AA", true, 3}, {"AAA", false, 7}, {"AAA", true, 4.5}} {
+		required, err := requiredContrast(c.level, c.large)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(required).To(Equal(c.required), "%s large=%t", c.level, c.large)
+	}
+	_, err := requiredContrast("A", false)
+	g.Expect(err).To(MatchError(`unknown contrast level "A" - use "AA" or "AAA"`))
+}
+
+func TestContrastResultsPassAndFail(t *testing.T) {
+	g := NewWithT(t)
+	grey := ContrastResult{Selector: "#grey", Text: "Grey", Foreground: "rgb(118, 118, 118)", Background: "rgb(255, 255, 255)", Ratio: 4.54}
+	large := grey
+	large.Selector, large.LargeText, large.Ratio = "#large", true, 3.2
+	g.Expect(grey.Passes("AA")).To(BeTrue())
+	g.Expect(grey.Passes("AAA")).To(BeFalse())
+	g.Expect(large.Passes("AA")).To(BeTrue())
+	g.Expect(large.Passes("AAA")).To(BeFalse())
+	g.Expect(grey.Passes("nope")).To(BeFalse())
+	g.Expect(ContrastReport{grey, large}.Failing("AAA")).To(HaveLen(2))
+	g.Expect(ContrastReport{grey, large}.Failing("AA")).To(BeEmpty())
+	g.Expect(large.String()).To(Equal(`#large "Grey": rgb(118, 118, 118) on rgb(255, 255, 255) is 3.20:1 (large text)`))
+}
+
+func TestFormatContrastRatioNeverRoundsUp(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(formatContrastRatio(4.499)).To(Equal("4.49"))
+	g.Expect(formatContrastRatio(4.35)).To(Equal("4.35"))
+	g.Expect(formatContrastRatio(21)).To(Equal("21.00"))
+}
+
+func TestWorstContrastFindsTheWorstSample(t *testing.T) {
+	g := NewWithT(t)
+	img := image.NewNRGBA(image.Rect(0, 0, 96, 96))
+	for y := 0; y < 96; y++ {
+		for x := 0; x < 96; x++ {
+			v := uint8(255 - y*2)
+			img.SetNRGBA(x, y, color.NRGBA{v, v, v, 255})
+		}
+	}
+	pixels := samplePixels(img, 48)
+	g.Expect(pixels).To(HaveLen(48 * 48))
+	text, bg, ratio := worstContrast(black, pixels)
+	g.Expect(text).To(Equal(black))
+	g.Expect(bg[0]).To(BeNumerically("<", 70), "the darkest rows are at the bottom")
+	g.Expect(ratio).To(Equal(contrastRatio(black, bg)))
+}
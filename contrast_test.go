@@ -0,0 +1,91 @@
+package biloba_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/onsi/biloba"
+)
+
+var _ = Describe("Colour contrast", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/contrast.html")
+		Eventually("#region").Should(b.Exist())
+	})
+
+	Describe("HaveSufficientContrast", func() {
+		It("passes text that meets the level and fails text that does not", func() {
+			Expect("#good").To(b.HaveSufficientContrast("AAA"))
+			Expect("#faint").NotTo(b.HaveSufficientContrast("AA"))
+		})
+
+		It("holds large text to the lower bar", func() {
+			Expect("#large").To(b.HaveSufficientContrast("AA"))
+			Expect("#large").To(b.HaveSufficientContrast("AAA"))
+		})
+
+		It("composites translucent text and nested backgrounds", func() {
+			var result biloba.ContrastResult
+			Expect("#on-card").To(b.HaveSufficientContrast("AAA").Capture(&result))
+			Expect(result.Foreground).To(Equal("rgb(238, 238, 238)"))
+			Expect(result.Background).To(Equal("rgb(34, 34, 34)"))
+
+			Expect("#translucent").NotTo(b.HaveSufficientContrast("AA"))
+			Expect(b.ContrastReport("#translucent")[0].Ratio).To(BeNumerically("~", 3.98, 0.05))
+
+			Expect("#faded").NotTo(b.HaveSufficientContrast("AA"), "the opacity of the element with the opaque background counts too")
+		})
+
+		It("samples a screenshot when the background is an image", func() {
+			Expect("#on-gradient").NotTo(b.HaveSufficientContrast("AA"))
+			report := b.ContrastReport("#gradient")
+			Expect(report).To(HaveLen(1))
+			Expect(report[0].Sampled).To(BeTrue())
+			Expect(report[0].Ratio).To(BeNumerically("<", 1.5))
+			Expect("#on-gradient").To(b.HaveComputedStyle("color", "rgb(0, 0, 0)"), "the text is put back after sampling")
+		})
+
+		It("sees the dark color scheme", func() {
+			Expect("#themed").To(b.HaveSufficientContrast("AA"))
+			b.EmulateMedia(biloba.MediaFeatures{ColorScheme: "dark"})
+			Expect("#themed").NotTo(b.HaveSufficientContrast("AA"))
+		})
+
+		It("polls until the contrast is sufficient", func() {
+			b.Run("fixLater()")
+			Eventually("#fixable").Should(b.HaveSufficientContrast("AA"))
+		})
+
+		It("fails with both colours and the ratio", func() {
+			var failure string
+			g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+			g.Expect("#faint").To(b.HaveSufficientContrast("AA"))
+			Expect(failure).To(HavePrefix("Expected #faint to have AA contrast - at least 4.50:1 for normal text - but rgb(153, 153, 153) on rgb(255, 255, 255) is 2.8"))
+		})
+
+		It("fails on an unknown level", func() {
+			b.HaveSufficientContrast("A+")
+			ExpectFailures(ContainSubstring(`unknown contrast level "A+"`))
+		})
+	})
+
+	Describe("ContrastReport", func() {
+		It("measures every rendered element with text in the region", func() {
+			report := b.ContrastReport("#region")
+			Expect(report).To(HaveLen(5))
+			Expect(report.Failing("AA")).To(ConsistOf(
+				HaveField("Selector", "#faint"),
+				HaveField("Selector", "#translucent"),
+			))
+			Expect(report.Failing("AAA")).To(HaveLen(2), "large text only needs 4.5:1 for AAA")
+		})
+
+		It("renders one result per line", func() {
+			Expect(b.ContrastReport("#good").String()).To(MatchRegexp(`^#good "Readable": rgb\(51, 51, 51\) on rgb\(255, 255, 255\) is 12\.6\d:1\n$`))
+		})
+
+		It("covers the whole page when given the tab", func() {
+			Expect(b.ContrastReport(b)).To(ContainElement(HaveField("Selector", "#themed")))
+		})
+	})
+})
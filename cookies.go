@@ -191,10 +191,11 @@ func (b *Biloba) ClearCookies() {
 	}
 }
 
-// resetBrowsingState clears cookies, web storage and any leaked prefers-color-scheme emulation so
-// the reusable root tab starts each spec from a clean slate. It is called from Prepare() and is
-// best-effort: errors are ignored rather than failing the spec, since this runs on the critical
-// between-specs path.
+// resetBrowsingState clears cookies, web storage, any emulated media (EmulateMedia or a leaked
+// screenshot variant), any leaked fake clock, any CPU throttling and any cross-origin frame sessions
+// a spec left behind so the reusable root tab starts each spec from a clean slate. It is called
+// from Prepare() and is best-effort: errors are ignored rather than failing the spec, since this
+// runs on the critical between-specs path.
 //
 // Cookies are cleared at the browser-context level, so this is origin-agnostic. Local and
 // session storage are origin-scoped, so we clear the current origin's storage via JS while
@@ -206,7 +207,10 @@ func (b *Biloba) resetBrowsingState() {
 		return storage.ClearCookies().WithBrowserContextID(b.browserContextID).Do(ctx)
 	})
 	b.RunErr(`try { window.localStorage.clear(); window.sessionStorage.clear(); } catch (e) {}`)
-	b.clearLeakedColorSchemeEmulation()
+	b.clearLeakedMediaEmulation()
+	b.uninstallLeakedClock()
+	b.resetCPUThrottle()
+	b.detachFrameTargets()
 }
 
 // runWithBrowserExecutor runs f against the browser-level CDP executor (as opposed to the
@@ -21,12 +21,18 @@ type bilobaJSResponse struct {
 	// doesn't report it (the each()/snapshot family).  Purely diagnostic - it feeds the poll-trajectory
 	// recorder's detached-node signal; nothing branches on it.
 	Found *bool `json:"found"`
+	// Gone reports that the selector is an ElementHandle whose node has left the page.  It will never
+	// match again, so the error stops a poll rather than waiting out its timeout.
+	Gone bool `json:"gone"`
 }
 
 func (r *bilobaJSResponse) Error() error {
 	if r.Err == "" {
 		return nil
 	}
+	if r.Gone {
+		return gomega.StopTrying(r.Err)
+	}
 	return errors.New(r.Err)
 }
 func (r *bilobaJSResponse) MatcherResult() (bool, error) { return r.Success, r.Error() }
@@ -36,14 +42,16 @@ func (r *bilobaJSResponse) ResultBool() bool             { return toBool(r.Resul
 func (r *bilobaJSResponse) ResultStringSlice() []string  { return toStringSlice(r.Result) }
 func (r *bilobaJSResponse) ResultAnySlice() []any        { return toAnySlice(r.Result) }
 
-// encodeSelector turns a CSS string, an XPath, or a "/..."-prefixed string into the
-// "s"/"x"-prefixed form that biloba.js's sel()/selEach() expect.
+// encodeSelector turns a CSS string, an XPath, a Locator, an ElementHandle, or a "/..."-prefixed
+// string into the "s"/"x"/"a"/"p"-prefixed form that biloba.js's sel()/selEach() expect.
 func encodeSelector(selector any) (string, error) {
 	switch x := selector.(type) {
 	case XPath:
 		return "x" + string(x), nil
 	case Locator:
 		return x.encode()
+	case ElementHandle:
+		return x.encode(), nil
 	case string:
 		if x[0] == '/' {
 			return "x" + x, nil
@@ -64,13 +72,17 @@ func (b *Biloba) runBilobaHandler(name string, selector any, args ...any) *bilob
 	}
 	parameters := []any{encoded}
 	parameters = append(parameters, args...)
-	_, err = b.RunErr(b.JSFunc("_biloba."+name).Invoke(parameters...), result)
+	if err := b.resolveAXQueries(parameters...); err != nil {
+		result.Err = err.Error()
+		return result
+	}
+	_, err = b.RunErr(b.bilobaInvocation(name, parameters...), result)
 	if err != nil {
 		result.Err = err.Error()
 	}
 	if result.Found != nil {
 		// one lock + a few comparisons per DOM op, and only when trajectory recording is on
-		b.recordMatch(encoded, fmt.Sprintf("%v", selector), *result.Found)
+		b.recordMatch(encoded, selector, *result.Found)
 	}
 	return result
 }
@@ -87,7 +99,11 @@ func (b *Biloba) runBilobaHandlerAsync(name string, selector any, args ...any) *
 	}
 	parameters := []any{encoded}
 	parameters = append(parameters, args...)
-	_, err = b.runErr(b.JSFunc("_biloba."+name).Invoke(parameters...), true, result)
+	if err := b.resolveAXQueries(parameters...); err != nil {
+		result.Err = err.Error()
+		return result
+	}
+	_, err = b.runErr(b.bilobaInvocation(name, parameters...), true, result)
 	if err != nil {
 		result.Err = err.Error()
 	}
@@ -997,6 +1013,7 @@ Biloba rationalizes the behavior of all input, select, and textarea elements so
 	tab.GetValue("input[type='radio']") // will be the value attribute of the selected radio button in the name group associated with the selected element
 	tab.GetValue("select") // will be the value of the selected option of the select element
 	tab.GetValue("select.multi-select") // will be a []string of values for all the selected options of the multiple select element
+	tab.GetValue("#editor") // will be the text of a contenteditable editor, one line per paragraph
 
 GetValue polls by default: it waits until an element matching selector is present, then returns its value.  An empty string (or an unselected radio group's "") is a valid value - GetValue does not wait for the value to become non-empty.  Configure the wait with WithTimeout/WithPolling/WithContext, or opt into act-once/fail-fast with Immediate().
 
@@ -1108,34 +1125,34 @@ When invoked with just one argument, tab.SetValue returns a Gomega matcher that
 
 the types you provide `SetValue` will depend on the type of input you are addressing.  See [Biloba.GetValue] for examples.
 
+A contenteditable editor - ProseMirror, Lexical and the like - ignores a value set behind its back, so SetValue selects everything in the editor and types the string over it, as [Biloba.Type] does: the editor sees the beforeinput and input events of real text entry.
+
 Read https://onsi.github.io/biloba/#working-with-the-dom to learn more about selectors and handling the DOM
 Read https://onsi.github.io/biloba/#form-elements to learn more about working with form elements
 */
 func (b *Biloba) SetValue(args ...any) types.GomegaMatcher {
 	b.gt.Helper()
 	if len(args) == 2 {
-		value := args[1]
-		var matcher types.GomegaMatcher
-		if b.realistic {
-			matcher = gcustom.MakeMatcher(func(selector any) (bool, error) {
-				return b.realisticSetValue(selector, value)
-			}).WithMessage("be value-settable (realistically)")
-		} else {
-			matcher = gcustom.MakeMatcher(func(selector any) (bool, error) {
-				return b.runBilobaHandler("setValue", selector, value).MatcherResult()
-			}).WithMessage("be value-settable")
-		}
-		b.pollOrImmediate(args[0], matcher)
+		b.pollOrImmediate(args[0], b.setValueMatcher(args[1]))
 		return nil
 	}
 	b.guardBareMatcher("SetValue")
+	return b.setValueMatcher(args[0])
+}
+
+// setValueMatcher sets value on the element it is applied to - realistically, for a Realistic() view.
+func (b *Biloba) setValueMatcher(value any) types.GomegaMatcher {
 	if b.realistic {
 		return gcustom.MakeMatcher(func(selector any) (bool, error) {
-			return b.realisticSetValue(selector, args[0])
+			return b.realisticSetValue(selector, value)
 		}).WithMessage("be value-settable (realistically)")
 	}
 	return gcustom.MakeMatcher(func(selector any) (bool, error) {
-		return b.runBilobaHandler("setValue", selector, args[0]).MatcherResult()
+		r := b.runBilobaHandler("setValue", selector, value)
+		if r.Success && r.ResultString() == "editor" {
+			return b.setEditorValue(selector, value)
+		}
+		return r.MatcherResult()
 	}).WithMessage("be value-settable")
 }
 
@@ -0,0 +1,165 @@
+package biloba
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/chromedp/cdproto/input"
+	"github.com/chromedp/chromedp"
+	"github.com/onsi/gomega/gcustom"
+)
+
+/*
+Caret says where [Biloba.Type] puts the caret before it types: pass [AtStart], [AtEnd] or [AfterText]
+alongside the text:
+
+	b.Type("#editor", biloba.AtStart, "Dear team, ")
+	b.Type("#editor", biloba.AfterText("Regards"), ",")
+	b.Type("input.search", biloba.AtEnd, " shoes")
+
+It works for contenteditable editors and for <input> and <textarea> elements.
+
+Read https://onsi.github.io/biloba/#rich-text-editors to learn more
+*/
+type Caret struct {
+	at   string
+	text string
+}
+
+// AtStart puts the caret before the first character.
+var AtStart = Caret{at: "start"}
+
+// AtEnd puts the caret after the last character - where Type puts it in a contenteditable editor by
+// default.
+var AtEnd = Caret{at: "end"}
+
+// AfterText puts the caret right after the first occurrence of text.  Type fails if the text isn't
+// there.
+func AfterText(text string) Caret {
+	return Caret{at: "after", text: text}
+}
+
+func (c Caret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"at": c.at, "text": c.text})
+}
+
+var selectAllCaret = Caret{at: "all"}
+
+// splitCaret peels a Caret out of Type's args, returning the remaining args alongside it (nil if there
+// was none).
+func splitCaret(args []any) (rest []any, caret *Caret) {
+	for _, a := range args {
+		if c, ok := a.(Caret); ok {
+			caret = &c
+		} else {
+			rest = append(rest, a)
+		}
+	}
+	return rest, caret
+}
+
+// placeCaret focuses the contenteditable editor selector stands for and places its caret, reporting
+// whether it is an editor at all.  For an <input> or <textarea> it only places a caret it is given.
+func (b *Biloba) placeCaret(selector any, caret *Caret) (bool, error) {
+	r := b.runBilobaHandlerAsync("placeCaret", selector, caret)
+	if r.Error() != nil {
+		return false, r.Error()
+	}
+	return r.ResultBool(), nil
+}
+
+// typeIntoEditor enters payload at the caret of the focused editor: text through Input.insertText, so
+// the editor sees the beforeinput and input events of real text entry, and newlines and named [Keys]
+// as key presses.
+func (b *Biloba) typeIntoEditor(payload []any) error {
+	for _, p := range payload {
+		if k, ok := p.(Key); ok {
+			if err := b.dispatchKeys(string(k), nil); err != nil {
+				return err
+			}
+			continue
+		}
+		for i, line := range strings.Split(p.(string), "\n") {
+			if i > 0 {
+				if err := b.dispatchKeys(string(Keys.Enter), nil); err != nil {
+					return err
+				}
+			}
+			if line == "" {
+				continue
+			}
+			if err := chromedp.Run(b.Context, input.InsertText(line)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setEditorValue replaces the text of the editor selector stands for with value: it selects
+// everything in the editor and types over it.
+func (b *Biloba) setEditorValue(selector any, value any) (bool, error) {
+	if _, err := b.placeCaret(selector, &selectAllCaret); err != nil {
+		return false, err
+	}
+	payload := []any{toString(value)}
+	if value == "" {
+		payload = []any{Keys.Backspace}
+	}
+	if err := b.typeIntoEditor(payload); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+/*
+HaveEditorText(expected) is a Gomega matcher that passes once the text of the contenteditable editor
+it is applied to matches expected - a string, or a Gomega matcher:
+
+	Eventually("#editor").Should(b.HaveEditorText("Dear team,\nThe release is out."))
+	Eventually(b.ByRole("textbox")).Should(b.HaveEditorText(ContainSubstring("release")))
+
+The text is read the way the editor's user reads it: one line per paragraph (or other block), <br> as
+a line break and non-breaking spaces as spaces.  It is the value [Biloba.GetValue] and
+[Biloba.HaveValue] report for an editor; HaveEditorText also fails on an element that isn't one.  The
+selector can match the contenteditable element or the wrapper an editor library mounts it in.  It
+returns a [ValueMatcher], so you can keep the text with .Capture(&text).
+
+Read https://onsi.github.io/biloba/#rich-text-editors to learn more
+*/
+func (b *Biloba) HaveEditorText(expected any) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveEditorText")
+	return b.editorMatcher("HaveEditorText", "editorText", expected)
+}
+
+/*
+HaveEditorHTML(expected) is a Gomega matcher that passes once the inner HTML of the contenteditable
+editor it is applied to matches expected - a string, or a Gomega matcher.  Editors decorate their markup
+with classes and attributes of their own, so a matcher for the formatting you care about usually reads
+best:
+
+	Eventually("#editor").Should(b.HaveEditorHTML(ContainSubstring("<strong>release</strong>")))
+
+It returns a [ValueMatcher], so you can keep the HTML with .Capture(&html).
+
+Read https://onsi.github.io/biloba/#rich-text-editors to learn more
+*/
+func (b *Biloba) HaveEditorHTML(expected any) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveEditorHTML")
+	return b.editorMatcher("HaveEditorHTML", "editorHTML", expected)
+}
+
+func (b *Biloba) editorMatcher(name string, handler string, expected any) *ValueMatcher {
+	matcher := matcherOrEqual(expected)
+	data := map[string]any{"Name": name, "Matcher": matcher}
+	return capturableResult(gcustom.MakeMatcher(func(selector any) (bool, error) {
+		r := b.runBilobaHandler(handler, selector)
+		if r.Error() != nil {
+			return false, r.Error()
+		}
+		data["Result"] = r.ResultString()
+		return matcher.Match(data["Result"])
+	}).WithTemplate("{{.Data.Name}} for {{.Actual}}:\n{{if .Failure}}{{.Data.Matcher.FailureMessage .Data.Result}}{{else}}{{.Data.Matcher.NegatedFailureMessage .Data.Result}}{{end}}", data), data)
+}
@@ -0,0 +1,113 @@
+package biloba_test
+
+import (
+	"github.com/onsi/biloba"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rich-text editors", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/editor.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	Describe("reading an editor", func() {
+		It("reads the editor's text, one line per paragraph", func() {
+			Ω(b.GetValue("#plain")).Should(Equal("Hello brave world"))
+			Ω("#plain").Should(b.HaveValue("Hello brave world"))
+			Ω("#plain").Should(b.HaveEditorText("Hello brave world"))
+			Ω("#plain").Should(b.HaveEditorHTML(`<p>Hello <b>brave</b> world</p>`))
+		})
+
+		It("finds the editor inside the wrapper it is mounted in", func() {
+			Ω("#model-wrapper").Should(b.HaveEditorText("Once upon a time"))
+			Ω(b.ByRole("textbox").WithName("Story")).Should(b.HaveValue("Once upon a time"))
+		})
+
+		It("captures what it read", func() {
+			var html string
+			Ω("#model").Should(b.HaveEditorHTML(ContainSubstring("<p>")).Capture(&html))
+			Ω(html).Should(Equal("<p>Once upon a time</p>"))
+		})
+
+		It("fails on an element that isn't an editor", func() {
+			matcher := b.HaveEditorText("anything")
+			_, err := matcher.Match("#heading")
+			Ω(err).Should(MatchError(ContainSubstring("DOM element is not contenteditable, and contains no contenteditable editor")))
+		})
+	})
+
+	Describe("SetValue", func() {
+		It("replaces the text through the editor's own input handling", func() {
+			b.SetValue("#model", "The end")
+			Ω("#model").Should(b.HaveEditorText("The end"))
+			Ω("#model").Should(b.HaveEditorHTML("<p>The end</p>"))
+			Ω("#log").Should(b.HaveInnerText(ContainSubstring("insertText")))
+		})
+
+		It("enters newlines as new paragraphs", func() {
+			b.SetValue("#model-wrapper", "Line one\nLine two")
+			Ω("#model").Should(b.HaveEditorHTML("<p>Line one</p><p>Line two</p>"))
+			Ω("#model").Should(b.HaveValue("Line one\nLine two"))
+			Ω("#log").Should(b.HaveInnerText(ContainSubstring("insertParagraph")))
+		})
+
+		It("clears the editor", func() {
+			b.SetValue("#model", "")
+			Ω("#model").Should(b.HaveEditorText(""))
+		})
+
+		It("works on a plain contenteditable, and realistically", func() {
+			b.SetValue("#plain", "Goodbye")
+			Ω("#plain").Should(b.HaveEditorText("Goodbye"))
+			b.Realistic().SetValue("#model", "Realistic")
+			Ω("#model").Should(b.HaveEditorText("Realistic"))
+		})
+
+		It("only takes strings", func() {
+			b.Immediate().SetValue("#model", true)
+			ExpectFailures(ContainSubstring("Contenteditable editors only accept string values"))
+		})
+	})
+
+	Describe("Type", func() {
+		It("types at the end of the editor by default", func() {
+			b.Type("#model", ", there lived")
+			Ω("#model").Should(b.HaveEditorText("Once upon a time, there lived"))
+		})
+
+		It("places the caret at the start, or after some text", func() {
+			b.Type("#model", biloba.AtStart, "Long ago. ")
+			Ω("#model").Should(b.HaveEditorText("Long ago. Once upon a time"))
+			b.Type("#model", biloba.AfterText("upon"), " a star and")
+			Ω("#model").Should(b.HaveEditorText("Long ago. Once upon a star and a time"))
+			b.Type("#plain", biloba.AfterText("Hello bra"), "vo and")
+			Ω("#plain").Should(b.HaveEditorText("Hello bravo and brave world"))
+		})
+
+		It("sends newlines and named keys as key presses", func() {
+			b.Type("#model", biloba.AtEnd, "!\nThe end", biloba.Keys.Backspace)
+			Ω("#model").Should(b.HaveEditorText("Once upon a time!\nThe en"))
+			Ω("#log").Should(b.HaveInnerText(ContainSubstring("deleteContentBackward")))
+		})
+
+		It("works through the matcher form and with a locator", func() {
+			Eventually("#model").Should(b.Type(biloba.AtStart, ">"))
+			b.Type(b.ByRole("textbox").WithName("Story"), biloba.AtEnd, "<")
+			Ω("#model").Should(b.HaveEditorText(">Once upon a time<"))
+		})
+
+		It("fails when the text to type after isn't there", func() {
+			b.Immediate().Type("#model", biloba.AfterText("dragons"), "!")
+			ExpectFailures(ContainSubstring(`cannot place the caret after "dragons": the editor's text is "Once upon a time"`))
+		})
+
+		It("places the caret in inputs too", func() {
+			b.Type("#name", biloba.AtStart, "Dr. ")
+			Ω("#name").Should(b.HaveValue("Dr. Smith"))
+			b.Type("#name", biloba.AfterText("Dr."), "!")
+			Ω("#name").Should(b.HaveValue("Dr.! Smith"))
+		})
+	})
+})
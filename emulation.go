@@ -0,0 +1,362 @@
+package biloba
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+/*
+ThrottleCPU(rate) slows this tab's renderer down by the given factor: 1 is no throttling, 4 is a 4x slowdown (a reasonable stand-in for a low-end phone).  Races that only show up on slow devices - a click that lands before a handler is attached, a spinner that is gone before anything checks for it - usually show up here too:
+
+	b.ThrottleCPU(6)
+	b.Navigate("/checkout")
+
+Throttling is a one-shot mutation on the tab and applies until it is changed again.  [Biloba.Prepare] puts the root tab back to the suite's rate ([BilobaConfigCPUThrottle], or unthrottled) before every spec.
+
+When a spec fails on a throttled tab the poll trajectory in the failure report says so, which is how you tell "the product is slow" apart from "the timeout is too tight for a 6x device".
+
+Read https://onsi.github.io/biloba/#cpu-throttling to learn more
+*/
+func (b *Biloba) ThrottleCPU(rate float64) {
+	b.gt.Helper()
+	b.guardConfig("ThrottleCPU")
+	if rate < 1 {
+		b.gt.Fatalf("ThrottleCPU: rate must be at least 1 (no throttling), got %g", rate)
+		return
+	}
+	if err := b.throttleCPU(rate); err != nil {
+		b.gt.Fatalf("Failed to throttle CPU:\n%s", err.Error())
+	}
+}
+
+// throttleCPU is the unguarded substrate behind ThrottleCPU, shared with ConnectToChrome, new tabs and
+// Prepare().  It records the rate the target is at only once Chrome has accepted it.
+func (b *Biloba) throttleCPU(rate float64) error {
+	if err := chromedp.Run(b.Context, emulation.SetCPUThrottlingRate(rate)); err != nil {
+		return err
+	}
+	if b.cpuThrottle != nil {
+		*b.cpuThrottle = rate
+	}
+	return nil
+}
+
+// resetCPUThrottle is Prepare()'s half of ThrottleCPU: like the colour-scheme override, the throttling
+// rate is a TARGET-level setting that outlives Navigate, so a spec that slowed the root tab down would
+// otherwise slow down every spec after it.  It only costs a round trip when the rate actually moved.
+func (b *Biloba) resetCPUThrottle() {
+	if b.cpuThrottle == nil || *b.cpuThrottle == b.suiteCPUThrottle() {
+		return
+	}
+	if err := b.throttleCPU(b.suiteCPUThrottle()); err != nil {
+		b.gt.Printf("Failed to reset CPU throttling during Prepare():\n  %s\n", err.Error())
+	}
+}
+
+// suiteCPUThrottle is the rate every tab starts at: BilobaConfigCPUThrottle, BILOBA_CPU_THROTTLE, or 1.
+func (b *Biloba) suiteCPUThrottle() float64 {
+	if b.root.suiteCPUThrottleRate < 1 {
+		return 1
+	}
+	return b.root.suiteCPUThrottleRate
+}
+
+// applySuiteCPUThrottle brings a freshly connected or freshly spawned tab down to the suite's rate.  A
+// no-op for the (overwhelmingly common) unthrottled suite.
+func (b *Biloba) applySuiteCPUThrottle() error {
+	if b.suiteCPUThrottle() == 1 {
+		return nil
+	}
+	return retryTransientCDP(func() error {
+		return b.throttleCPU(b.suiteCPUThrottle())
+	})
+}
+
+// cpuThrottleNote annotates failure-report titles for a throttled tab, so that a poll that timed out
+// under a 6x slowdown doesn't read like one that timed out on a fast machine.
+func (b *Biloba) cpuThrottleNote() string {
+	if b.cpuThrottle == nil || *b.cpuThrottle <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(" (CPU throttled %gx)", *b.cpuThrottle)
+}
+
+/*
+MediaOption is what [Biloba.EmulateMedia] and [Biloba.InMediaVariants] take: a [MediaFeatures] value, or a media type such as [PrintMedia].
+*/
+type MediaOption interface {
+	applyMedia(*mediaEmulation)
+}
+
+/*
+MediaFeatures names the CSS media features to emulate.  Each field is the value the corresponding media query should see; an empty field is left alone:
+
+	b.EmulateMedia(biloba.MediaFeatures{ReducedMotion: "reduce", ForcedColors: "active", Contrast: "more"})
+
+Read https://onsi.github.io/biloba/#emulating-media-features to learn more
+*/
+type MediaFeatures struct {
+	// ColorScheme is prefers-color-scheme: "light" or "dark"
+	ColorScheme string
+	// ReducedMotion is prefers-reduced-motion: "reduce" or "no-preference"
+	ReducedMotion string
+	// ForcedColors is forced-colors: "active" or "none"
+	ForcedColors string
+	// Contrast is prefers-contrast: "more", "less", "custom" or "no-preference"
+	Contrast string
+	// ReducedTransparency is prefers-reduced-transparency: "reduce" or "no-preference"
+	ReducedTransparency string
+}
+
+func (f MediaFeatures) applyMedia(m *mediaEmulation) {
+	for i, value := range f.values() {
+		if value != "" {
+			*m.features.field(i) = value
+		}
+	}
+}
+
+// mediaFeatureNames are the CSS names of MediaFeatures' fields, in field order.  The order is also the
+// order they appear in labels and baseline filenames, so it is part of the on-disk format.
+var mediaFeatureNames = []string{"prefers-color-scheme", "prefers-reduced-motion", "forced-colors", "prefers-contrast", "prefers-reduced-transparency"}
+
+func (f *MediaFeatures) values() []string {
+	return []string{f.ColorScheme, f.ReducedMotion, f.ForcedColors, f.Contrast, f.ReducedTransparency}
+}
+
+func (f *MediaFeatures) field(i int) *string {
+	return []*string{&f.ColorScheme, &f.ReducedMotion, &f.ForcedColors, &f.Contrast, &f.ReducedTransparency}[i]
+}
+
+type mediaType string
+
+func (t mediaType) applyMedia(m *mediaEmulation) { m.media = string(t) }
+
+/*
+PrintMedia is a [MediaOption] that emulates the print media type, so @media print stylesheets apply:
+
+	b.EmulateMedia(biloba.PrintMedia())
+
+Read https://onsi.github.io/biloba/#emulating-media-features to learn more
+*/
+func PrintMedia() MediaOption { return mediaType("print") }
+
+/*
+ScreenMedia is a [MediaOption] that emulates the screen media type - what the page sees when nothing is emulated.
+*/
+func ScreenMedia() MediaOption { return mediaType("screen") }
+
+// mediaEmulation is one complete emulated-media state for a target: a media type and a set of media
+// features.  The zero value is "nothing emulated".
+type mediaEmulation struct {
+	media    string
+	features MediaFeatures
+}
+
+func newMediaEmulation(options ...MediaOption) mediaEmulation {
+	m := mediaEmulation{}
+	for _, option := range options {
+		if option != nil {
+			option.applyMedia(&m)
+		}
+	}
+	return m
+}
+
+func (m mediaEmulation) isZero() bool { return m == mediaEmulation{} }
+
+// over layers a screenshot variant on top of what the tab already emulates, so that a spec that called
+// EmulateMedia(ReducedMotion) and then asserts InColorSchemes("dark") captures reduced-motion dark
+// mode rather than silently dropping the reduced motion for the capture.
+func (m mediaEmulation) over(base mediaEmulation) mediaEmulation {
+	out := base
+	if m.media != "" {
+		out.media = m.media
+	}
+	m.features.applyMedia(&out)
+	return out
+}
+
+func (m mediaEmulation) params() *emulation.SetEmulatedMediaParams {
+	params := emulation.SetEmulatedMedia()
+	if m.media != "" {
+		params = params.WithMedia(m.media)
+	}
+	features := []*emulation.MediaFeature{}
+	for i, value := range m.features.values() {
+		if value != "" {
+			features = append(features, &emulation.MediaFeature{Name: mediaFeatureNames[i], Value: value})
+		}
+	}
+	if len(features) > 0 {
+		params = params.WithFeatures(features)
+	}
+	return params
+}
+
+// describe renders the emulation the way a failure message names it: "prefers-color-scheme: dark",
+// "forced-colors: active, media: print".
+func (m mediaEmulation) describe() string {
+	parts := []string{}
+	for i, value := range m.features.values() {
+		if value != "" {
+			parts = append(parts, mediaFeatureNames[i]+": "+value)
+		}
+	}
+	if m.media != "" {
+		parts = append(parts, "media: "+m.media)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// suffix is what a variant appends to a baseline's filename.  A colour scheme on its own is just the
+// scheme - home-dark.png, the name InColorSchemes has always written - and everything else spells out
+// the feature so two variants can never collide: home-forced-colors-active.png, home-print.png.
+func (m mediaEmulation) suffix() string {
+	if m.isZero() {
+		return ""
+	}
+	if m.media == "" && m.features == (MediaFeatures{ColorScheme: m.features.ColorScheme}) {
+		return m.features.ColorScheme
+	}
+	parts := []string{}
+	for i, value := range m.features.values() {
+		if value == "" {
+			continue
+		}
+		if i == 0 {
+			parts = append(parts, value)
+		} else {
+			parts = append(parts, strings.TrimPrefix(mediaFeatureNames[i], "prefers-")+"-"+value)
+		}
+	}
+	if m.media != "" {
+		parts = append(parts, m.media)
+	}
+	return strings.Join(parts, "-")
+}
+
+// mediaState tracks the emulated media on a TARGET.  tab is what EmulateMedia asked for - what a
+// screenshot variant is layered on and restored to afterwards.  emulated records whether the target
+// currently carries any override at all: emulation.SetEmulatedMedia is a target-level override that
+// outlives Navigate, so Prepare() clears it, but only when this says there is something to clear
+// (Prepare runs before every spec and an unconditional CDP round trip there is not free).  It lives
+// behind a pointer so the shallow clone-with-a-flag views (Realistic() and friends) share the tab's one
+// state instead of each getting a copy that the tab never sees.
+type mediaState struct {
+	tab      mediaEmulation
+	emulated bool
+}
+
+/*
+EmulateMedia(options...) emulates CSS media features and/or a media type on this tab, replacing whatever was emulated before:
+
+	b.EmulateMedia(biloba.MediaFeatures{ReducedMotion: "reduce", ForcedColors: "active", Contrast: "more"})
+	b.EmulateMedia(biloba.PrintMedia())
+	b.EmulateMedia(biloba.MediaFeatures{ColorScheme: "dark"}, biloba.PrintMedia())
+	b.EmulateMedia() // back to what the browser reports on its own
+
+The emulation survives navigation and applies until it is changed again; [Biloba.Prepare] clears it on the root tab before every spec.  A [Biloba.HaveScreenshot] variant ([Biloba.InColorSchemes], [Biloba.InMediaVariants]) is layered on top of it for the duration of the capture.
+
+Read https://onsi.github.io/biloba/#emulating-media-features to learn more
+*/
+func (b *Biloba) EmulateMedia(options ...MediaOption) {
+	b.gt.Helper()
+	b.guardConfig("EmulateMedia")
+	m := newMediaEmulation(options...)
+	if err := b.emulateMedia(m); err != nil {
+		b.gt.Fatalf("Failed to emulate media:\n%s", err.Error())
+		return
+	}
+	b.media.tab = m
+}
+
+// emulateMedia puts this target's emulated media in state m; the zero m clears the override.  It keeps
+// b.media.emulated honest so Prepare() knows whether there is anything to clean up: the flag goes up
+// BEFORE the command that sets the override (a command that reports an error may still have landed)
+// and only comes down when a clear actually succeeds.
+func (b *Biloba) emulateMedia(m mediaEmulation) error {
+	ctx, cancel := b.waitingContext(screenshotCaptureTimeout)
+	defer cancel()
+	if !m.isZero() {
+		b.media.emulated = true
+	}
+	err := chromedp.Run(ctx, m.params())
+	if m.isZero() && err == nil {
+		b.media.emulated = false
+	}
+	return err
+}
+
+// clearLeakedMediaEmulation is Prepare()'s half of the emulated-media teardown, called from
+// resetBrowsingState.  It clears both what a spec asked for with EmulateMedia and what a screenshot
+// variant left behind when its deferred restore did not land (an expired capture timeout, a cancelled
+// context) - either would otherwise leave every later spec in this process rendering in dark mode, or
+// print mode, with nothing to show for it.  Gated on the flag rather than run unconditionally: in the
+// overwhelming case there is no override and this costs nothing.
+func (b *Biloba) clearLeakedMediaEmulation() {
+	b.media.tab = mediaEmulation{}
+	if !b.media.emulated {
+		return
+	}
+	if err := b.emulateMedia(mediaEmulation{}); err != nil {
+		b.gt.Printf("Failed to clear leaked media emulation during Prepare():\n  %s\n", err.Error())
+	}
+}
+
+/*
+EmulatePageHidden() makes the current page behave as though the user switched to another tab: the window loses focus (blur fires on window and on the focused element, and document.hasFocus() returns false), then document.visibilityState becomes "hidden" and visibilitychange fires:
+
+	b.EmulatePageHidden()
+	Eventually("#sync-status").Should(b.HaveInnerText("Paused"))
+	b.EmulatePageVisible()
+	Eventually(".toast").Should(b.HaveInnerText("Welcome back!"))
+
+The emulation lives in the current document - a navigation starts visible and focused again - and only the page's view of its state changes: Chrome keeps running the page's timers and animation frames at full speed.
+
+Read https://onsi.github.io/biloba/#page-visibility-and-focus to learn more
+*/
+func (b *Biloba) EmulatePageHidden() {
+	b.gt.Helper()
+	b.guardConfig("EmulatePageHidden")
+	b.emulatePageState("EmulatePageHidden", true, false)
+}
+
+/*
+EmulatePageVisible() undoes [Biloba.EmulatePageHidden] and [Biloba.EmulateWindowBlur]: the page becomes visible (visibilitychange fires) and then regains focus (focus fires on window and on the focused element), just as it would when the user switches back to the tab.  It is a no-op on a page that is already visible and focused.
+
+Read https://onsi.github.io/biloba/#page-visibility-and-focus to learn more
+*/
+func (b *Biloba) EmulatePageVisible() {
+	b.gt.Helper()
+	b.guardConfig("EmulatePageVisible")
+	b.emulatePageState("EmulatePageVisible", false, true)
+}
+
+/*
+EmulateWindowBlur() makes the window lose focus while the page stays visible - the user clicked into another application, say.  blur fires on window and on the focused element, and document.hasFocus() returns false; document.activeElement is left alone, as it is in a real browser.  Call [Biloba.EmulatePageVisible] to give the focus back.
+
+Read https://onsi.github.io/biloba/#page-visibility-and-focus to learn more
+*/
+func (b *Biloba) EmulateWindowBlur() {
+	b.gt.Helper()
+	b.guardConfig("EmulateWindowBlur")
+	b.emulatePageState("EmulateWindowBlur", nil, false)
+}
+
+// emulatePageState drives biloba.js's emulatePageState.  hidden is an any rather than a bool because a
+// blur leaves visibility where it was: nil (null in the page) says to keep the current state.
+//
+// There is no CDP command for page visibility, and the one for focus is already spoken for:
+// applyFocusEmulation pins every tab to focused so that focus and blur events fire at all in full
+// headless Chrome.  Turning that off to fake a blur would break Blur() and every onBlur handler in the
+// same spec, so both states are faked in the page instead.
+func (b *Biloba) emulatePageState(method string, hidden any, focused bool) {
+	b.gt.Helper()
+	if r := b.runBilobaFunc("emulatePageState", hidden, focused); r.Error() != nil {
+		b.gt.Fatalf("%s failed:\n%s", method, r.Error())
+	}
+}
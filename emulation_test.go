@@ -0,0 +1,156 @@
+package biloba_test
+
+import (
+	"time"
+
+	"github.com/onsi/biloba"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Emulation", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/dom.html")
+		Eventually("#hello").Should(b.Exist())
+	})
+
+	Describe("ThrottleCPU", func() {
+		It("starts every spec unthrottled", func() {
+			Ω(b.CPUThrottleForTest()).Should(Equal(1.0))
+		})
+
+		It("throttles the tab's CPU", func() {
+			b.ThrottleCPU(4)
+			Ω(b.CPUThrottleForTest()).Should(Equal(4.0))
+			// the page still works, just more slowly
+			Eventually("#hello").Should(b.HaveInnerText("Hello Biloba!"))
+		})
+
+		It("is shared by the clone-with-a-flag views", func() {
+			b.Realistic().ThrottleCPU(2)
+			Ω(b.CPUThrottleForTest()).Should(Equal(2.0))
+		})
+
+		It("rejects a rate below 1", func() {
+			b.ThrottleCPU(0.5)
+			ExpectFailures(ContainSubstring("ThrottleCPU: rate must be at least 1 (no throttling), got 0.5"))
+		})
+
+		It("rejects poll-config knobs", func() {
+			b.WithTimeout(time.Second).ThrottleCPU(2)
+			ExpectFailures(ContainSubstring("ThrottleCPU does not support WithTimeout"))
+		})
+
+		It("applies the suite's rate to every tab", func() {
+			throttled := biloba.ConnectToChrome(gt, biloba.BilobaConfigCPUThrottle(3))
+			Ω(throttled.CPUThrottleForTest()).Should(Equal(3.0))
+			Ω(throttled.NewTab().CPUThrottleForTest()).Should(Equal(3.0))
+
+			throttled.ThrottleCPU(6)
+			throttled.Prepare()
+			Ω(throttled.CPUThrottleForTest()).Should(Equal(3.0))
+		})
+
+		It("rejects a suite rate below 1", func() {
+			biloba.ConnectToChrome(gt, biloba.BilobaConfigCPUThrottle(0.5))
+			ExpectFailures(ContainSubstring("BilobaConfigCPUThrottle must be a number of at least 1, got 0.5"))
+		})
+	})
+
+	Describe("EmulateMedia", func() {
+		matches := func(query string) bool {
+			var result bool
+			b.Run(b.JSFunc("(query) => window.matchMedia(query).matches").Invoke(query), &result)
+			return result
+		}
+
+		It("emulates media features", func() {
+			b.EmulateMedia(biloba.MediaFeatures{ReducedMotion: "reduce", ForcedColors: "active", Contrast: "more"})
+			Ω(matches("(prefers-reduced-motion: reduce)")).Should(BeTrue())
+			Ω(matches("(forced-colors: active)")).Should(BeTrue())
+			Ω(matches("(prefers-contrast: more)")).Should(BeTrue())
+		})
+
+		It("emulates the print media type", func() {
+			b.EmulateMedia(biloba.PrintMedia())
+			Ω(matches("print")).Should(BeTrue())
+			Ω(matches("screen")).Should(BeFalse())
+		})
+
+		It("replaces the previous emulation, and clears it when called with nothing", func() {
+			b.EmulateMedia(biloba.MediaFeatures{ReducedMotion: "reduce"})
+			b.EmulateMedia(biloba.MediaFeatures{Contrast: "more"})
+			Ω(matches("(prefers-reduced-motion: reduce)")).Should(BeFalse())
+			Ω(matches("(prefers-contrast: more)")).Should(BeTrue())
+
+			b.EmulateMedia()
+			Ω(matches("(prefers-contrast: more)")).Should(BeFalse())
+		})
+
+		It("survives a navigation", func() {
+			b.EmulateMedia(biloba.MediaFeatures{ReducedMotion: "reduce"})
+			b.Navigate(fixtureServer + "/dom.html")
+			Eventually("#hello").Should(b.Exist())
+			Ω(matches("(prefers-reduced-motion: reduce)")).Should(BeTrue())
+		})
+
+		It("rejects poll-config knobs", func() {
+			b.WithTimeout(time.Second).EmulateMedia(biloba.PrintMedia())
+			ExpectFailures(ContainSubstring("EmulateMedia does not support WithTimeout"))
+		})
+	})
+
+	Describe("page visibility and focus", func() {
+		BeforeEach(func() {
+			b.Navigate(fixtureServer + "/visibility.html")
+			Eventually("#hello").Should(b.Exist())
+			b.Focus("#draft")
+			b.Run("events = []")
+		})
+
+		It("hides the page the way a tab switch does, and brings it back", func() {
+			b.EmulatePageHidden()
+			Ω("#sync-status").Should(b.HaveInnerText("Paused"))
+			Ω(b.Run("document.visibilityState")).Should(Equal("hidden"))
+			Ω(b.Run("document.hasFocus()")).Should(BeFalse())
+			Ω(b.Run("events")).Should(Equal([]any{"draft:blur", "window:blur", "visibilitychange:hidden"}))
+
+			b.Run("events = []")
+			b.EmulatePageVisible()
+			Ω("#toast").Should(b.HaveInnerText("Welcome back!"))
+			Ω(b.Run("document.visibilityState")).Should(Equal("visible"))
+			Ω(b.Run("document.hasFocus()")).Should(BeTrue())
+			Ω(b.Run("events")).Should(Equal([]any{"visibilitychange:visible", "window:focus", "draft:focus"}))
+		})
+
+		It("blurs the window without hiding the page", func() {
+			b.EmulateWindowBlur()
+			Ω(b.Run("document.visibilityState")).Should(Equal("visible"))
+			Ω(b.Run("document.hasFocus()")).Should(BeFalse())
+			Ω("#draft").Should(b.BeFocused())
+			Ω(b.Run("events")).Should(Equal([]any{"draft:blur", "window:blur"}))
+
+			b.Run("events = []")
+			b.EmulatePageVisible()
+			Ω(b.Run("events")).Should(Equal([]any{"window:focus", "draft:focus"}))
+		})
+
+		It("leaves a hidden page hidden when the window is blurred", func() {
+			b.EmulatePageHidden()
+			b.EmulateWindowBlur()
+			Ω(b.Run("document.visibilityState")).Should(Equal("hidden"))
+		})
+
+		It("does nothing to a page that is already visible", func() {
+			b.EmulatePageVisible()
+			Ω(b.Run("events")).Should(BeEmpty())
+		})
+
+		It("starts a new document visible", func() {
+			b.EmulatePageHidden()
+			b.Navigate(fixtureServer + "/visibility.html")
+			Eventually("#hello").Should(b.Exist())
+			Ω(b.Run("document.visibilityState")).Should(Equal("visible"))
+		})
+	})
+})
@@ -0,0 +1,264 @@
+package biloba
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxExplainDepth bounds how deeply ExplainSelector follows a selector into the selectors it hinges on
+// (a Within scope, an And/Or operand, a Containing target...).
+const maxExplainDepth = 3
+
+/*
+SelectorExplanation is a step-by-step account of how a selector narrowed to what it matches - see
+[Biloba.ExplainSelector].
+*/
+type SelectorExplanation struct {
+	Selector string
+	Steps    []SelectorExplanationStep
+}
+
+/*
+SelectorExplanationStep is one step of a [SelectorExplanation]: what the step added to the selector,
+and how many elements matched once it had.  When a step that matches a name, a text or another value
+leaves nothing, Nearest holds the closest values on the page (NearestOf says what they are - "names",
+"texts", "labels"...).  Depth is greater than zero for the steps of a selector the outer one hinges on,
+such as a Within scope that matched nothing.
+*/
+type SelectorExplanationStep struct {
+	Description string
+	Matches     int
+	Nearest     []string
+	NearestOf   string
+	Depth       int
+}
+
+func (s SelectorExplanationStep) String() string {
+	out := fmt.Sprintf("%s → %s", s.Description, pluralizeMatches(s.Matches))
+	if len(s.Nearest) > 0 {
+		quoted := make([]string, len(s.Nearest))
+		for i, n := range s.Nearest {
+			quoted[i] = fmt.Sprintf("%q", n)
+		}
+		out += fmt.Sprintf("; nearest %s: %s", s.NearestOf, strings.Join(quoted, ", "))
+	}
+	return out
+}
+
+// Matches returns how many elements the whole selector matches: the count after its last step.
+func (e SelectorExplanation) Matches() int {
+	for i := len(e.Steps) - 1; i >= 0; i-- {
+		if e.Steps[i].Depth == 0 {
+			return e.Steps[i].Matches
+		}
+	}
+	return 0
+}
+
+func (e SelectorExplanation) String() string {
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "%s\n", e.Selector)
+	for _, step := range e.Steps {
+		fmt.Fprintf(out, "%s%s\n", strings.Repeat("  ", step.Depth+1), step)
+	}
+	return out.String()
+}
+
+func pluralizeMatches(n int) string {
+	if n == 1 {
+		return "1 match"
+	}
+	return fmt.Sprintf("%d matches", n)
+}
+
+/*
+ExplainSelector(selector) shows, step by step, how selector narrowed to what it matches right now - the
+question every selector that matches nothing raises:
+
+	fmt.Println(b.ExplainSelector(b.ByRole("button").WithName("Save").Within("#dialog")))
+
+	role=button name="Save" within(#dialog)
+	  ByRole("button") → 14 matches
+	  WithName("Save") → 0 matches; nearest names: "Save draft", "Saved"
+
+A [Locator] is walked one refinement at a time - its role (or text, label...), its name, its Within
+scope, each filter, its level and states, and its ordinal - and the explanation stops at the first step
+that leaves nothing.  Where that step matched a name or some other text, the closest values on the page
+are suggested; where it hinged on another selector that matched nothing (a Within scope, an And/Or
+operand, a Containing target), that selector is explained beneath it.  CSS selectors are explained one
+compound selector and combinator at a time, and XPaths one location step and predicate at a time.
+
+You rarely need to call it yourself: when a spec fails while polling a selector that never matched,
+Biloba attaches its explanation to the failure.  ExplainSelector is a one-shot snapshot: it does not
+poll.
+
+Read https://onsi.github.io/biloba/#explaining-a-selector to learn more
+*/
+func (b *Biloba) ExplainSelector(selector any) SelectorExplanation {
+	b.gt.Helper()
+	b.guardConfig("ExplainSelector")
+	explanation, err := b.explainSelector(selector)
+	if err != nil {
+		b.gt.Fatalf("Failed to explain selector:\n%s", err.Error())
+	}
+	return explanation
+}
+
+func (b *Biloba) explainSelector(selector any) (SelectorExplanation, error) {
+	explanation := SelectorExplanation{Selector: describeSelector(selector)}
+	err := b.appendExplanation(&explanation, selector, 0)
+	return explanation, err
+}
+
+// explainNeverMatched explains the selector this tab's current poll never matched, if it still matches
+// nothing - the explanation attachFailureArtifactsIfFailed attaches to a failure.
+func (b *Biloba) explainNeverMatched() (SelectorExplanation, bool) {
+	view, selector, ok := b.probes.neverMatched()
+	if !ok {
+		return SelectorExplanation{}, false
+	}
+	explanation, err := view.explainSelector(selector)
+	if err != nil || explanation.Matches() > 0 {
+		return SelectorExplanation{}, false
+	}
+	return explanation, true
+}
+
+// explainedStep is what biloba.js reports for one step.  CSS and XPath steps carry their own label;
+// a Locator's steps are labelled in Go, by explainStages.
+type explainedStep struct {
+	Label     string   `json:"label"`
+	Count     int      `json:"count"`
+	Nearest   []string `json:"nearest"`
+	NearestOf string   `json:"nearestOf"`
+	Anchors   *int     `json:"anchors"`
+}
+
+func (b *Biloba) appendExplanation(explanation *SelectorExplanation, selector any, depth int) error {
+	r := b.runBilobaHandler("explainSelector", selector)
+	if r.Error() != nil {
+		return r.Error()
+	}
+	steps := []explainedStep{}
+	if err := remarshal(r.Result, &steps); err != nil {
+		return err
+	}
+	var stages []explainStage
+	if l, ok := selector.(Locator); ok {
+		stages = l.explainStages()
+	}
+	for i, step := range steps {
+		description := step.Label
+		var hinges []any
+		if i < len(stages) {
+			description, hinges = stages[i].label, stages[i].hinges
+		}
+		explanation.Steps = append(explanation.Steps, SelectorExplanationStep{Description: description, Matches: step.Count, Nearest: step.Nearest, NearestOf: step.NearestOf, Depth: depth})
+		if step.Count > 0 {
+			continue
+		}
+		// a step that emptied the set because a selector it hinges on matches nothing is explained by
+		// that selector's own explanation
+		if (step.Anchors == nil || *step.Anchors == 0) && depth < maxExplainDepth {
+			for _, hinge := range hinges {
+				if err := b.appendExplanation(explanation, hinge, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		break
+	}
+	return nil
+}
+
+// explainStage labels one of the steps biloba.js's explainLocate counts, and lists the selectors the
+// step hinges on.
+type explainStage struct {
+	label  string
+	hinges []any
+}
+
+var locatorConstructors = map[string]string{"text": "ByText", "label": "ByLabel", "placeholder": "ByPlaceholder", "alttext": "ByAltText", "title": "ByTitle"}
+
+// explainStages returns the Locator's steps in the order explainLocate counts them: the base match
+// (a named role counts as two steps), Within, each filter, Level, each state and the ordinal.
+func (l Locator) explainStages() []explainStage {
+	stages := []explainStage{}
+	withName := func() string {
+		if l.nameMode == "contains" {
+			return fmt.Sprintf("WithNameContains(%q)", l.name)
+		}
+		return fmt.Sprintf("WithName(%q)", l.name)
+	}
+	switch l.by {
+	case "role":
+		stages = append(stages, explainStage{label: fmt.Sprintf("ByRole(%q)", l.role)})
+		if l.nameSet {
+			stages = append(stages, explainStage{label: withName()})
+		}
+	case "axrole":
+		label := fmt.Sprintf("ByAXRole(%q)", l.role)
+		if l.nameSet {
+			label += "." + withName()
+		}
+		stages = append(stages, explainStage{label: label})
+	case "css":
+		stages = append(stages, explainStage{label: fmt.Sprintf("ByCSS(%q)", l.value)})
+	case "testid":
+		stages = append(stages, explainStage{label: fmt.Sprintf("ByTestID(%q)", l.value)})
+	case "and", "or":
+		parts := make([]string, len(l.operands))
+		for i, o := range l.operands {
+			parts[i] = describeSelector(o)
+		}
+		label := "And"
+		if l.by == "or" {
+			label = "Or"
+		}
+		stages = append(stages, explainStage{label: fmt.Sprintf("%s(%s)", label, strings.Join(parts, ", ")), hinges: l.operands})
+	default:
+		constructor := locatorConstructors[l.by]
+		if l.valueMode == "contains" {
+			constructor += "Contains"
+		}
+		stages = append(stages, explainStage{label: fmt.Sprintf("%s(%q)", constructor, l.value)})
+	}
+	if l.withinSet {
+		stages = append(stages, explainStage{label: fmt.Sprintf("Within(%s)", describeSelector(l.within)), hinges: []any{l.within}})
+	}
+	for _, f := range l.filters {
+		not := ""
+		if f.negate {
+			not = "Not"
+		}
+		switch f.kind {
+		case "containsText":
+			stages = append(stages, explainStage{label: fmt.Sprintf("%sContainingText(%q)", not, f.value)})
+		case "contains":
+			stages = append(stages, explainStage{label: fmt.Sprintf("%sContaining(%s)", not, describeSelector(f.selector)), hinges: []any{f.selector}})
+		case "within":
+			stages = append(stages, explainStage{label: fmt.Sprintf("%sWithin(%s)", not, describeSelector(f.selector)), hinges: []any{f.selector}})
+		case "near":
+			stages = append(stages, explainStage{label: fmt.Sprintf("Near(%s, %g)", describeSelector(f.selector), f.maxPx), hinges: []any{f.selector}})
+		default:
+			stages = append(stages, explainStage{label: fmt.Sprintf("%s%s(%s)", strings.ToUpper(f.kind[:1]), f.kind[1:], describeSelector(f.selector)), hinges: []any{f.selector}})
+		}
+	}
+	if l.levelSet {
+		stages = append(stages, explainStage{label: fmt.Sprintf("Level(%d)", l.level)})
+	}
+	for _, state := range l.states {
+		stages = append(stages, explainStage{label: strings.ToUpper(state[:1]) + state[1:] + "()"})
+	}
+	if l.nthSet {
+		switch l.nth {
+		case 0:
+			stages = append(stages, explainStage{label: "First()"})
+		case -1:
+			stages = append(stages, explainStage{label: "Last()"})
+		default:
+			stages = append(stages, explainStage{label: fmt.Sprintf("Nth(%d)", l.nth)})
+		}
+	}
+	return stages
+}
@@ -0,0 +1,75 @@
+package biloba
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like a11y_locator_internal_test.go: they check how ExplainSelector labels
+// a Locator's steps and renders an explanation.  No browser.
+
+func labels(stages []explainStage) []string {
+	out := make([]string, len(stages))
+	for i, s := range stages {
+		out[i] = s.label
+	}
+	return out
+}
+
+func TestExplainStagesFollowsExplainLocate(t *testing.T) {
+	g := NewWithT(t)
+	b := &Biloba{}
+	l := b.ByRole("heading").WithNameContains("Orders").Within("#main").ContainingText("today").Level(2).Expanded().Nth(1)
+	g.Expect(labels(l.explainStages())).To(Equal([]string{
+		`ByRole("heading")`,
+		`WithNameContains("Orders")`,
+		`Within(#main)`,
+		`ContainingText("today")`,
+		`Level(2)`,
+		`Expanded()`,
+		`Nth(1)`,
+	}))
+
+	g.Expect(labels(b.ByTextContains("Save").Last().explainStages())).To(Equal([]string{`ByTextContains("Save")`, `Last()`}))
+	g.Expect(labels(b.ByAXRole("button").WithName("Go").explainStages())).To(Equal([]string{`ByAXRole("button").WithName("Go")`}))
+}
+
+func TestExplainStagesHingesOnNestedSelectors(t *testing.T) {
+	g := NewWithT(t)
+	b := &Biloba{}
+	dialog := b.ByRole("dialog")
+	stages := b.ByRole("button").Within(dialog).NotContaining(".icon").explainStages()
+	g.Expect(stages).To(HaveLen(3))
+	g.Expect(stages[0].hinges).To(BeEmpty())
+	g.Expect(stages[1].hinges).To(Equal([]any{dialog}))
+	g.Expect(stages[2].label).To(Equal("NotContaining(.icon)"))
+	g.Expect(stages[2].hinges).To(Equal([]any{".icon"}))
+
+	stages = b.ByRole("button").Or(b.ByRole("link")).explainStages()
+	g.Expect(stages).To(HaveLen(1))
+	g.Expect(stages[0].hinges).To(HaveLen(2))
+}
+
+func TestSelectorExplanationString(t *testing.T) {
+	g := NewWithT(t)
+	e := SelectorExplanation{
+		Selector: `role=button name="Save" within(role=dialog)`,
+		Steps: []SelectorExplanationStep{
+			{Description: `ByRole("button")`, Matches: 14},
+			{Description: `WithName("Save")`, Matches: 1},
+			{Description: `Within(role=dialog)`, Matches: 0},
+			{Description: `ByRole("dialog")`, Matches: 0, Depth: 1},
+		},
+	}
+	g.Expect(e.Matches()).To(Equal(0))
+	g.Expect(e.String()).To(Equal(`role=button name="Save" within(role=dialog)
+  ByRole("button") → 14 matches
+  WithName("Save") → 1 match
+  Within(role=dialog) → 0 matches
+    ByRole("dialog") → 0 matches
+`))
+
+	step := SelectorExplanationStep{Description: `ByLabel("Emial")`, Nearest: []string{"Email", "Email address"}, NearestOf: "labels"}
+	g.Expect(step.String()).To(Equal(`ByLabel("Emial") → 0 matches; nearest labels: "Email", "Email address"`))
+}
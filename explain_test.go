@@ -0,0 +1,78 @@
+package biloba_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExplainSelector", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/explain.html")
+		Eventually("#heading").Should(b.Exist())
+		b.ResetPollDiagnosticsForTest()
+	})
+
+	It("walks a locator one refinement at a time, suggesting the nearest names where it came up empty", func() {
+		explanation := b.ExplainSelector(b.ByRole("button").WithName("Save"))
+		Ω(explanation.Matches()).Should(Equal(0))
+		Ω(explanation.Steps).Should(HaveLen(2))
+		Ω(explanation.Steps[0].String()).Should(Equal(`ByRole("button") → 4 matches`))
+		Ω(explanation.Steps[1].Description).Should(Equal(`WithName("Save")`))
+		Ω(explanation.Steps[1].Nearest).Should(HaveExactElements("Saved", "Save draft", "Cancel"))
+		Ω(explanation.String()).Should(HavePrefix(`role=button name="Save"` + "\n"))
+		Ω(explanation.String()).Should(ContainSubstring(`WithName("Save") → 0 matches; nearest names: "Saved", "Save draft", "Cancel"`))
+	})
+
+	It("stops at the first step that leaves nothing", func() {
+		explanation := b.ExplainSelector(b.ByRole("button").WithName("Delete").Within("#editor").First())
+		Ω(explanation.Steps).Should(HaveLen(3))
+		Ω(explanation.Steps[2].String()).Should(Equal(`Within(#editor) → 0 matches`))
+	})
+
+	It("explains a Within scope that matched nothing beneath the step it emptied", func() {
+		explanation := b.ExplainSelector(b.ByRole("button").Within(b.ByRole("form").WithName("Settings")))
+		Ω(explanation.Steps).Should(HaveLen(4))
+		Ω(explanation.Steps[1].Description).Should(HavePrefix("Within("))
+		Ω(explanation.Steps[2]).Should(And(HaveField("Description", `ByRole("form")`), HaveField("Depth", 1)))
+		Ω(explanation.Steps[3].Depth).Should(Equal(1))
+		Ω(explanation.Matches()).Should(Equal(0))
+	})
+
+	It("reports the whole set when the selector matches", func() {
+		explanation := b.ExplainSelector(b.ByRole("button").ContainingText("Save"))
+		Ω(explanation.Matches()).Should(Equal(2))
+		Ω(explanation.Steps[1].String()).Should(Equal(`ContainingText("Save") → 2 matches`))
+	})
+
+	It("explains CSS one compound selector and combinator at a time", func() {
+		explanation := b.ExplainSelector("#editor > button.secondary")
+		Ω(explanation.Steps).Should(HaveLen(3))
+		Ω(explanation.Steps[0].String()).Should(Equal("#editor → 1 match"))
+		Ω(explanation.Steps[1].String()).Should(Equal("#editor > button → 3 matches"))
+		Ω(explanation.Steps[2].String()).Should(Equal("#editor > button.secondary → 0 matches"))
+	})
+
+	It("explains XPath one location step and predicate at a time", func() {
+		explanation := b.ExplainSelector(b.XPath("//form[@id='editor']/button[text()='Publish']"))
+		Ω(explanation.Steps).Should(HaveLen(4))
+		Ω(explanation.Steps[0].String()).Should(Equal("//form → 1 match"))
+		Ω(explanation.Steps[2].String()).Should(Equal("//form[@id='editor']/button → 3 matches"))
+		Ω(explanation.Steps[3].Matches).Should(Equal(0))
+	})
+
+	Describe("on failure", func() {
+		It("attaches an explanation when a polled selector never matched", func() {
+			b.WithTimeout(time.Millisecond*100).GetProperty(b.ByRole("button").WithName("Save"), "id")
+			ExpectFailures(ContainSubstring("Timed out after"))
+			Ω(b.NeverMatchedExplanationForTest()).Should(ContainSubstring(`WithName("Save") → 0 matches; nearest names: "Saved"`))
+		})
+
+		It("stays quiet when the selector matched", func() {
+			b.WithTimeout(time.Millisecond*100).GetProperty("#heading", "neverDefined")
+			ExpectFailures(ContainSubstring("Timed out after"))
+			Ω(b.NeverMatchedExplanationForTest()).Should(BeEmpty())
+		})
+	})
+})
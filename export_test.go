@@ -112,11 +112,37 @@ func (b *Biloba) DetachedNodeNoteForTest() string {
 	return b.probes.renderDetachedNode()
 }
 
+// NeverMatchedExplanationForTest exposes the explanation attached to a failure whose poll never matched
+// its selector, for explain_test.go.  It is "" when there is nothing to explain.
+func (b *Biloba) NeverMatchedExplanationForTest() string {
+	explanation, ok := b.explainNeverMatched()
+	if !ok {
+		return ""
+	}
+	return explanation.String()
+}
+
 // OccludedClicksNoteForTest exposes this tab's recorded occluded-click notes for interactions_test.go.
 func (b *Biloba) OccludedClicksNoteForTest() string {
 	return b.occlusions.render()
 }
 
+// StartChurnRecorderForTest starts the document-wide mutation recorder BilobaConfigFailureMutations
+// installs, so mutations_test.go can check the failure artifact without reconfiguring the suite.
+func (b *Biloba) StartChurnRecorderForTest() {
+	b.run("_biloba.startChurnRecorder()")
+}
+
+// ChurnNoteForTest exposes the "DOM mutations during the last poll" failure artifact for
+// mutations_test.go.  It is "" when nothing churned.
+func (b *Biloba) ChurnNoteForTest() string {
+	start := b.probes.windowStart()
+	if start.IsZero() {
+		return ""
+	}
+	return b.churnReport(start)
+}
+
 // ResetPollDiagnosticsForTest exposes resetPollDiagnostics so a spec can start from a clean slate.
 func (b *Biloba) ResetPollDiagnosticsForTest() {
 	b.resetPollDiagnostics()
@@ -168,10 +194,11 @@ func ScreenshotSettleScheduleForTest() []time.Duration {
 }
 
 // EmulateColorSchemeForTest applies (or, with the empty string, clears) a prefers-color-scheme
-// override on this tab, so visual_test.go can simulate the capture whose deferred teardown never
-// landed and assert that the next Prepare() cleans it up.
+// override on this tab WITHOUT recording it as the tab's EmulateMedia state, so visual_test.go can
+// simulate the capture whose deferred teardown never landed and assert that the next Prepare() cleans
+// it up.
 func (b *Biloba) EmulateColorSchemeForTest(scheme string) error {
-	return b.emulateColorScheme(scheme)
+	return b.emulateMedia(newMediaEmulation(MediaFeatures{ColorScheme: scheme}))
 }
 
 // TruthyEnvForTest exposes truthyEnv (and its unrecognised-value warning) for visual_test.go,
@@ -199,3 +226,13 @@ type TabScreenshotForTest struct {
 	Failure          string
 	ImgcatScreenshot string
 }
+
+// CPUThrottleForTest exposes the CPU throttling rate this tab's target is currently at.
+func (b *Biloba) CPUThrottleForTest() float64 { return *b.cpuThrottle }
+
+// FrameResolutionsForTest reports how many times a Frame() view has resolved its frame, for frame_test.go.
+func (b *Biloba) FrameResolutionsForTest() int {
+	b.frame.lock.Lock()
+	defer b.frame.lock.Unlock()
+	return b.frame.resolutions
+}
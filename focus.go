@@ -0,0 +1,431 @@
+package biloba
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+)
+
+// maxTabStops bounds a tab-order walk.  Focus always cycles or leaves the scope eventually, but a page
+// that keeps adding focusable elements as you tab through it would otherwise walk forever.
+const maxTabStops = 200
+
+/*
+TabOrder(scope) presses Tab as real keyboard input, again and again, and returns where focus landed
+each time - until focus cycles back to an element it has already visited or leaves scope.  Pass a
+selector to walk one region, or the tab itself to walk the whole page:
+
+	Ω(b.TabOrder("#signup")).Should(Equal([]string{
+		`role=textbox name="Email"`,
+		`role=textbox name="Password"`,
+		`role=checkbox name="Remember me"`,
+		`role=button name="Sign up"`,
+	}))
+
+Each element is described the way a [Locator] prints: `role=... name="..."` when it has an accessible
+role and name, and `css=...` otherwise.  The walk starts just before scope, whatever had focus, and puts
+focus back where it was when it is done.
+
+TabOrder is a one-shot snapshot: it does not poll.  To compare the order against selectors - and wait
+for it - use [Biloba.HaveTabOrder].
+
+Read https://onsi.github.io/biloba/#tab-order to learn more
+*/
+func (b *Biloba) TabOrder(scope any) []string {
+	b.gt.Helper()
+	b.guardConfig("TabOrder")
+	tab, selector := tabOrderSubject(b, scope)
+	walk, err := tab.walkTabOrder(selector, nil, nil)
+	if err != nil {
+		b.gt.Fatalf("Failed to walk the tab order:\n%s", err.Error())
+		return nil
+	}
+	return walk.focused
+}
+
+/*
+HaveTabOrder(selectors...) is a Gomega matcher that passes once tabbing through the subject visits
+exactly the elements matching selectors, in order.  Apply it to a selector to walk one region, or to the
+tab to walk the whole page:
+
+	Eventually("#signup").Should(b.HaveTabOrder(
+		b.ByLabel("Email"),
+		b.ByLabel("Password"),
+		"#remember",
+		b.ByRole("button").WithName("Sign up"),
+	))
+
+Each attempt walks the tab order afresh, as [Biloba.TabOrder] does.  The failure message lists the
+order you asked for next to the order the walk found, and why the walk stopped.
+
+Read https://onsi.github.io/biloba/#tab-order to learn more
+*/
+func (b *Biloba) HaveTabOrder(selectors ...any) types.GomegaMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveTabOrder")
+	encoded := []any{}
+	expected := &strings.Builder{}
+	for i, selector := range selectors {
+		e, err := encodeSelector(selector)
+		if err != nil {
+			b.gt.Fatalf("HaveTabOrder: %s", err.Error())
+			return nil
+		}
+		encoded = append(encoded, e)
+		fmt.Fprintf(expected, "  %d. %s\n", i+1, describeSelector(selector))
+	}
+	data := map[string]any{"Expected": expected.String()}
+	return gcustom.MakeMatcher(func(actual any) (bool, error) {
+		if _, ok := actual.(*Biloba); !ok {
+			if _, err := encodeSelector(actual); err != nil {
+				return false, gomega.StopTrying(fmt.Sprintf("HaveTabOrder takes either a selector (a CSS string, an XPath, or a Locator) to walk one region, or the tab itself to walk the whole page.  Got:\n%s", format.Object(actual, 1)))
+			}
+		}
+		tab, selector := tabOrderSubject(b, actual)
+		data["Subject"] = describeSnapshotSubject(selector)
+		var matches []bool
+		walk, err := tab.walkTabOrder(selector, nil, func() error {
+			r := tab.runBilobaFunc("tabOrderMatches", encoded...)
+			if r.Error() != nil {
+				return r.Error()
+			}
+			return remarshal(r.Result, &matches)
+		})
+		if err != nil {
+			return false, err
+		}
+		data["Actual"] = renderTabOrder(walk)
+		if len(walk.focused) != len(selectors) {
+			return false, nil
+		}
+		for _, matched := range matches {
+			if !matched {
+				return false, nil
+			}
+		}
+		return true, nil
+	}).WithTemplate("{{if .Failure}}Expected the tab order of {{.Data.Subject}} to be:\n{{.Data.Expected}}but it is:\n{{.Data.Actual}}{{else}}Expected the tab order of {{.Data.Subject}} NOT to be:\n{{.Data.Expected}}but it is.{{end}}", data)
+}
+
+// tabOrderSubject resolves a tab-order scope: the tab means the whole page.
+func tabOrderSubject(b *Biloba, scope any) (*Biloba, any) {
+	if tab, ok := scope.(*Biloba); ok {
+		return tab, nil
+	}
+	return b, scope
+}
+
+// tabWalk is what walking the tab order found: each focused element's description, and why the walk
+// stopped.
+type tabWalk struct {
+	focused []string
+	stop    string
+}
+
+// walkTabOrder presses Tab (holding mods) through the scope matching selector - the whole page when it
+// is nil - until biloba.js's tabOrderStep says to stop.  inspect, when given, runs while the walk's
+// elements are still recorded in the page, before focus is put back.
+func (b *Biloba) walkTabOrder(selector any, mods []clickModifier, inspect func() error) (tabWalk, error) {
+	var r *bilobaJSResponse
+	if selector == nil {
+		r = b.runBilobaFunc("tabOrderStart")
+	} else {
+		r = b.runBilobaHandler("tabOrderStart", selector)
+	}
+	if r.Error() != nil {
+		return tabWalk{}, r.Error()
+	}
+	defer b.runBilobaFunc("tabOrderEnd")
+	walk := tabWalk{focused: []string{}}
+	if err := b.pressTabUntilStop(&walk, mods); err != nil {
+		return walk, err
+	}
+	if inspect != nil {
+		if err := inspect(); err != nil {
+			return walk, err
+		}
+	}
+	return walk, nil
+}
+
+// pressTabUntilStop drives a walk biloba.js has started, recording each stop in walk.
+func (b *Biloba) pressTabUntilStop(walk *tabWalk, mods []clickModifier) error {
+	for walk.stop == "" {
+		if len(walk.focused) >= maxTabStops {
+			walk.stop = fmt.Sprintf("stopped after %d Tab presses", maxTabStops)
+			break
+		}
+		if err := b.dispatchKeys(string(Keys.Tab), mods); err != nil {
+			return err
+		}
+		r := b.runBilobaFunc("tabOrderStep")
+		if r.Error() != nil {
+			return r.Error()
+		}
+		step := struct {
+			Focused string `json:"focused"`
+			Stop    string `json:"stop"`
+		}{}
+		if err := remarshal(r.Result, &step); err != nil {
+			return err
+		}
+		if step.Focused != "" {
+			walk.focused = append(walk.focused, step.Focused)
+		}
+		walk.stop = step.Stop
+	}
+	return nil
+}
+
+func renderTabOrder(walk tabWalk) string {
+	out := &strings.Builder{}
+	for i, focused := range walk.focused {
+		fmt.Fprintf(out, "  %d. %s\n", i+1, focused)
+	}
+	fmt.Fprintf(out, "  (then %s)\n", walk.stop)
+	return out.String()
+}
+
+// focusTrapWait is how long BeFocusTrapped gives the modal after pressing Escape - first to close, then
+// to put focus back on the invoker.  Both commonly happen a tick or a closing animation later.  The
+// matcher never sleeps through it: a sample that finds the modal still settling returns an error, and
+// the poll samples again on its own schedule.
+const focusTrapWait = 500 * time.Millisecond
+
+/*
+FocusTrapResult is what [Biloba.BeFocusTrapped] found.  Tab and ShiftTab are where each key took focus,
+starting from where it was; Leak is where focus went when it got out ("" when it never did).  Invoker is
+the element that opened the modal - the last element outside it that had focus or was clicked - and
+ClosesOnEscape, FocusAfterClose and FocusReturned report what pressing Escape did.  Elements are
+described as [Biloba.TabOrder] describes them.
+*/
+type FocusTrapResult struct {
+	Tab             []string
+	ShiftTab        []string
+	Leak            string
+	Invoker         string
+	ClosesOnEscape  bool
+	FocusAfterClose string
+	FocusReturned   bool
+
+	escapePressed bool
+	invokerHint   string
+}
+
+func (r FocusTrapResult) String() string {
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "  Tab:       %s\n", strings.Join(r.Tab, " -> "))
+	if r.ShiftTab != nil {
+		fmt.Fprintf(out, "  Shift+Tab: %s\n", strings.Join(r.ShiftTab, " -> "))
+	}
+	if !r.escapePressed {
+		return out.String()
+	}
+	switch {
+	case !r.ClosesOnEscape:
+		fmt.Fprintf(out, "  Escape:    does not close it\n")
+	case r.Invoker == "":
+		fmt.Fprintf(out, "  Escape:    closes it; focus went to %s (Biloba did not see what opened it)\n", r.FocusAfterClose)
+	case r.FocusReturned:
+		fmt.Fprintf(out, "  Escape:    closes it; focus returned to the invoker, %s\n", r.Invoker)
+	default:
+		fmt.Fprintf(out, "  Escape:    closes it; focus went to %s, not the invoker, %s%s\n", r.FocusAfterClose, r.Invoker, r.invokerHint)
+	}
+	return out.String()
+}
+
+/*
+BeFocusTrapped() is a Gomega matcher for modal dialogs.  Apply it to the modal once it is open:
+
+	b.Realistic().Click("#delete")
+	Eventually("#confirm").Should(b.BeFocusTrapped())
+
+It passes when focus is inside the modal and a keyboard user cannot get it out: pressing Tab, and then
+Shift+Tab, cycles through the modal's focusable elements without ever leaving it.  Once that holds it
+presses Escape, and reports what happens.  A modal that stays open is fine - the report says so - but a
+modal that closes has to put focus back on the element that opened it.  Biloba takes that to be the last
+element outside the modal that had focus or was clicked; a plain (non-realistic) [Biloba.Click] does not
+focus what it clicks, so open the modal with a realistic click, or [Biloba.Focus] the invoker first.
+
+It polls until focus has moved into the modal and the trap holds.  Escape is pressed once; the samples
+after it watch, for up to half a second, for the modal to close and focus to come back - so apply it
+with Eventually.  Because Escape usually closes the modal, a failure after Escape does not poll on: the
+spec fails straight away.
+
+It returns a [ValueMatcher], so you can keep the [FocusTrapResult] and check Escape yourself:
+
+	var trap biloba.FocusTrapResult
+	Eventually("#confirm").Should(b.BeFocusTrapped().Capture(&trap))
+	Ω(trap.ClosesOnEscape).Should(BeTrue())
+
+Read https://onsi.github.io/biloba/#focus-traps to learn more
+*/
+func (b *Biloba) BeFocusTrapped() *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("BeFocusTrapped")
+	data := map[string]any{}
+	// escaped is what the samples found up to pressing Escape in the modal escapedIn names: the samples
+	// after it only watch that modal settle, so Escape is never pressed twice.  It is let go once the
+	// modal settles, when the matcher is applied to another modal, and once the settle window has
+	// long run out (an Eventually that gave up mid-settle left it behind), so a reused matcher starts over.
+	var escaped *FocusTrapResult
+	var escapedIn string
+	var escapedAt time.Time
+	return capturableResult(gcustom.MakeMatcher(func(selector any) (bool, error) {
+		encoded, err := encodeSelector(selector)
+		if err != nil {
+			return false, gomega.StopTrying(fmt.Sprintf("BeFocusTrapped must be applied to a selector (a CSS string, an XPath, or a Locator) for the modal.  Got:\n%s", format.Object(selector, 1)))
+		}
+		subject := describeSnapshotSubject(selector)
+		data["Subject"] = subject
+		if escaped != nil && (escapedIn != encoded || time.Since(escapedAt) >= 2*focusTrapWait) {
+			escaped = nil
+		}
+		if escaped == nil {
+			result, problem, err := b.checkFocusTrap(selector)
+			if err != nil {
+				return false, err
+			}
+			data["Result"] = result
+			data["Problem"] = problem
+			if problem != "" {
+				return false, nil
+			}
+			escaped, escapedIn, escapedAt = &result, encoded, time.Now()
+		}
+		problem, settled, err := b.settleFocusTrap(selector, escaped, time.Since(escapedAt) >= focusTrapWait)
+		data["Result"] = *escaped
+		data["Problem"] = problem
+		if err != nil {
+			return false, err
+		}
+		if !settled {
+			return false, fmt.Errorf("%s has not settled since Biloba pressed Escape - apply BeFocusTrapped with Eventually to give it time to close", subject)
+		}
+		result := *escaped
+		escaped = nil
+		if problem != "" {
+			return false, gomega.StopTrying(fmt.Sprintf("Expected %s to trap focus, but %s\n\n%s", subject, problem, result))
+		}
+		return true, nil
+	}).WithTemplate("{{if .Failure}}Expected {{.Data.Subject}} to trap focus, but {{.Data.Problem}}{{if .Data.Result.Tab}}\n\n{{.Data.Result}}{{end}}{{else}}Expected {{.Data.Subject}} NOT to trap focus, but it does:\n\n{{.Data.Result}}{{end}}", data), data)
+}
+
+// checkFocusTrap runs BeFocusTrapped's checks up to pressing Escape, in order, stopping at the first
+// problem.  settleFocusTrap takes it from there.
+func (b *Biloba) checkFocusTrap(selector any) (FocusTrapResult, string, error) {
+	result := FocusTrapResult{}
+	for _, mods := range [][]clickModifier{nil, {modShift}} {
+		walk, outside, err := b.walkFocusTrap(selector, mods)
+		if err != nil {
+			return result, "", err
+		}
+		if walk == nil {
+			if outside == "" {
+				return result, "focus is not inside it", nil
+			}
+			return result, fmt.Sprintf("focus is not inside it - it is on %s", outside), nil
+		}
+		stops := append(walk.focused, "("+walk.stop+")")
+		if mods == nil {
+			result.Tab = stops
+		} else {
+			result.ShiftTab = stops
+		}
+		if !strings.HasPrefix(walk.stop, "focus cycled back") {
+			result.Leak = walk.stop
+			key := map[bool]string{true: "Tab", false: "Shift+Tab"}[mods == nil]
+			return result, fmt.Sprintf("%s got out of it: %s", key, walk.stop), nil
+		}
+	}
+
+	r := b.runBilobaHandler("focusTrapInvoker", selector)
+	if r.Error() != nil {
+		return result, "", r.Error()
+	}
+	invoker := struct {
+		Invoker  string `json:"invoker"`
+		HadFocus bool   `json:"hadFocus"`
+	}{}
+	if err := remarshal(r.Result, &invoker); err != nil {
+		return result, "", err
+	}
+	result.Invoker = invoker.Invoker
+	if !invoker.HadFocus {
+		result.invokerHint = " (which never had focus - a plain b.Click does not focus what it clicks)"
+	}
+
+	if err := b.dispatchKeys(string(Keys.Escape), nil); err != nil {
+		return result, "", err
+	}
+	result.escapePressed = true
+	return result, "", nil
+}
+
+// settleFocusTrap looks once at what pressing Escape did, recording it in result.  It is settled when
+// the modal has closed and focus is back on the invoker (or there is no invoker to go back to) - or,
+// once late, whatever it finds: a modal still open stays open, and focus that has not come back is
+// the problem.
+func (b *Biloba) settleFocusTrap(selector any, result *FocusTrapResult, late bool) (string, bool, error) {
+	state := focusTrapState{}
+	r := b.runBilobaHandler("focusTrapClosed", selector)
+	if r.Error() != nil {
+		return "", false, r.Error()
+	}
+	if err := remarshal(r.Result, &state); err != nil {
+		return "", false, err
+	}
+	result.ClosesOnEscape = state.Closed
+	result.FocusAfterClose, result.FocusReturned = "", false
+	if !state.Closed {
+		return "", late, nil
+	}
+	result.FocusAfterClose = state.Focused
+	if result.Invoker == "" {
+		return "", true, nil
+	}
+	result.FocusReturned = state.Returned
+	if !state.Returned {
+		return "focus did not return to the element that opened it when Escape closed it", late, nil
+	}
+	return "", true, nil
+}
+
+// walkFocusTrap walks the tab order from wherever focus is inside the modal, holding mods.  When focus
+// is not in the modal there is no walk - just a description of where focus is instead ("" for the
+// page itself).
+func (b *Biloba) walkFocusTrap(selector any, mods []clickModifier) (*tabWalk, string, error) {
+	r := b.runBilobaHandler("focusTrapStart", selector)
+	if r.Error() != nil {
+		return nil, "", r.Error()
+	}
+	start := struct {
+		Start   string `json:"start"`
+		Outside string `json:"outside"`
+	}{}
+	if err := remarshal(r.Result, &start); err != nil {
+		return nil, "", err
+	}
+	if start.Start == "" {
+		return nil, start.Outside, nil
+	}
+	defer b.runBilobaFunc("tabOrderEnd")
+	walk := &tabWalk{focused: []string{start.Start}}
+	if err := b.pressTabUntilStop(walk, mods); err != nil {
+		return nil, "", err
+	}
+	return walk, "", nil
+}
+
+// focusTrapState is biloba.js's focusTrapClosed: whether the modal has closed, where focus is, and
+// whether that is the invoker.
+type focusTrapState struct {
+	Closed   bool   `json:"closed"`
+	Focused  string `json:"focused"`
+	Returned bool   `json:"returned"`
+}
@@ -0,0 +1,176 @@
+package biloba_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/onsi/biloba"
+)
+
+var _ = Describe("Tab order", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/focus.html")
+		Eventually("#signup").Should(b.Exist())
+	})
+
+	Describe("TabOrder", func() {
+		It("records where each Tab lands until focus leaves the scope", func() {
+			Expect(b.TabOrder("#signup")).To(Equal([]string{
+				`role=textbox name="Email"`,
+				`role=textbox name="Password"`,
+				`role=checkbox name="Remember me"`,
+				`css=#signup > div`,
+				`role=button name="Sign up"`,
+			}))
+		})
+
+		It("walks the whole page when given the tab", func() {
+			order := b.TabOrder(b)
+			Expect(order).To(HaveLen(9))
+			Expect(order[0]).To(Equal(`role=link name="Skip to content"`))
+			Expect(order[8]).To(Equal(`role=textbox name="Parked"`))
+		})
+
+		It("returns nothing for a scope with nothing to focus", func() {
+			Expect(b.TabOrder("#empty")).To(BeEmpty())
+		})
+
+		It("puts focus back where it was", func() {
+			b.Focus("#parked")
+			b.TabOrder("#signup")
+			Expect("#parked").To(b.BeFocused())
+		})
+
+		It("fails when the scope is missing", func() {
+			b.TabOrder("#nope")
+			ExpectFailures(ContainSubstring("could not find DOM element matching selector: #nope"))
+		})
+	})
+
+	Describe("HaveTabOrder", func() {
+		It("matches the order against selectors of any kind", func() {
+			Expect("#signup").To(b.HaveTabOrder(
+				b.ByLabel("Email"),
+				"#password",
+				b.XPath("//input[@id='remember']"),
+				".chip",
+				b.ByRole("button").WithName("Sign up"),
+			))
+			Expect("#signup").NotTo(b.HaveTabOrder("#email", "#password"))
+		})
+
+		It("polls until the order is right", func() {
+			Expect("#shuffled").NotTo(b.HaveTabOrder("#second", "#first"))
+			b.Run("unshuffleLater()")
+			Eventually("#shuffled").Should(b.HaveTabOrder("#second", "#first"))
+		})
+
+		It("fails with both orders and why the walk stopped", func() {
+			var failure string
+			g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+			g.Expect("#shuffled").To(b.HaveTabOrder("#second", "#first"))
+			Expect(failure).To(Equal("Expected the tab order of #shuffled to be:\n  1. #second\n  2. #first\nbut it is:\n  1. role=button name=\"First\"\n  2. role=button name=\"Second\"\n  (then focus left the scope for role=textbox name=\"Parked\")\n"))
+		})
+	})
+})
+
+var _ = Describe("Focus traps", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/focus_trap.html")
+		Eventually("#open-good").Should(b.Exist())
+	})
+
+	open := func(id string) {
+		b.Focus("#open-" + id)
+		b.Click("#open-" + id)
+	}
+
+	It("passes a modal that traps focus and gives it back on Escape", func() {
+		open("good")
+		var trap biloba.FocusTrapResult
+		Eventually("#good").Should(b.BeFocusTrapped().Capture(&trap))
+		Expect(trap.Tab).To(Equal([]string{`role=button name="Cancel"`, `role=button name="Delete"`, `(focus cycled back to role=button name="Cancel")`}))
+		Expect(trap.ShiftTab).To(Equal([]string{`role=button name="Cancel"`, `role=button name="Delete"`, `(focus cycled back to role=button name="Cancel")`}))
+		Expect(trap.Invoker).To(Equal(`role=button name="Delete file"`))
+		Expect(trap.ClosesOnEscape).To(BeTrue())
+		Expect(trap.FocusReturned).To(BeTrue())
+		Expect("#open-good").To(b.BeFocused())
+	})
+
+	It("reports a modal that Escape does not close without failing", func() {
+		open("sticky")
+		var trap biloba.FocusTrapResult
+		Eventually("#sticky").Should(b.BeFocusTrapped().Capture(&trap))
+		Expect(trap.ClosesOnEscape).To(BeFalse())
+		Expect(trap.String()).To(ContainSubstring("Escape:    does not close it"))
+	})
+
+	It("waits for a modal that closes a moment after Escape, without pressing Escape again", func() {
+		open("slow")
+		var trap biloba.FocusTrapResult
+		Eventually("#slow").Should(b.BeFocusTrapped().Capture(&trap))
+		Expect(trap.ClosesOnEscape).To(BeTrue())
+		Expect(trap.FocusReturned).To(BeTrue())
+		Expect(b.Run("window.escapes")).To(BeEquivalentTo(1))
+	})
+
+	It("asks to be polled when a single sample can't see the modal settle", func() {
+		open("slow")
+		Eventually("#slow button").Should(b.BeFocused())
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect("#slow").To(b.BeFocusTrapped())
+		Expect(failure).To(ContainSubstring("#slow has not settled since Biloba pressed Escape - apply BeFocusTrapped with Eventually"))
+	})
+
+	It("starts over when a matcher that was left settling is applied to another modal", func() {
+		trapped := b.BeFocusTrapped()
+		open("slow")
+		Eventually("#slow button").Should(b.BeFocused())
+		g := NewGomega(func(message string, callerSkip ...int) {})
+		g.Expect("#slow").To(trapped)
+		Eventually("#slow").ShouldNot(b.BeVisible())
+
+		open("good")
+		var trap biloba.FocusTrapResult
+		Eventually("#good").Should(trapped.Capture(&trap))
+		Expect(trap.Invoker).To(Equal(`role=button name="Delete file"`))
+		Expect(b.Run("window.escapes")).To(BeEquivalentTo(2))
+	})
+
+	It("fails when Tab gets out", func() {
+		open("leaky")
+		Eventually("#leaky input").Should(b.BeFocused())
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect("#leaky").To(b.BeFocusTrapped())
+		Expect(failure).To(HavePrefix(`Expected #leaky to trap focus, but Tab got out of it: focus left the scope for role=link name="After the modals"`))
+		Expect(failure).To(ContainSubstring(`  Tab:       role=textbox name="Recipient" -> role=button name="Send" -> (focus left the scope for role=link name="After the modals")`))
+	})
+
+	It("fails when focus is not inside the modal", func() {
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		b.Focus("#after")
+		b.Run(`document.getElementById("good").hidden = false`)
+		g.Expect("#good").To(b.BeFocusTrapped())
+		Expect(failure).To(Equal(`Expected #good to trap focus, but focus is not inside it - it is on role=link name="After the modals"`))
+	})
+
+	It("fails straight away when closing the modal loses focus", func() {
+		open("forgetful")
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Eventually("#forgetful").Should(b.BeFocusTrapped())
+		Expect(failure).To(ContainSubstring("Expected #forgetful to trap focus, but focus did not return to the element that opened it when Escape closed it"))
+		Expect(failure).To(ContainSubstring(`Escape:    closes it; focus went to the page, not the invoker, role=button name="Settings"`))
+	})
+
+	It("points out an invoker that never had focus", func() {
+		b.Click("#open-forgetful")
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Eventually("#forgetful").Should(b.BeFocusTrapped())
+		Expect(failure).To(ContainSubstring("(which never had focus - a plain b.Click does not focus what it clicks)"))
+	})
+})
@@ -0,0 +1,121 @@
+package biloba
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gcustom"
+)
+
+/*
+FillForm(selector, fields) fills in the form matching selector, field by field, by label:
+
+	b.FillForm("#signup", map[string]any{
+		"Email":     "jane@example.com",
+		"Country":   "Canada",
+		"Plan":      "Pro",
+		"Subscribe": true,
+	})
+
+Each key is resolved the way [Biloba.ByLabel] resolves a label - the control whose accessible name
+(from its <label>, aria-label or aria-labelledby) is the key - and, failing that, as the label of a
+radio group (its fieldset's <legend>, or the name of its role=radiogroup) and then as a control's name
+attribute.  Each value is then set with [Biloba.SetValue]'s semantics for that control:
+
+  - text inputs and textareas take a string (or a number)
+  - checkboxes take true or false
+  - a radio group takes the label - or the value - of the option to pick; a lone radio takes true
+  - a <select> takes the visible label of an option, falling back to its value; a <select multiple>
+    takes a slice of them.  [Biloba.ValueLabel] works here too.
+
+Every key is resolved before anything is set, so a typo fails straight away without half-filling the
+form - the failure lists the labels the form does have.  The fields are then set in document order, each
+polling the way SetValue does until it is visible and enabled.  Each key is resolved by its label again
+just before its field is set, so a field that setting an earlier one re-rendered or moved is still
+found - and a field inside a shadow root is set where it is.  FillForm polls until the form and all of its
+fields are there, and honors WithTimeout, WithPolling, WithContext and Immediate; a Realistic() view
+types into text fields and clicks checkboxes for real.
+
+Read https://onsi.github.io/biloba/#filling-forms-by-label to learn more
+*/
+func (b *Biloba) FillForm(selector any, fields map[string]any) {
+	b.gt.Helper()
+	keys := []string{}
+	matcher := gcustom.MakeMatcher(func(sel any) (bool, error) {
+		r := b.runBilobaHandler("planFormFill", sel, fields)
+		if r.Error() != nil {
+			return false, r.Error()
+		}
+		plan := struct {
+			Keys    []string `json:"keys"`
+			Problem string   `json:"problem"`
+		}{}
+		if err := remarshal(r.Result, &plan); err != nil {
+			return false, err
+		}
+		if plan.Problem != "" {
+			// the form is there, so a key that names no field is a typo in the spec - polling won't fix it
+			return false, gomega.StopTrying(fmt.Sprintf("Failed to fill %s: %s", describeSelector(selector), plan.Problem))
+		}
+		keys = plan.Keys
+		return true, nil
+	}).WithMessage("have the fields to fill")
+	if !b.pollOrImmediate(selector, matcher) {
+		return
+	}
+	for _, key := range keys {
+		field := ElementHandle{id: fmt.Sprintf("pin-%d", atomic.AddInt64(&pinCounter, 1)), selector: fmt.Sprintf("field %q of %s", key, describeSelector(selector))}
+		var value any
+		pinned := gcustom.MakeMatcher(func(sel any) (bool, error) {
+			r := b.runBilobaHandler("pinFormField", sel, key, fields[key], field.id, describeSelector(field.selector))
+			if r.Error() != nil {
+				return false, r.Error()
+			}
+			value = r.Result
+			return true, nil
+		}).WithMessage(fmt.Sprintf("have a field labelled %q", key))
+		if !b.pollOrImmediate(selector, pinned) {
+			return
+		}
+		field.pinnedAt = time.Now()
+		if !b.pollOrImmediate(field, b.setValueMatcher(value)) {
+			return
+		}
+	}
+}
+
+/*
+GetFormValues(selector) returns the current value of every field in the form matching selector, keyed
+both by label and by name attribute - whichever your spec finds clearer:
+
+	values := b.GetFormValues("#signup")
+	Ω(values.GetString("Email")).Should(Equal("jane@example.com"))
+	Ω(values.GetBool("subscribe")).Should(BeTrue())
+
+Values are read the way [Biloba.GetValue] reads them: a string for text fields, a bool for a checkbox,
+the value of the checked option for a radio group (keyed by the group's label), the value of the
+selected option for a <select>, and a []string for a <select multiple>.  Buttons are left out.
+
+GetFormValues polls until the form exists.
+
+Read https://onsi.github.io/biloba/#filling-forms-by-label to learn more
+*/
+func (b *Biloba) GetFormValues(selector any) Properties {
+	b.gt.Helper()
+	values := Properties{}
+	matcher := gcustom.MakeMatcher(func(sel any) (bool, error) {
+		r := b.runBilobaHandler("getFormValues", sel)
+		if r.Error() != nil {
+			return false, r.Error()
+		}
+		if !r.Success {
+			return false, nil
+		}
+		values = newProperties(r.Result)
+		return true, nil
+	}).WithMessage("exist")
+	b.pollOrImmediate(selector, matcher)
+	return values
+}
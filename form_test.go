@@ -0,0 +1,104 @@
+package biloba_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Filling forms by label", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/form.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	Describe("FillForm", func() {
+		It("resolves each field by label and sets it the way SetValue would, in document order", func() {
+			b.FillForm("#signup", map[string]any{
+				"Subscribe": true,
+				"Email":     "jane@example.com",
+				"Age":       42,
+				"Country":   "Canada",
+				"Plan":      "Pro",
+				"Interests": []string{"Go", "Zig"},
+				"Bio":       "Hello there",
+			})
+			Ω(`[name="email"]`).Should(b.HaveValue("jane@example.com"))
+			Ω("#age").Should(b.HaveValue("42"))
+			Ω("#country").Should(b.HaveValue("c-17"))
+			Ω(`[name="plan"]`).Should(b.HaveValue("p-2"))
+			Ω(`[name="subscribe"]`).Should(b.HaveValue(true))
+			Ω("#interests").Should(b.HaveValue(ConsistOf("i-1", "i-3")))
+			Ω(`[name="bio"]`).Should(b.HaveValue("Hello there"))
+			Ω("#log").Should(b.HaveInnerText("email,age,country,plan,subscribe,interests,bio,"))
+		})
+
+		It("falls back to name attributes, option values and ValueLabel", func() {
+			b.FillForm("#signup", map[string]any{
+				"referrer": "a friend",
+				"Country":  "c-42",
+				"plan":     "p-1",
+			})
+			Ω(`[name="referrer"]`).Should(b.HaveValue("a friend"))
+			Ω("#country").Should(b.HaveValue("c-42"))
+			Ω(`[name="plan"]`).Should(b.HaveValue("p-1"))
+
+			b.FillForm("#signup", map[string]any{"Country": b.ValueLabel("Canada"), "Free": true})
+			Ω("#country").Should(b.HaveValue("c-17"))
+			Ω(`[name="plan"]`).Should(b.HaveValue("p-1"))
+		})
+
+		It("resolves every field before setting any, listing the labels when one is missing", func() {
+			b.Immediate().FillForm("#signup", map[string]any{"Email": "jane@example.com", "Emial": "typo"})
+			ExpectFailures(SatisfyAll(
+				ContainSubstring(`there is no field labelled "Emial"`),
+				ContainSubstring(`"Email", "Age", "Country", "Plan", "Subscribe", "Interests", "Bio", "referrer"`),
+			))
+			Ω(`[name="email"]`).Should(b.HaveValue(""))
+		})
+
+		It("fails straight away on a label the form doesn't have", func() {
+			start := time.Now()
+			b.FillForm("#signup", map[string]any{"Emial": "typo"})
+			ExpectFailures(ContainSubstring(`there is no field labelled "Emial"`))
+			Ω(time.Since(start)).Should(BeNumerically("<", time.Second))
+		})
+
+		It("finds each field again before setting it, in shadow roots and after the form changes", func() {
+			b.FillForm("#signup", map[string]any{"Country": "Canada", "referrer": "a friend", "Promo code": "SPRING"})
+			Ω("#province").Should(b.Exist())
+			Ω(`[name="referrer"]`).Should(b.HaveValue("a friend"))
+			Ω(`[name="decoy"]`).Should(b.HaveValue(""))
+			Ω(b.Run(`document.querySelector("#extras > div").shadowRoot.querySelector("input").value`)).Should(Equal("SPRING"))
+
+			b.FillForm("#signup", map[string]any{"Province": "Ontario"})
+			Ω("#province").Should(b.HaveValue("Ontario"))
+		})
+
+		It("fails on an option a radio group doesn't have", func() {
+			b.Immediate().FillForm("#signup", map[string]any{"Plan": "Enterprise"})
+			ExpectFailures(ContainSubstring(`"Plan" has no option "Enterprise" - the options are: "Free", "Pro"`))
+		})
+	})
+
+	Describe("GetFormValues", func() {
+		It("returns every value keyed by label and by name", func() {
+			b.FillForm("#signup", map[string]any{"Email": "jane@example.com", "Plan": "Free", "Subscribe": true, "Interests": []string{"Rust"}})
+			values := b.GetFormValues("#signup")
+			Ω(values.GetString("Email")).Should(Equal("jane@example.com"))
+			Ω(values.GetString("email")).Should(Equal("jane@example.com"))
+			Ω(values.GetString("Plan")).Should(Equal("p-1"))
+			Ω(values.GetString("plan")).Should(Equal("p-1"))
+			Ω(values.GetBool("Subscribe")).Should(BeTrue())
+			Ω(values.GetStringSlice("interests")).Should(Equal([]string{"i-2"}))
+			Ω(values.Get("Country")).Should(Equal(""))
+			Ω(values).Should(HaveKeyWithValue("referrer", ""))
+			Ω(values).ShouldNot(HaveKey("Sign up"))
+		})
+
+		It("reads an unchecked radio group as nil", func() {
+			Ω(b.GetFormValues("#signup")).Should(HaveKeyWithValue("Plan", BeNil()))
+		})
+	})
+})
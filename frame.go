@@ -0,0 +1,338 @@
+package biloba
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+/*
+Frame(selector) returns a lightweight view of this tab scoped to the document inside the <iframe> matching selector.  Every DOM matcher, action, and script run through the view happens inside the frame:
+
+	card := b.Frame("iframe[name='card-number']")
+	Eventually("input[name='cardnumber']").Should(card.Exist())
+	card.Type("input[name='cardnumber']", "4242424242424242")
+	Ω("#brand").Should(card.HaveInnerText("Visa"))
+
+This works for same-origin and cross-origin frames alike - payment widgets, embedded sign-in frames, and the like.  A cross-origin frame is a separate CDP target; Biloba attaches to it for you and installs its JavaScript there.
+
+The view resolves its frame on first use and keeps it until the frame's document goes away - the frame navigates or reloads, or the iframe is removed - and then resolves it again, so a view made before the iframe exists (or one that outlives a reload of the frame) just works, and polling commands keep polling until the iframe and its document are there.  selector is resolved in this view's document, so nested frames chain: b.Frame("#outer").Frame("#inner").
+
+The view shares everything else with its tab: realistic-mode input and screenshots are dispatched to the tab (with coordinates translated out of the frame), and tab-level commands such as Navigate, window size, emulation, and network stubbing act on the whole tab.
+
+Read https://onsi.github.io/biloba/#working-with-frames to learn more
+*/
+func (b *Biloba) Frame(selector any) *Biloba {
+	fb := *b
+	fb.frame = &frameScope{owner: b, selector: selector}
+	fb.bilobaIsInstalled = false
+	return &fb
+}
+
+// frameScope is what makes a view a Frame() view: the iframe's selector, and the view (tab or
+// enclosing frame) it is resolved in.  It also caches the resolved document, so a poll inside the
+// frame doesn't find the iframe again on every sample.  Copies of the view share it via the pointer.
+type frameScope struct {
+	owner    *Biloba
+	selector any
+
+	lock        sync.Mutex
+	resolved    *resolvedFrame
+	resolutions int
+}
+
+// resolvedFrame is a Frame view's cached document, and what it depends on: the tracker for the
+// session hosting the iframe and its generation when the frame was resolved, and - for a cross-origin
+// frame - the frame's own target and that target's generation.  Any of them moving means the
+// document may be gone.
+type resolvedFrame struct {
+	session          documentSession
+	host             *frameContexts
+	hostGeneration   uint64
+	target           *frameTarget
+	frameID          cdp.FrameID
+	targetGeneration uint64
+}
+
+// cached returns the frame's resolved document when nothing it depends on has changed since.
+func (f *frameScope) cached(b *Biloba, host documentSession) (documentSession, bool) {
+	f.lock.Lock()
+	r := f.resolved
+	f.lock.Unlock()
+	if r == nil || r.host != host.frames || r.hostGeneration != host.frames.current() {
+		return documentSession{}, false
+	}
+	if r.target != nil && (!b.hasFrameTarget(r.frameID, r.target) || r.targetGeneration != r.target.frames.current()) {
+		return documentSession{}, false
+	}
+	return r.session, true
+}
+
+func (f *frameScope) remember(r *resolvedFrame) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.resolved = r
+	f.resolutions++
+}
+
+// documentSession is where a view's document lives: the CDP session hosting it, the execution context
+// to evaluate in (0 for the session's main frame), the document's frame, and the tracker for the
+// frames that session hosts in-process.
+type documentSession struct {
+	ctx       context.Context
+	contextID runtime.ExecutionContextID
+	frameID   cdp.FrameID
+	frames    *frameContexts
+}
+
+// documentSession resolves this view's document.  For a tab it is the tab itself; for a Frame view
+// it walks out through the owning views to find the iframe, then either the in-process frame's main
+// world or the cross-origin frame's own target - unless the view already resolved a document that is
+// still there.
+func (b *Biloba) documentSession() (documentSession, error) {
+	if b.frame == nil {
+		return documentSession{ctx: b.Context, frames: b.frameContexts}, nil
+	}
+	owner := b.frame.owner
+	session, err := owner.documentSession()
+	if err != nil {
+		return documentSession{}, err
+	}
+	if cached, ok := b.frame.cached(b, session); ok {
+		return cached, nil
+	}
+	// read the generation before resolving, so a navigation that races the resolution invalidates it
+	hostGeneration := session.frames.current()
+	node, err := owner.elementObject(session, b.frame.selector)
+	if err != nil {
+		return documentSession{}, err
+	}
+	if node == nil {
+		return documentSession{}, fmt.Errorf("could not find the iframe matching selector: %v", b.frame.selector)
+	}
+	var described *cdp.Node
+	var origin []byte
+	err = chromedp.Run(session.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		defer runtime.ReleaseObject(node.ObjectID).Do(ctx)
+		var err error
+		described, err = dom.DescribeNode().WithObjectID(node.ObjectID).Do(ctx)
+		if err != nil || described.ContentDocument != nil {
+			return err
+		}
+		res, exp, err := runtime.CallFunctionOn(`function() { return _biloba.frameOrigin(this) }`).WithObjectID(node.ObjectID).WithReturnByValue(true).Do(ctx)
+		if err == nil && exp != nil {
+			err = exp
+		}
+		if err == nil {
+			origin = res.Value
+		}
+		return err
+	}))
+	if err != nil {
+		return documentSession{}, err
+	}
+	if described.NodeName != "IFRAME" && described.NodeName != "FRAME" {
+		return documentSession{}, fmt.Errorf("the element matching selector %v is a <%s>, not an <iframe>", b.frame.selector, strings.ToLower(described.NodeName))
+	}
+	if described.FrameID == "" {
+		return documentSession{}, fmt.Errorf("the iframe matching selector %v has no document yet", b.frame.selector)
+	}
+	if described.ContentDocument != nil {
+		contextID, ok := session.frames.lookup(described.FrameID)
+		if !ok {
+			return documentSession{}, fmt.Errorf("the iframe matching selector %v has no document yet", b.frame.selector)
+		}
+		resolved := documentSession{ctx: session.ctx, contextID: contextID, frameID: described.FrameID, frames: session.frames}
+		b.frame.remember(&resolvedFrame{session: resolved, host: session.frames, hostGeneration: hostGeneration})
+		return resolved, nil
+	}
+
+	// No content document means the frame is rendered out of process: it is a target of its own
+	frameTarget, err := b.attachFrameTarget(described.FrameID)
+	if err != nil {
+		return documentSession{}, fmt.Errorf("could not attach to the cross-origin iframe matching selector %v:\n%w", b.frame.selector, err)
+	}
+	inner := documentSession{ctx: frameTarget.ctx, frames: frameTarget.frames}
+	targetGeneration := frameTarget.frames.current()
+	if len(origin) == 0 {
+		origin = []byte("null")
+	}
+	if err := inner.evaluate("window._bilobaFrameOrigin = "+string(origin), nil); err != nil {
+		b.forgetFrameTarget(described.FrameID)
+		return documentSession{}, err
+	}
+	b.frame.remember(&resolvedFrame{session: inner, host: session.frames, hostGeneration: hostGeneration, target: frameTarget, frameID: described.FrameID, targetGeneration: targetGeneration})
+	return inner, nil
+}
+
+// elementObject resolves selector in session's document (which must be b's) to a remote reference to
+// the first matching element, or nil when nothing matches.  The caller releases the reference.
+func (b *Biloba) elementObject(session documentSession, selector any) (*runtime.RemoteObject, error) {
+	encoded, err := encodeSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	b.ensureBiloba()
+	if err := b.resolveAXQueries(encoded); err != nil {
+		return nil, err
+	}
+	var node *runtime.RemoteObject
+	script := b.bilobaInvocation("node", encoded)
+	err = session.evaluate(script, &node)
+	if err != nil && strings.Contains(err.Error(), "_biloba is not defined") {
+		b.reloadBiloba()
+		err = session.evaluate(script, &node)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if node == nil || node.ObjectID == "" {
+		return nil, nil
+	}
+	return node, nil
+}
+
+// evaluate runs script in the session's document, decoding the result the way chromedp.Evaluate does.
+func (s documentSession) evaluate(script string, res any, opts ...chromedp.EvaluateOption) error {
+	if s.contextID != 0 {
+		opts = append(opts, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithContextID(s.contextID)
+		})
+	}
+	return chromedp.Run(s.ctx, chromedp.EvaluateAsDevTools(script, res, opts...))
+}
+
+// frameContexts tracks the main-world execution context of each frame one CDP session hosts, from the
+// Runtime domain's context events.  Runtime.evaluate runs in the session's main frame unless it is
+// given a context, and CDP offers no way to ask for a frame's context after the fact - so we listen.
+// generation counts the contexts destroyed and the frames navigated or detached, so a Frame view can
+// tell when the document it resolved may be gone.
+type frameContexts struct {
+	lock       sync.Mutex
+	byFrame    map[cdp.FrameID]runtime.ExecutionContextID
+	generation uint64
+}
+
+func newFrameContexts() *frameContexts {
+	return &frameContexts{byFrame: map[cdp.FrameID]runtime.ExecutionContextID{}}
+}
+
+func (f *frameContexts) handle(ev any) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	switch ev := ev.(type) {
+	case *runtime.EventExecutionContextCreated:
+		var aux struct {
+			IsDefault bool        `json:"isDefault"`
+			FrameID   cdp.FrameID `json:"frameId"`
+		}
+		if json.Unmarshal(ev.Context.AuxData, &aux) == nil && aux.IsDefault && aux.FrameID != "" {
+			f.byFrame[aux.FrameID] = ev.Context.ID
+		}
+	case *runtime.EventExecutionContextDestroyed:
+		f.generation++
+		for frameID, id := range f.byFrame {
+			if id == ev.ExecutionContextID {
+				delete(f.byFrame, frameID)
+			}
+		}
+	case *runtime.EventExecutionContextsCleared:
+		f.generation++
+		f.byFrame = map[cdp.FrameID]runtime.ExecutionContextID{}
+	case *page.EventFrameNavigated, *page.EventFrameDetached:
+		f.generation++
+	}
+}
+
+func (f *frameContexts) current() uint64 {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.generation
+}
+
+func (f *frameContexts) lookup(frameID cdp.FrameID) (runtime.ExecutionContextID, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	id, ok := f.byFrame[frameID]
+	return id, ok
+}
+
+// frameTarget is Biloba's own session on a cross-origin (out-of-process) frame.  Chrome names such a
+// frame's target after the frame, so the frame ID is all it takes to attach.
+type frameTarget struct {
+	ctx    context.Context
+	frames *frameContexts
+}
+
+// frameTargets holds the tab's sessions on its cross-origin frames, so each is attached to once.  It
+// is a pointer on Biloba, like media, so every view of the tab shares it.
+type frameTargets struct {
+	lock     sync.Mutex
+	byFrame  map[cdp.FrameID]*frameTarget
+	attached []*frameTarget
+}
+
+// attachFrameTarget returns the tab's session on a cross-origin frame, attaching if need be.  The
+// chromedp context is deliberately never cancelled: cancelling closes the target, and for a frame's
+// target Chrome closes the page that hosts it.  Sessions are detached instead - by Prepare(), or along
+// with the tab when it closes.
+func (b *Biloba) attachFrameTarget(frameID cdp.FrameID) (*frameTarget, error) {
+	b.frameTargets.lock.Lock()
+	defer b.frameTargets.lock.Unlock()
+	if t, ok := b.frameTargets.byFrame[frameID]; ok {
+		return t, nil
+	}
+	ctx, _ := chromedp.NewContext(b.Context, chromedp.WithTargetID(target.ID(frameID)))
+	t := &frameTarget{ctx: ctx, frames: newFrameContexts()}
+	chromedp.ListenTarget(ctx, t.frames.handle)
+	if err := chromedp.Run(ctx); err != nil {
+		return nil, err
+	}
+	b.frameTargets.byFrame[frameID] = t
+	b.frameTargets.attached = append(b.frameTargets.attached, t)
+	return t, nil
+}
+
+// forgetFrameTarget drops a session that has stopped working (the frame's target went away when it
+// navigated back to the parent's site, say), so the next command attaches afresh.
+func (b *Biloba) forgetFrameTarget(frameID cdp.FrameID) {
+	b.frameTargets.lock.Lock()
+	delete(b.frameTargets.byFrame, frameID)
+	b.frameTargets.lock.Unlock()
+}
+
+// hasFrameTarget reports whether t is still the tab's session on the frame - it isn't once the session
+// has been forgotten or detached.
+func (b *Biloba) hasFrameTarget(frameID cdp.FrameID, t *frameTarget) bool {
+	b.frameTargets.lock.Lock()
+	defer b.frameTargets.lock.Unlock()
+	return b.frameTargets.byFrame[frameID] == t
+}
+
+// detachFrameTargets is Prepare()'s cleanup for the cross-origin frame sessions the previous spec
+// attached on the reused root tab.
+func (b *Biloba) detachFrameTargets() {
+	b.frameTargets.lock.Lock()
+	attached := b.frameTargets.attached
+	b.frameTargets.byFrame = map[cdp.FrameID]*frameTarget{}
+	b.frameTargets.attached = nil
+	b.frameTargets.lock.Unlock()
+	for _, t := range attached {
+		c := chromedp.FromContext(t.ctx)
+		if c == nil || c.Target == nil {
+			continue
+		}
+		b.runWithBrowserExecutor(func(ctx context.Context) error {
+			return target.DetachFromTarget().WithSessionID(c.Target.SessionID).Do(ctx)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package biloba_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Frame", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/frames.html")
+		Eventually("#hello").Should(b.Exist())
+	})
+
+	Describe("same-origin frames", func() {
+		It("scopes DOM matchers and actions to the frame's document", func() {
+			frame := b.Frame("#same-origin")
+			Eventually("#iframe-btn").Should(frame.Exist())
+			Ω("#iframe-btn").ShouldNot(b.Exist())
+			Ω(".cell").Should(frame.HaveCount(2))
+
+			frame.Click("#iframe-btn")
+			Ω("#iframe-btn").Should(frame.HaveInnerText("Iframe Clicked"))
+			Ω(frame.Run("document.title")).Should(Equal("Iframe Content"))
+		})
+
+		It("clicks in realistic mode", func() {
+			frame := b.Realistic().Frame("#same-origin")
+			Eventually("#iframe-btn").Should(frame.Exist())
+			frame.Click("#iframe-btn")
+			Eventually("#iframe-btn").Should(frame.HaveInnerText("Iframe Clicked"))
+		})
+	})
+
+	Describe("cross-origin frames", func() {
+		It("attaches to the frame's target and works inside it", func() {
+			pay := b.Frame("#payment")
+			Eventually("#card-number").Should(pay.Exist())
+			Ω(pay.Run("location.hostname")).Should(Equal("localhost"))
+			Ω("#card-number").ShouldNot(b.Exist())
+
+			pay.Type("#card-number", "4242")
+			Ω("#card-number").Should(pay.HaveValue("4242"))
+			pay.Click("#pay")
+			Ω("#status").Should(pay.HaveInnerText("Paid with 4242"))
+		})
+
+		It("clicks in realistic mode, translating the point out of the frame", func() {
+			pay := b.Realistic().Frame("#payment")
+			Eventually("#card-number").Should(pay.Exist())
+			pay.SetValue("#card-number", "5555")
+			pay.Click("#pay")
+			Eventually("#status").Should(pay.HaveInnerText("Paid with 5555"))
+		})
+
+		It("chains into frames nested inside the frame", func() {
+			terms := b.Frame("#payment").Frame("#terms")
+			Eventually("#terms-text").Should(terms.HaveInnerText("No refunds"))
+		})
+	})
+
+	It("resolves the frame once, and again when the frame reloads", func() {
+		frame := b.Frame("#same-origin")
+		Eventually("#iframe-btn").Should(frame.Exist())
+		resolutions := frame.FrameResolutionsForTest()
+		for range 5 {
+			Ω("#iframe-btn").Should(frame.Exist())
+			Ω(frame.Run("document.title")).Should(Equal("Iframe Content"))
+		}
+		Ω(frame.FrameResolutionsForTest()).Should(Equal(resolutions))
+
+		frame.Click("#iframe-btn")
+		Ω("#iframe-btn").Should(frame.HaveInnerText("Iframe Clicked"))
+		frame.Run("location.reload()")
+		Eventually("#iframe-btn").Should(frame.HaveInnerText("Iframe Button"))
+		Ω(frame.FrameResolutionsForTest()).Should(BeNumerically(">", resolutions))
+	})
+
+	It("polls until the frame is there", func() {
+		b.Run(`setTimeout(() => {
+			let f = document.createElement("iframe")
+			f.id = "late"
+			f.src = "/iframe-content.html"
+			document.body.appendChild(f)
+		}, 100)`)
+		Eventually("#iframe-btn").Should(b.Frame("#late").Exist())
+	})
+
+	It("fails when no iframe matches", func() {
+		b.Frame("#nope").Run("1")
+		ExpectFailures(ContainSubstring("could not find the iframe matching selector: #nope"))
+	})
+
+	It("fails when the selector does not match an iframe", func() {
+		b.Frame("#not-a-frame").Run("1")
+		ExpectFailures(ContainSubstring("the element matching selector #not-a-frame is a <div>, not an <iframe>"))
+	})
+})
@@ -7,7 +7,6 @@ import (
 	"sync/atomic"
 
 	"github.com/chromedp/cdproto/runtime"
-	"github.com/chromedp/chromedp"
 	"github.com/onsi/gomega/gcustom"
 )
 
@@ -64,7 +63,11 @@ func (b *Biloba) runErr(script string, awaitPromise bool, args ...any) (any, err
 		}
 		return p
 	}
-	err := chromedp.Run(b.Context, chromedp.EvaluateAsDevTools(script, &encodedResult, options))
+	session, err := b.documentSession()
+	if err != nil {
+		return nil, err
+	}
+	err = session.evaluate(script, &encodedResult, options)
 	if err != nil {
 		if strings.Contains(err.Error(), "_biloba is not defined") {
 			b.reloadBiloba()
@@ -170,9 +170,10 @@ func (b *Biloba) dispatchKeys(keys string, mods []clickModifier) error {
 }
 
 // focusAndSendKeys focuses the element matching selector (failing if it is missing, hidden, or
-// disabled) then dispatches keys as real keyboard events via chromedp, holding mods down.  The
-// element is resolved fresh in the browser so keydown/keypress/keyup all fire.
-func (b *Biloba) focusAndSendKeys(selector any, keys string, mods []clickModifier) (bool, error) {
+// disabled), places the caret, then dispatches keys as real keyboard events via chromedp, holding mods
+// down.  The element is resolved fresh in the browser so keydown/keypress/keyup all fire.  A
+// contenteditable editor is typed into with typeIntoEditor instead, unless modifiers are held.
+func (b *Biloba) focusAndSendKeys(selector any, payload []any, keys string, caret *Caret, mods []clickModifier) (bool, error) {
 	if b.realistic {
 		// realistically bring the element into view before focusing+typing (Playwright focuses
 		// inputs via JS too, so the keys path is unchanged - only the scroll is added)
@@ -187,6 +188,16 @@ func (b *Biloba) focusAndSendKeys(selector any, keys string, mods []clickModifie
 	if !r.Success {
 		return false, nil
 	}
+	editor, err := b.placeCaret(selector, caret)
+	if err != nil {
+		return false, err
+	}
+	if editor && len(mods) == 0 {
+		if err := b.typeIntoEditor(payload); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
 	if err := b.dispatchKeys(keys, mods); err != nil {
 		return false, err
 	}
@@ -236,6 +247,13 @@ You can hold keyboard modifiers ([Biloba.Shift], [Biloba.Ctrl], [Biloba.Alt], [B
 	b.Type("input.search", "a", b.Meta())
 	Eventually("input.search").Should(b.Type("a", b.Meta()))
 
+Pass a [Caret] - [AtStart], [AtEnd] or [AfterText] - to say where the text goes, in an <input>, a <textarea> or a contenteditable editor:
+
+	b.Type("input.search", biloba.AtStart, "red ")
+	b.Type("#editor", biloba.AfterText("Regards"), ",")
+
+Rich-text editors (ProseMirror, Lexical and other contenteditable hosts) are typed into the way an editor expects: text goes through Input.insertText, so the editor sees the beforeinput and input events of real text entry, and newlines and named [Keys] are sent as key presses.  The caret goes at the end of the editor unless you say otherwise.  Held modifiers send everything as key presses, so hotkeys like Cmd-B reach the editor as keydowns.
+
 Note: the matcher form cannot mix leading text with trailing keys - b.Type("hello", biloba.Keys.Enter) is read as the immediate form with selector "hello".  That's fine: the immediate form now polls, so it already covers that case; reach for the matcher form only when you need a custom Consistently or composition.
 
 Read https://onsi.github.io/biloba/#keyboard-input to learn more about keyboard input
@@ -243,6 +261,7 @@ Read https://onsi.github.io/biloba/#keyboard-input to learn more about keyboard
 func (b *Biloba) Type(args ...any) types.GomegaMatcher {
 	b.gt.Helper()
 	rest, mods := splitModifiers(args)
+	rest, caret := splitCaret(rest)
 	if len(rest) == 0 {
 		b.gt.Fatalf("Type requires text or keys to type")
 		return nil
@@ -250,7 +269,7 @@ func (b *Biloba) Type(args ...any) types.GomegaMatcher {
 
 	firstIsSelector := false
 	switch rest[0].(type) {
-	case string, XPath:
+	case string, XPath, Locator, ElementHandle:
 		firstIsSelector = true
 	}
 
@@ -262,7 +281,7 @@ func (b *Biloba) Type(args ...any) types.GomegaMatcher {
 			return nil
 		}
 		matcher := gcustom.MakeMatcher(func(selector any) (bool, error) {
-			return b.focusAndSendKeys(selector, keys, mods)
+			return b.focusAndSendKeys(selector, rest[1:], keys, caret, mods)
 		}).WithMessage("be typable")
 		b.pollOrImmediate(selector, matcher)
 		return nil
@@ -275,7 +294,7 @@ func (b *Biloba) Type(args ...any) types.GomegaMatcher {
 	}
 	b.guardBareMatcher("Type")
 	return gcustom.MakeMatcher(func(selector any) (bool, error) {
-		return b.focusAndSendKeys(selector, keys, mods)
+		return b.focusAndSendKeys(selector, rest, keys, caret, mods)
 	}).WithMessage("be typable")
 }
 
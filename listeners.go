@@ -50,4 +50,9 @@ func (b *Biloba) setUpListeners() {
 			b.handleEventRequestPaused(ev)
 		}
 	})
+	if b.isRootTab() {
+		// spawned tabs start listening before they attach (see registerTabFor); the root tab's
+		// page is still about:blank here, so it has no frames to miss
+		chromedp.ListenTarget(b.Context, b.frameContexts.handle)
+	}
 }
@@ -20,16 +20,16 @@ Locator is a semantic selector that matches elements the way a user (or the acce
 
 Locators are resilient to DOM churn the way structural selectors are not, and they nudge you toward testing what the user perceives.  Build them with the By* constructors ([Biloba.ByRole], [Biloba.ByText], [Biloba.ByLabel], [Biloba.ByPlaceholder], [Biloba.ByAltText], [Biloba.ByTitle], [Biloba.ByTestID]).
 
-Locators compose.  Filter and combine them with [Locator.WithName], [Locator.ContainingText], [Locator.Containing], [Locator.Within] (scoping), [Locator.And]/[Locator.Or] (set combination - which accept any CSS/XPath/Locator selector), [Locator.Nth]/[Locator.First]/[Locator.Last] (ordinal), [Locator.Level] (heading level), and the ARIA-state filters ([Locator.Checked], [Locator.Disabled], [Locator.Expanded], [Locator.Pressed], [Locator.Selected]).
+Locators compose.  Filter and combine them with [Locator.WithName], [Locator.ContainingText], [Locator.Containing], [Locator.Within] (scoping), [Locator.RightOf]/[Locator.LeftOf]/[Locator.Above]/[Locator.Below]/[Locator.Near] (layout position), [Locator.And]/[Locator.Or] (set combination - which accept any CSS/XPath/Locator selector), [Locator.Nth]/[Locator.First]/[Locator.Last] (ordinal), [Locator.Level] (heading level), and the ARIA-state filters ([Locator.Checked], [Locator.Disabled], [Locator.Expanded], [Locator.Pressed], [Locator.Selected]).
 
 Coverage is pragmatic, not the full ARIA spec: explicit role="" plus the common implicit roles, and accessible names from aria-labelledby/aria-label/<label>/alt/placeholder/value/text/figcaption/caption/title.  Locators pierce open shadow roots (closed roots and cross-origin frames are skipped).  For the long tail of structural queries, drop to [Biloba.XPath] or CSS :has().
 
 Read https://onsi.github.io/biloba/#selecting-dom-elements to learn more about selectors.
 */
 type Locator struct {
-	by string // leaf: role|text|label|placeholder|alttext|title|testid ; combinator: and|or
+	by string // leaf: role|axrole|text|label|placeholder|alttext|title|testid ; combinator: and|or
 
-	role string // by=="role"
+	role string // by=="role" or by=="axrole"
 
 	value     string // search string for text/label/placeholder/alttext/title/testid
 	valueMode string // "exact" | "contains"
@@ -58,10 +58,11 @@ type Locator struct {
 // locatorFilter is a post-match predicate: a visible-text test ("containsText") or a
 // has-a-descendant-matching-selector test ("contains"). negate flips the sense.
 type locatorFilter struct {
-	kind     string // "containsText" | "contains"
-	value    string // containsText
-	mode     string // containsText: "exact" | "contains"
-	selector any    // contains: the descendant selector
+	kind     string  // "containsText" | "contains" | "within" | "near" | "rightOf" | "leftOf" | "above" | "below"
+	value    string  // containsText
+	mode     string  // containsText: "exact" | "contains"
+	selector any     // contains/within: the descendant or scope selector; the positional kinds: the anchor
+	maxPx    float64 // near: the largest gap, in CSS pixels
 	negate   bool
 }
 
@@ -93,6 +94,20 @@ func (b *Biloba) ByRole(role string) Locator {
 	return Locator{by: "role", role: role}
 }
 
+/*
+ByAXRole(role) is like [Biloba.ByRole], but roles and names come from Chrome's own accessibility tree (via Accessibility.queryAXTree) rather than biloba.js's heuristics - so aria-labelledby chains, display:contents, shadow-DOM slotting and the rest of the spec follow exactly what a screen reader is told:
+
+	b.Click(b.ByAXRole("button").WithName("Save draft"))
+	Eventually(b.ByAXRole("image").WithNameContains("logo")).Should(b.BeVisible())
+
+Roles are Chrome's, as [Biloba.A11yOutline] prints them (e.g. "image" where ARIA says "img").  Each use costs a CDP round-trip, so reach for ByAXRole when the heuristics get a name wrong, not by default.
+
+Read https://onsi.github.io/biloba/#locators-backed-by-chromes-accessibility-tree to learn more.
+*/
+func (b *Biloba) ByAXRole(role string) Locator {
+	return Locator{by: "axrole", role: role}
+}
+
 /*
 ByText(text) returns a [Locator] that matches the smallest element whose visible text equals text exactly.
 
@@ -272,6 +287,49 @@ func (l Locator) NotWithin(scope any) Locator {
 	return l.addFilter(locatorFilter{kind: "within", selector: scope, negate: true})
 }
 
+// ---- positional filters ------------------------------------------------------------------------
+
+/*
+RightOf(anchor) narrows the [Locator] to elements laid out to the right of an element matching anchor (any CSS/XPath/Locator) and sharing part of its row - for layouts with no structure to scope by, like a dense grid of divs:
+
+	b.Click(b.ByRole("button").WithName("Edit").RightOf(b.ByText("Jane Doe")))
+
+The positional filters compare bounding boxes, just as [Biloba.BeRightOf] does: the element's left edge is at or beyond the anchor's right edge, and the two boxes overlap vertically.  Elements that are not laid out never match, and if anchor matches nothing the locator matches nothing.
+
+Read https://onsi.github.io/biloba/#selecting-by-position to learn more.
+*/
+func (l Locator) RightOf(anchor any) Locator {
+	return l.addFilter(locatorFilter{kind: "rightOf", selector: anchor})
+}
+
+// LeftOf(anchor) is like [Locator.RightOf] but keeps elements whose right edge is at or before the anchor's left edge.
+func (l Locator) LeftOf(anchor any) Locator {
+	return l.addFilter(locatorFilter{kind: "leftOf", selector: anchor})
+}
+
+// Above(anchor) narrows the [Locator] to elements whose bottom edge is at or above the top edge of an element matching anchor, and that overlap it horizontally (the same column).
+func (l Locator) Above(anchor any) Locator {
+	return l.addFilter(locatorFilter{kind: "above", selector: anchor})
+}
+
+// Below(anchor) is like [Locator.Above] but keeps elements whose top edge is at or below the anchor's bottom edge.
+func (l Locator) Below(anchor any) Locator {
+	return l.addFilter(locatorFilter{kind: "below", selector: anchor})
+}
+
+/*
+Near(anchor, maxPx) narrows the [Locator] to elements whose bounding box comes within maxPx CSS pixels of the box of an element matching anchor (any CSS/XPath/Locator), in any direction:
+
+	b.SetValue(b.ByRole("textbox").Near(b.ByText("Discount code"), 50), "SPRING")
+
+The anchor itself, and elements nested inside it or wrapped around it, are never "near" it.
+
+Read https://onsi.github.io/biloba/#selecting-by-position to learn more.
+*/
+func (l Locator) Near(anchor any, maxPx float64) Locator {
+	return l.addFilter(locatorFilter{kind: "near", selector: anchor, maxPx: maxPx})
+}
+
 // ---- set combination ---------------------------------------------------------------------------
 
 /*
@@ -372,6 +430,17 @@ func (l Locator) payload() (map[string]any, error) {
 		if l.nameSet {
 			p["nameSet"], p["name"], p["nameMode"] = true, l.name, l.nameMode
 		}
+	case "axrole":
+		p["role"] = l.role
+		if l.nameSet {
+			p["nameSet"], p["name"], p["nameMode"] = true, l.name, l.nameMode
+		}
+		// the key under which Go hands biloba.js the elements Chrome matched (see resolveAXQueries)
+		key, err := json.Marshal([]any{l.role, l.nameSet, l.nameMode, l.name})
+		if err != nil {
+			return nil, err
+		}
+		p["key"] = string(key)
 	case "css":
 		p["value"] = l.value
 	case "text", "label", "placeholder", "alttext", "title":
@@ -403,12 +472,15 @@ func (l Locator) payload() (map[string]any, error) {
 			switch f.kind {
 			case "containsText":
 				fm["value"], fm["mode"] = f.value, f.mode
-			case "contains", "within":
+			case "contains", "within", "near", "rightOf", "leftOf", "above", "below":
 				enc, err := encodeSelector(f.selector)
 				if err != nil {
 					return nil, err
 				}
 				fm["selector"] = enc
+				if f.kind == "near" {
+					fm["maxPx"] = f.maxPx
+				}
 			}
 			fs[i] = fm
 		}
@@ -436,8 +508,8 @@ func (l Locator) String() string {
 	}
 	var base string
 	switch l.by {
-	case "role":
-		base = "role=" + l.role
+	case "role", "axrole":
+		base = l.by + "=" + l.role
 		if l.nameSet {
 			base += fmt.Sprintf(" name%s%q", op(l.nameMode), l.name)
 		}
@@ -471,6 +543,10 @@ func (l Locator) String() string {
 			base += fmt.Sprintf(" %scontainsText%q", not, f.value)
 		} else if f.kind == "within" {
 			base += fmt.Sprintf(" %swithin(%s)", not, describeSelector(f.selector))
+		} else if f.kind == "near" {
+			base += fmt.Sprintf(" near(%s, %gpx)", describeSelector(f.selector), f.maxPx)
+		} else if f.kind != "contains" {
+			base += fmt.Sprintf(" %s(%s)", f.kind, describeSelector(f.selector))
 		} else {
 			base += fmt.Sprintf(" %scontaining(%s)", not, describeSelector(f.selector))
 		}
@@ -274,3 +274,51 @@ var _ = Describe("Role / text / label locators", func() {
 		})
 	})
 })
+
+var _ = Describe("Positional locators", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/locator_position.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	It("finds the element in the anchor's row with RightOf", func() {
+		Expect(b.GetProperty(b.ByRole("button").WithName("Edit").RightOf(b.ByText("Jane Doe")), "id")).To(Equal("edit-jane"))
+		Expect(b.GetInnerText(b.ByCSS("#people > div").RightOf(b.ByText("John Roe")).First())).To(Equal("john@example.com"))
+		b.Click(b.ByRole("button").WithName("Edit").RightOf(b.ByText("Ann Poe")))
+		Expect("#edited").To(b.HaveInnerText("ann"))
+	})
+
+	It("finds the element in the anchor's row with LeftOf", func() {
+		Expect(b.GetInnerText(b.ByCSS("#people > div").LeftOf("#edit-john").First())).To(Equal("John Roe"))
+		Expect(b.ByCSS("#people > div").LeftOf("#edit-john")).To(b.HaveCount(2))
+	})
+
+	It("finds elements in the anchor's column with Above and Below", func() {
+		Expect(b.GetInnerText(b.ByCSS("#people > div").Above(b.ByText("Jane Doe")))).To(Equal("Name"))
+		Expect(b.ByRole("button").WithName("Edit").Below("#edit-jane")).To(b.HaveCount(2))
+		Expect(b.GetProperty(b.ByRole("button").WithName("Edit").Above("#edit-ann").Last(), "id")).To(Equal("edit-john"))
+	})
+
+	It("finds elements within a distance with Near", func() {
+		Expect(b.ByRole("button").Near("#gap-anchor", 50)).To(b.HaveCount(1))
+		Expect(b.GetProperty(b.ByRole("button").Near("#gap-anchor", 50), "id")).To(Equal("close"))
+		Expect(b.ByRole("button").Near("#gap-anchor", 250)).To(b.HaveCount(2))
+		Expect(b.ByRole("button").Near("#gap-anchor", 10)).NotTo(b.Exist())
+	})
+
+	It("does not count the anchor, or what is nested with it, as near", func() {
+		Expect(b.ByText("Jane Doe").Near(b.ByText("Jane Doe"), 1000)).NotTo(b.Exist())
+		Expect(b.ByCSS("#people").Near(b.ByText("Jane Doe"), 1000)).NotTo(b.Exist())
+		Expect(b.GetInnerText(b.ByCSS("#people > div").Near(b.ByText("Jane Doe"), 0))).To(Equal("jane@example.com"))
+	})
+
+	It("never matches elements that are not laid out, or an anchor that matches nothing", func() {
+		Expect(b.ByRole("button").WithName("Hidden").Near("#far", 1000)).NotTo(b.Exist())
+		Expect(b.ByRole("button").RightOf("#no-such-anchor")).NotTo(b.Exist())
+	})
+
+	It("describes itself in failures", func() {
+		Expect(b.ByRole("button").WithName("Edit").RightOf(b.ByText("Jane Doe")).String()).To(Equal(`role=button name="Edit" rightOf(text="Jane Doe")`))
+		Expect(b.ByRole("button").Near("#gap-anchor", 50).String()).To(Equal(`role=button near(#gap-anchor, 50px)`))
+	})
+})
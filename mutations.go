@@ -0,0 +1,325 @@
+package biloba
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/onsi/gomega/gcustom"
+)
+
+var mutationLogCounter int64
+
+// maxChurnRows bounds each ranking, and the timeline tail, in a rendered mutation report.
+const maxChurnRows = 10
+
+/*
+Mutation is one DOM mutation a [MutationLog] recorded: when it happened (since recording began), its
+Kind ("childList", "attributes" or "characterData"), the Node it happened to - described by tag, id
+and classes, like "tr#row-2.selected" - the Attribute that changed, and the elements Added and Removed.
+*/
+type Mutation struct {
+	At        time.Duration
+	Kind      string
+	Node      string
+	Attribute string
+	Added     []string
+	Removed   []string
+}
+
+func (m Mutation) String() string {
+	out := fmt.Sprintf("+%ss %s %s", roundDuration(m.At), m.Kind, m.Node)
+	if m.Attribute != "" {
+		out += fmt.Sprintf(" [%s]", m.Attribute)
+	}
+	for _, a := range m.Added {
+		out += " +" + a
+	}
+	for _, r := range m.Removed {
+		out += " -" + r
+	}
+	return out
+}
+
+/*
+MutationLog records the DOM mutations that touch the elements matching a selector - see
+[Biloba.RecordMutations].  Read the timeline with [MutationLog.Mutations], count it with
+[MutationLog.Count], [MutationLog.CountsByNode] and [MutationLog.CountsByAttribute], print
+[MutationLog.Report], or assert on it with [Biloba.HaveMutatedAtMost].
+
+Read https://onsi.github.io/biloba/#recording-mutations to learn more
+*/
+type MutationLog struct {
+	b        *Biloba
+	id       string
+	selector any
+}
+
+// String describes the log by its selector.
+func (l *MutationLog) String() string {
+	return fmt.Sprintf("mutations(%s)", describeSelector(l.selector))
+}
+
+// GomegaString keeps failure messages to the selector, rather than the tab the log records in.
+func (l *MutationLog) GomegaString() string {
+	return l.String()
+}
+
+/*
+RecordMutations(selector) starts recording every DOM mutation that touches the elements matching
+selector - inside them, to them, or adding and removing them - and returns the [MutationLog]:
+
+	rows := b.RecordMutations("#orders")
+	b.Click(b.ByRole("button").WithName("Refresh"))
+	Eventually("#orders").Should(b.HaveTableRows(HaveLen(20)))
+	fmt.Println(rows.Report())
+	Ω(rows).Should(b.HaveMutatedAtMost(50))
+
+It is the tool for a re-render storm: a component that re-mounts on every keystroke, or a list that
+rebuilds itself whenever anything changes, is the usual reason a spec flakes on a node that matched and
+then stopped matching.  The log counts mutations by node and by attribute, lists the elements that were
+removed and added again (re-mounted), and keeps a timeline of the latest 5000 mutations.
+
+Elements that start matching later are recorded from then on.  A log lasts as long as the document - a
+navigation ends it - or until [MutationLog.Stop].  RecordMutations acts immediately and does not poll.
+
+Read https://onsi.github.io/biloba/#recording-mutations to learn more
+*/
+func (b *Biloba) RecordMutations(selector any) *MutationLog {
+	b.gt.Helper()
+	b.guardConfig("RecordMutations")
+	l := &MutationLog{b: b, id: fmt.Sprintf("mutations-%d", atomic.AddInt64(&mutationLogCounter, 1)), selector: selector}
+	r := b.runBilobaHandler("recordMutations", selector, l.id)
+	if r.Error() != nil {
+		b.gt.Fatalf("Failed to record mutations:\n%s", r.Error())
+	}
+	return l
+}
+
+// recordedMutations is what biloba.js reports for a recorder: how many mutations it counted, and the
+// latest of them.
+type recordedMutations struct {
+	Total     int
+	Mutations []Mutation
+}
+
+func decodeRecordedMutations(result any) (recordedMutations, error) {
+	raw := struct {
+		Total   int `json:"total"`
+		Entries []struct {
+			At        float64  `json:"at"`
+			Kind      string   `json:"kind"`
+			Node      string   `json:"node"`
+			Attribute string   `json:"attribute"`
+			Added     []string `json:"added"`
+			Removed   []string `json:"removed"`
+		} `json:"entries"`
+	}{}
+	if err := remarshal(result, &raw); err != nil {
+		return recordedMutations{}, err
+	}
+	recorded := recordedMutations{Total: raw.Total, Mutations: make([]Mutation, len(raw.Entries))}
+	for i, e := range raw.Entries {
+		recorded.Mutations[i] = Mutation{At: time.Duration(e.At * float64(time.Millisecond)), Kind: e.Kind, Node: e.Node, Attribute: e.Attribute, Added: e.Added, Removed: e.Removed}
+	}
+	return recorded, nil
+}
+
+func (l *MutationLog) read() (recordedMutations, error) {
+	r := l.b.runBilobaFunc("mutationLog", l.id)
+	if r.Error() != nil {
+		return recordedMutations{}, r.Error()
+	}
+	return decodeRecordedMutations(r.Result)
+}
+
+func (l *MutationLog) mustRead() recordedMutations {
+	l.b.gt.Helper()
+	recorded, err := l.read()
+	if err != nil {
+		l.b.gt.Fatalf("Failed to read mutation log:\n%s", err.Error())
+	}
+	return recorded
+}
+
+// Mutations returns the timeline: the latest 5000 mutations recorded, oldest first.
+func (l *MutationLog) Mutations() []Mutation {
+	l.b.gt.Helper()
+	return l.mustRead().Mutations
+}
+
+// Count returns how many mutations have been recorded - all of them, not just those the timeline keeps.
+func (l *MutationLog) Count() int {
+	l.b.gt.Helper()
+	return l.mustRead().Total
+}
+
+// CountsByNode returns how many of the timeline's mutations happened to each node.
+func (l *MutationLog) CountsByNode() map[string]int {
+	l.b.gt.Helper()
+	return countMutations(l.mustRead().Mutations, func(m Mutation) []string { return []string{m.Node} })
+}
+
+// CountsByAttribute returns how many of the timeline's attribute mutations changed each attribute.
+func (l *MutationLog) CountsByAttribute() map[string]int {
+	l.b.gt.Helper()
+	return countMutations(l.mustRead().Mutations, func(m Mutation) []string {
+		if m.Attribute == "" {
+			return nil
+		}
+		return []string{m.Attribute}
+	})
+}
+
+// Report renders what the log has recorded: the counts by node and attribute, the elements that were
+// re-mounted, and the tail of the timeline.
+func (l *MutationLog) Report() string {
+	l.b.gt.Helper()
+	return l.mustRead().String()
+}
+
+/*
+Stop stops the log recording.  What it recorded stays readable until the page navigates.
+
+Read https://onsi.github.io/biloba/#recording-mutations to learn more
+*/
+func (l *MutationLog) Stop() {
+	l.b.gt.Helper()
+	if r := l.b.runBilobaFunc("stopRecordingMutations", l.id); r.Error() != nil {
+		l.b.gt.Fatalf("Failed to stop recording mutations:\n%s", r.Error())
+	}
+}
+
+func countMutations(mutations []Mutation, keys func(Mutation) []string) map[string]int {
+	counts := map[string]int{}
+	for _, m := range mutations {
+		for _, k := range keys(m) {
+			counts[k]++
+		}
+	}
+	return counts
+}
+
+// remounts counts, for each element that was removed and added again, how many times it came back.
+func remounts(mutations []Mutation) map[string]int {
+	removed := countMutations(mutations, func(m Mutation) []string { return m.Removed })
+	added := countMutations(mutations, func(m Mutation) []string { return m.Added })
+	counts := map[string]int{}
+	for element, n := range removed {
+		if back := min(n, added[element]); back > 0 {
+			counts[element] = back
+		}
+	}
+	return counts
+}
+
+func (r recordedMutations) String() string {
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "%s", pluralize(r.Total, "mutation"))
+	if len(r.Mutations) < r.Total {
+		fmt.Fprintf(out, " (the counts below cover the latest %d)", len(r.Mutations))
+	}
+	fmt.Fprintf(out, "\n")
+	if len(r.Mutations) == 0 {
+		return out.String()
+	}
+	section := func(title string, counts map[string]int, unit string) {
+		if len(counts) == 0 {
+			return
+		}
+		keys := make([]string, 0, len(counts))
+		for k := range counts {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if counts[keys[i]] != counts[keys[j]] {
+				return counts[keys[i]] > counts[keys[j]]
+			}
+			return keys[i] < keys[j]
+		})
+		fmt.Fprintf(out, "%s:\n", title)
+		for i, k := range keys {
+			if i == maxChurnRows {
+				fmt.Fprintf(out, "  ...and %d more\n", len(keys)-maxChurnRows)
+				break
+			}
+			fmt.Fprintf(out, "  %5d%s %s\n", counts[k], unit, k)
+		}
+	}
+	section("By node", countMutations(r.Mutations, func(m Mutation) []string { return []string{m.Node} }), "")
+	section("By attribute", countMutations(r.Mutations, func(m Mutation) []string {
+		if m.Attribute == "" {
+			return nil
+		}
+		return []string{m.Attribute}
+	}), "")
+	section("Re-mounted (removed, then added again)", remounts(r.Mutations), "×")
+	tail := r.Mutations
+	if len(tail) > maxChurnRows*2 {
+		fmt.Fprintf(out, "Timeline (the latest %d):\n", maxChurnRows*2)
+		tail = tail[len(tail)-maxChurnRows*2:]
+	} else {
+		fmt.Fprintf(out, "Timeline:\n")
+	}
+	for _, m := range tail {
+		fmt.Fprintf(out, "  %s\n", m)
+	}
+	return out.String()
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+/*
+HaveMutatedAtMost(n) is a Gomega matcher that passes when the [MutationLog] it is applied to has
+recorded at most n mutations - a budget that catches a re-render storm before it becomes a flake:
+
+	rows := b.RecordMutations("#orders")
+	b.Type("#filter", "shipped")
+	Eventually("#orders").Should(b.HaveTableRows(HaveLen(3)))
+	Ω(rows).Should(b.HaveMutatedAtMost(40))
+
+The failure prints the log's [MutationLog.Report]: what churned, how often, and what was re-mounted.
+It returns a [ValueMatcher], so you can keep the count with .Capture(&count).
+
+Read https://onsi.github.io/biloba/#recording-mutations to learn more
+*/
+func (b *Biloba) HaveMutatedAtMost(n int) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveMutatedAtMost")
+	data := map[string]any{"N": n}
+	return capturableResult(gcustom.MakeMatcher(func(actual any) (bool, error) {
+		l, ok := actual.(*MutationLog)
+		if !ok {
+			return false, fmt.Errorf("HaveMutatedAtMost must be applied to the *biloba.MutationLog that RecordMutations returns.  Got:\n%T", actual)
+		}
+		recorded, err := l.read()
+		if err != nil {
+			return false, err
+		}
+		data["Result"] = recorded.Total
+		data["Report"] = recorded
+		return recorded.Total <= n, nil
+	}).WithTemplate("Expected {{.Actual}} {{.To}} have mutated at most {{.Data.N}} times.  It recorded {{.Data.Report}}", data), data)
+}
+
+// churnReport renders what the document-wide recorder BilobaConfigFailureMutations installs saw from
+// since on, or "" when it saw nothing (or isn't running in this tab's document).  It hands the page
+// how long ago since was, not the time itself: the browser's clock need not agree with ours.
+func (b *Biloba) churnReport(since time.Time) string {
+	r := b.runBilobaFunc("churnSince", float64(time.Since(since).Microseconds())/1000)
+	if r.Error() != nil || r.Result == nil {
+		return ""
+	}
+	recorded, err := decodeRecordedMutations(r.Result)
+	if err != nil || recorded.Total == 0 {
+		return ""
+	}
+	return recorded.String()
+}
@@ -0,0 +1,58 @@
+package biloba
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like probe_trajectory_internal_test.go: they exercise how a mutation
+// recording is counted and rendered.  No browser.
+
+func TestMutationReport(t *testing.T) {
+	g := NewWithT(t)
+	remount := func(at time.Duration) Mutation {
+		return Mutation{At: at, Kind: "childList", Node: "ul#list", Added: []string{"li#row-1"}, Removed: []string{"li#row-1"}}
+	}
+	toggle := func(at time.Duration) Mutation {
+		return Mutation{At: at, Kind: "attributes", Node: "span#badge", Attribute: "class"}
+	}
+
+	recorded := recordedMutations{Total: 4, Mutations: []Mutation{remount(10 * time.Millisecond), toggle(20 * time.Millisecond), remount(30 * time.Millisecond), toggle(40 * time.Millisecond)}}
+	g.Expect(remounts(recorded.Mutations)).To(Equal(map[string]int{"li#row-1": 2}))
+	g.Expect(recorded.String()).To(Equal("4 mutations\n" +
+		"By node:\n" +
+		"      2 span#badge\n" +
+		"      2 ul#list\n" +
+		"By attribute:\n" +
+		"      2 class\n" +
+		"Re-mounted (removed, then added again):\n" +
+		"      2× li#row-1\n" +
+		"Timeline:\n" +
+		"  +0.01s childList ul#list +li#row-1 -li#row-1\n" +
+		"  +0.02s attributes span#badge [class]\n" +
+		"  +0.03s childList ul#list +li#row-1 -li#row-1\n" +
+		"  +0.04s attributes span#badge [class]\n"))
+
+	// an element that was only removed, or only added, was not re-mounted
+	g.Expect(remounts([]Mutation{{Removed: []string{"li#a"}}, {Added: []string{"li#b"}}})).To(BeEmpty())
+
+	// nothing recorded renders as just the count
+	g.Expect(recordedMutations{}.String()).To(Equal("0 mutations\n"))
+
+	// a long recording ranks the top nodes and shows only the tail of the timeline - and says so when
+	// the page dropped entries the counts can't cover
+	var mutations []Mutation
+	for i := range 30 {
+		mutations = append(mutations, Mutation{At: time.Duration(i) * time.Millisecond, Kind: "attributes", Node: fmt.Sprintf("div#n-%02d", i), Attribute: "style"})
+	}
+	out := recordedMutations{Total: 6000, Mutations: mutations}.String()
+	g.Expect(out).To(HavePrefix("6000 mutations (the counts below cover the latest 30)\n"))
+	g.Expect(out).To(ContainSubstring("  ...and 20 more\n"))
+	g.Expect(out).To(ContainSubstring("     30 style\n"))
+	g.Expect(out).To(ContainSubstring("Timeline (the latest 20):\n"))
+	g.Expect(out).NotTo(ContainSubstring("+0.00s"))
+	g.Expect(out).To(HaveSuffix("  +0.03s attributes div#n-29 [style]\n"))
+}
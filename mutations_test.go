@@ -0,0 +1,127 @@
+package biloba_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recording mutations", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/mutations.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	It("counts mutations by node and by attribute", func() {
+		panel := b.RecordMutations("#panel")
+		b.Click("#remount")
+		Eventually("#status").Should(b.HaveInnerText("Remounted"))
+		b.Click("#toggle")
+		Eventually("#status").Should(b.HaveInnerText("Toggled"))
+
+		Ω(panel.Count()).Should(Equal(7))
+		Ω(panel.CountsByNode()).Should(Equal(map[string]int{"ul#list": 3, "span#badge.active": 2, "span#badge": 2}))
+		Ω(panel.CountsByAttribute()).Should(Equal(map[string]int{"class": 4}))
+	})
+
+	It("keeps a timeline", func() {
+		panel := b.RecordMutations("#panel")
+		b.Click("#remount")
+		Eventually("#status").Should(b.HaveInnerText("Remounted"))
+
+		mutations := panel.Mutations()
+		Ω(mutations).Should(HaveLen(3))
+		for _, m := range mutations {
+			Ω(m.Kind).Should(Equal("childList"))
+			Ω(m.Node).Should(Equal("ul#list"))
+			Ω(m.Added).Should(Equal([]string{"li#row-1", "li#row-2"}))
+			Ω(m.Removed).Should(Equal([]string{"li#row-1", "li#row-2"}))
+		}
+		Ω(mutations[2].At).Should(BeNumerically(">=", mutations[0].At+10*time.Millisecond))
+		Ω(mutations[0].String()).Should(MatchRegexp(`^\+\d+\.\d\ds childList ul#list \+li#row-1 \+li#row-2 -li#row-1 -li#row-2$`))
+	})
+
+	It("ignores mutations outside the elements the selector matches", func() {
+		list := b.RecordMutations("#list")
+		b.Click("#toggle")
+		Eventually("#status").Should(b.HaveInnerText("Toggled"))
+		b.Click("#tick")
+		Eventually("#status").Should(b.HaveInnerText("Ticked"))
+		Ω(list.Count()).Should(Equal(0))
+		Ω(list).Should(b.HaveMutatedAtMost(0))
+	})
+
+	It("passes HaveMutatedAtMost within budget, and captures the count", func() {
+		panel := b.RecordMutations("#panel")
+		b.Click("#remount")
+		Eventually("#status").Should(b.HaveInnerText("Remounted"))
+
+		var count int
+		Ω(panel).Should(b.HaveMutatedAtMost(3).Capture(&count))
+		Ω(count).Should(Equal(3))
+	})
+
+	It("reports what churned when HaveMutatedAtMost fails", func() {
+		panel := b.RecordMutations("#panel")
+		b.Click("#remount")
+		Eventually("#status").Should(b.HaveInnerText("Remounted"))
+		b.Click("#toggle")
+		Eventually("#status").Should(b.HaveInnerText("Toggled"))
+
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect(panel).To(b.HaveMutatedAtMost(2))
+		Ω(failure).Should(ContainSubstring("mutations(#panel)"))
+		Ω(failure).Should(ContainSubstring("to have mutated at most 2 times.  It recorded 7 mutations"))
+		Ω(failure).Should(MatchRegexp(`By node:\n\s+3 ul#list\n`))
+		Ω(failure).Should(MatchRegexp(`By attribute:\n\s+4 class\n`))
+		Ω(failure).Should(MatchRegexp(`Re-mounted \(removed, then added again\):\n\s+3× li#row-1\n\s+3× li#row-2\n`))
+		Ω(failure).Should(MatchRegexp(`Timeline:\n\s+\+\d+\.\d\ds childList ul#list`))
+		Ω(panel.Report()).Should(HavePrefix("7 mutations\n"))
+	})
+
+	It("stops recording when stopped", func() {
+		panel := b.RecordMutations("#panel")
+		b.Click("#remount")
+		Eventually("#status").Should(b.HaveInnerText("Remounted"))
+		panel.Stop()
+		b.Click("#toggle")
+		Eventually("#status").Should(b.HaveInnerText("Toggled"))
+		Ω(panel.Count()).Should(Equal(3))
+	})
+
+	It("fails when applied to anything but a mutation log, or after the page navigates", func() {
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect("#panel").To(b.HaveMutatedAtMost(1))
+		Ω(failure).Should(ContainSubstring("HaveMutatedAtMost must be applied to the *biloba.MutationLog that RecordMutations returns"))
+
+		panel := b.RecordMutations("#panel")
+		b.Navigate(fixtureServer + "/mutations.html")
+		Eventually("#heading").Should(b.Exist())
+		panel.Count()
+		ExpectFailures(ContainSubstring("this mutation log is not in this document - the page has navigated since RecordMutations"))
+	})
+
+	Describe("the failure artifact", func() {
+		BeforeEach(func() {
+			b.StartChurnRecorderForTest()
+			b.ResetPollDiagnosticsForTest()
+		})
+
+		It("reports what churned during the last poll", func() {
+			b.Click("#remount")
+			Eventually("#status").Should(b.HaveInnerText("Remounted"))
+			note := b.ChurnNoteForTest()
+			Ω(note).Should(ContainSubstring("ul#list"))
+			Ω(note).Should(MatchRegexp(`\d× li#row-1`))
+			Ω(note).Should(ContainSubstring("div#status"))
+		})
+
+		It("stays quiet when nothing churned", func() {
+			Eventually("#status").Should(b.HaveInnerText("Idle"))
+			Ω(b.ChurnNoteForTest()).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,209 @@
+package biloba
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+)
+
+// OptionLabel names an option for [Biloba.SelectOption] by its visible label.
+type OptionLabel string
+
+// OptionIndex names an option for [Biloba.SelectOption] by its zero-based position.
+type OptionIndex int
+
+func (o OptionLabel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"label": string(o)})
+}
+
+func (o OptionIndex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int{"index": int(o)})
+}
+
+/*
+SelectOption(selector, options...) selects options by what the user sees - their label - or by their
+position, rather than by the value attribute [Biloba.SetValue] matches:
+
+	b.SelectOption("#country", biloba.OptionLabel("Canada"))
+	b.SelectOption("#country", biloba.OptionIndex(0))
+	b.SelectOption("#toppings", biloba.OptionLabel("Cheese"), biloba.OptionLabel("Olives"))
+
+A plain string is taken as a label, and a plain int as an index.  selector can be a native <select> -
+for a <select multiple>, the options you name become the whole selection - or an ARIA widget:
+
+  - an element with role listbox: each option (role option, matched by accessible name) is clicked.
+    In an aria-multiselectable listbox, as in a <select multiple>, the options you name become the
+    whole selection: one that is already selected is left alone, and any other selected option is
+    clicked to deselect it.  Any other listbox - a combobox's too - takes one option, as a single
+    <select> does.
+  - an element with role combobox: it is clicked open first, when its listbox isn't showing, and then
+    the option is clicked.  The listbox is the one the combobox names in aria-controls or aria-owns,
+    one inside it, or else the only listbox on the page that is showing.
+
+Clicks are Biloba's clicks, so a Realistic() view clicks for real.  An option the ARIA widget hasn't
+rendered yet is waited for.  SelectOption polls, and honors WithTimeout, WithPolling, WithContext and
+Immediate; the failure lists the options there are.
+
+Read https://onsi.github.io/biloba/#selecting-options-by-label to learn more
+*/
+func (b *Biloba) SelectOption(selector any, options ...any) {
+	b.gt.Helper()
+	specs := []any{}
+	for _, option := range options {
+		switch o := option.(type) {
+		case OptionLabel, OptionIndex:
+			specs = append(specs, o)
+		case string:
+			specs = append(specs, OptionLabel(o))
+		case int:
+			specs = append(specs, OptionIndex(o))
+		default:
+			b.gt.Fatalf("SelectOption takes biloba.OptionLabel or biloba.OptionIndex options.  Got:\n%s", format.Object(option, 1))
+			return
+		}
+	}
+	if len(specs) == 0 {
+		b.gt.Fatalf("SelectOption needs at least one option to select")
+		return
+	}
+	var kind string
+	classify := gcustom.MakeMatcher(func(sel any) (bool, error) {
+		r := b.runBilobaHandler("optionWidget", sel)
+		if r.Error() != nil {
+			return false, r.Error()
+		}
+		kind = r.ResultString()
+		return true, nil
+	}).WithMessage("be a <select>, a listbox or a combobox")
+	if !b.pollOrImmediate(selector, classify) {
+		return
+	}
+	if kind == "select" {
+		b.pollOrImmediate(selector, gcustom.MakeMatcher(func(sel any) (bool, error) {
+			return b.runBilobaHandler("selectOptions", sel, specs).MatcherResult()
+		}).WithMessage("have the options to select"))
+		return
+	}
+	for _, spec := range specs {
+		if !b.pollOrImmediate(selector, b.ariaOptionMatcher(kind, spec, len(specs))) {
+			return
+		}
+	}
+	b.pollOrImmediate(selector, gcustom.MakeMatcher(func(sel any) (bool, error) {
+		r := b.runBilobaHandler("unnamedSelectedOption", sel, specs)
+		if r.Error() != nil || r.ResultString() == "" {
+			return r.Error() == nil, r.Error()
+		}
+		// deselect it, and check again once the widget has re-rendered
+		_, err := b.performClick(r.ResultString(), pointerConfig{})
+		return false, err
+	}).WithMessage("have only the options named selected"))
+}
+
+// ariaOptionMatcher clicks the option spec names in the ARIA listbox or combobox it is applied to - one
+// of count options to select, which only a multi-select listbox takes.  A combobox whose listbox isn't
+// showing is clicked open - once: a widget that is slow to open is waited for, not toggled shut again
+// by the next poll.
+func (b *Biloba) ariaOptionMatcher(kind string, spec any, count int) types.GomegaMatcher {
+	opened := false
+	data := map[string]any{"Option": describeOption(spec)}
+	return gcustom.MakeMatcher(func(sel any) (bool, error) {
+		r := b.runBilobaHandler("findOption", sel, spec)
+		if r.Error() != nil {
+			return false, r.Error()
+		}
+		found := struct {
+			Open     bool     `json:"open"`
+			Multiple bool     `json:"multiple"`
+			Path     string   `json:"path"`
+			Selected bool     `json:"selected"`
+			Options  []string `json:"options"`
+		}{}
+		if err := remarshal(r.Result, &found); err != nil {
+			return false, err
+		}
+		if !found.Open {
+			delete(data, "Options")
+			if kind == "combobox" && !opened {
+				opened = true
+				if ok, err := b.performClick(sel, pointerConfig{}); !ok || err != nil {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		if !found.Multiple && count > 1 {
+			return false, fmt.Errorf("A single-select input takes one option - got %d", count)
+		}
+		data["Options"] = formatOptionLabels(found.Options)
+		if found.Path == "" {
+			return false, nil
+		}
+		if found.Selected {
+			return true, nil
+		}
+		return b.performClick(found.Path, pointerConfig{})
+	}).WithTemplate("Expected {{.Actual}} to have an option {{.Data.Option}} to click{{if .Data.Options}}.  Its options are: {{.Data.Options}}{{else}}, but its listbox never showed{{end}}", data)
+}
+
+func describeOption(spec any) string {
+	if index, ok := spec.(OptionIndex); ok {
+		return fmt.Sprintf("at index %d", index)
+	}
+	return fmt.Sprintf("labelled %q", spec)
+}
+
+func formatOptionLabels(labels []string) string {
+	quoted := make([]string, len(labels))
+	for i, label := range labels {
+		quoted[i] = fmt.Sprintf("%q", label)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+/*
+HaveSelectedOptionLabels(expected...) is a Gomega matcher that passes once the labels of the selected
+options of the <select>, listbox or combobox it is applied to are exactly expected, in order:
+
+	Eventually("#country").Should(b.HaveSelectedOptionLabels("Canada"))
+	Eventually("#toppings").Should(b.HaveSelectedOptionLabels("Cheese", "Olives"))
+
+Pass a single Gomega matcher to match the []string of labels as a whole:
+
+	Eventually(b.ByRole("listbox")).Should(b.HaveSelectedOptionLabels(ContainElement("Olives")))
+
+A listbox's selected options are those with aria-selected="true".  A combobox's are the aria-selected
+options of its listbox when it has one in the page; when it doesn't - most close and remove it - it is
+the value (of an <input>) or text the combobox shows.  It returns a [ValueMatcher], so you can keep
+the labels with .Capture(&labels).
+
+Read https://onsi.github.io/biloba/#selecting-options-by-label to learn more
+*/
+func (b *Biloba) HaveSelectedOptionLabels(expected ...any) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveSelectedOptionLabels")
+	var matcher types.GomegaMatcher
+	if len(expected) == 1 {
+		if m, ok := expected[0].(types.GomegaMatcher); ok {
+			matcher = m
+		}
+	}
+	if matcher == nil {
+		matcher = gomega.HaveExactElements(expected...)
+	}
+	data := map[string]any{"Matcher": matcher}
+	return capturableResult(gcustom.MakeMatcher(func(selector any) (bool, error) {
+		r := b.runBilobaHandler("selectedOptionLabels", selector)
+		if r.Error() != nil {
+			return false, r.Error()
+		}
+		labels := toStringSlice(r.Result)
+		data["Result"] = labels
+		return matcher.Match(labels)
+	}).WithTemplate("HaveSelectedOptionLabels for {{.Actual}}:\n{{if .Failure}}{{.Data.Matcher.FailureMessage .Data.Result}}{{else}}{{.Data.Matcher.NegatedFailureMessage .Data.Result}}{{end}}", data), data)
+}
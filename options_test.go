@@ -0,0 +1,115 @@
+package biloba_test
+
+import (
+	"time"
+
+	"github.com/onsi/biloba"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Selecting options by label", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/options.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	Describe("native selects", func() {
+		It("selects an option by label or by index, firing change", func() {
+			b.SelectOption("#country", biloba.OptionLabel("Mexico"))
+			Ω("#country").Should(b.HaveValue("c-42"))
+			Ω("#country").Should(b.HaveSelectedOptionLabels("Mexico"))
+
+			b.SelectOption("#country", biloba.OptionIndex(1))
+			Ω("#country").Should(b.HaveSelectedOptionLabels("Canada"))
+			Ω("#log").Should(b.HaveInnerText("country:c-42,country:c-17,"))
+		})
+
+		It("takes plain strings and ints too", func() {
+			b.SelectOption("#country", "Mexico")
+			Ω("#country").Should(b.HaveValue("c-42"))
+			b.SelectOption("#country", 0)
+			Ω("#country").Should(b.HaveValue(""))
+		})
+
+		It("makes the named options the whole selection of a multi-select", func() {
+			Ω("#toppings").Should(b.HaveSelectedOptionLabels("Cheese"))
+			b.SelectOption("#toppings", biloba.OptionLabel("Olives"), biloba.OptionLabel("Peppers"))
+			Ω("#toppings").Should(b.HaveSelectedOptionLabels("Olives", "Peppers"))
+			Ω("#toppings").Should(b.HaveValue(ConsistOf("t-2", "t-3")))
+		})
+
+		It("fails on a missing or disabled option, listing the options", func() {
+			b.Immediate().SelectOption("#country", biloba.OptionLabel("Peru"))
+			ExpectFailures(ContainSubstring(`Select input does not have an option labelled "Peru" - the options are: "Choose one", "Canada", "Mexico", "Atlantis"`))
+			b.Immediate().SelectOption("#country", biloba.OptionLabel("Atlantis"))
+			ExpectFailures(ContainSubstring(`The "Atlantis" option is not enabled`))
+			b.Immediate().SelectOption("#country", biloba.OptionIndex(7))
+			ExpectFailures(ContainSubstring(`Select input does not have an option at index 7`))
+		})
+
+		It("refuses several options for a single select", func() {
+			b.Immediate().SelectOption("#country", "Canada", "Mexico")
+			ExpectFailures(ContainSubstring("A single-select input takes one option - got 2"))
+		})
+	})
+
+	Describe("ARIA listboxes", func() {
+		It("clicks the option with the accessible name", func() {
+			b.SelectOption("#colors", biloba.OptionLabel("Green"))
+			Ω("#colors").Should(b.HaveSelectedOptionLabels("Green"))
+			b.SelectOption(b.ByRole("listbox").WithName("Colors"), biloba.OptionIndex(2))
+			Ω("#colors").Should(b.HaveSelectedOptionLabels(ContainElement("Blue")))
+			Ω("#log").Should(b.HaveInnerText("color:Green,color:Blue,"))
+		})
+
+		It("makes the named options the whole selection of a multi-select listbox", func() {
+			Ω("#sizes").Should(b.HaveSelectedOptionLabels("Small", "Large"))
+			b.SelectOption("#sizes", biloba.OptionLabel("Medium"), biloba.OptionLabel("Large"))
+			Ω("#sizes").Should(b.HaveSelectedOptionLabels("Medium", "Large"))
+			Ω("#log").Should(b.HaveInnerText("size:Medium,size:Small,"))
+		})
+
+		It("refuses several options for a single-select listbox, without clicking any", func() {
+			b.Immediate().SelectOption("#colors", "Green", "Blue")
+			ExpectFailures(ContainSubstring("A single-select input takes one option - got 2"))
+			Ω("#log").Should(b.HaveInnerText(""))
+		})
+
+		It("lists the options when the one named isn't there", func() {
+			b.WithTimeout(200*time.Millisecond).SelectOption("#colors", biloba.OptionLabel("Purple"))
+			ExpectFailures(ContainSubstring(`to have an option labelled "Purple" to click.  Its options are: "Red", "Green", "Blue"`))
+		})
+	})
+
+	Describe("ARIA comboboxes", func() {
+		It("opens the combobox, waits for the option and clicks it", func() {
+			Ω("#fruit").Should(b.HaveSelectedOptionLabels("Pick a fruit"))
+			b.SelectOption("#fruit", biloba.OptionLabel("Banana"))
+			Ω("#fruit").Should(b.HaveSelectedOptionLabels("Banana"))
+			Ω("#fruit").Should(b.HaveInnerText("Banana"))
+
+			b.SelectOption("#fruit", biloba.OptionIndex(2))
+			Ω("#fruit").Should(b.HaveSelectedOptionLabels("Cherry"))
+			Ω("#log").Should(b.HaveInnerText("fruit:Banana,fruit:Cherry,"))
+		})
+
+		It("reads an input combobox's value once its listbox is gone", func() {
+			Ω("#city").Should(b.HaveSelectedOptionLabels())
+			b.SelectOption("#city", "Oslo")
+			Ω("#city-list").ShouldNot(b.Exist())
+			Ω("#city").Should(b.HaveSelectedOptionLabels("Oslo"))
+			Ω("#city").Should(b.HaveValue("Oslo"))
+		})
+
+		It("works in realistic mode", func() {
+			b.Realistic().SelectOption("#fruit", "Apple")
+			Ω("#fruit").Should(b.HaveSelectedOptionLabels("Apple"))
+		})
+	})
+
+	It("fails on an element that isn't a select, listbox or combobox", func() {
+		b.Immediate().SelectOption("#heading", "Canada")
+		ExpectFailures(ContainSubstring("element is not a <select>, or an element with role listbox or combobox"))
+	})
+})
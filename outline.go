@@ -66,6 +66,21 @@ func (b *Biloba) Outline() string {
 	return capOutline(resp.ResultString())
 }
 
+// outlineOf is Outline's uncapped core: the whole body, or - given a selector - the element matching
+// it and its subtree.
+func (b *Biloba) outlineOf(selector any) (string, error) {
+	var resp *bilobaJSResponse
+	if selector == nil {
+		resp = b.runBilobaFunc("outline")
+	} else {
+		resp = b.runBilobaHandler("outline", selector)
+	}
+	if resp.Error() != nil {
+		return "", resp.Error()
+	}
+	return resp.ResultString(), nil
+}
+
 type tabOutline struct {
 	title   string
 	text    string
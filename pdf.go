@@ -0,0 +1,194 @@
+package biloba
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/onsi/gomega/gcustom"
+)
+
+// pdfPrintTimeout bounds one PrintToPDF.  Printing lays the whole document out again at paper size and
+// embeds its fonts, which on a long page takes noticeably longer than a screenshot.
+const pdfPrintTimeout = 30 * time.Second
+
+/*
+PDFOptions configures [Biloba.PrintToPDF].  The zero value prints the way Chrome's print dialog does by default: US Letter, portrait, with Chrome's default margins, and without background graphics.
+
+	pdf := b.PrintToPDF(biloba.PDFOptions{
+		PaperWidth: 8.27, PaperHeight: 11.69, // A4
+		Margins:         &biloba.PDFMargins{Top: 0.5, Bottom: 0.5, Left: 0.75, Right: 0.75},
+		PrintBackground: true,
+		PageRanges:      "1-2",
+	})
+
+Read https://onsi.github.io/biloba/#printing-to-pdf to learn more
+*/
+type PDFOptions struct {
+	// PaperWidth and PaperHeight are in inches.  Zero means US Letter (8.5 x 11).
+	PaperWidth, PaperHeight float64
+	// Margins are in inches.  nil means Chrome's default margins (0.4in); &PDFMargins{} means none.
+	Margins *PDFMargins
+	// Landscape prints in landscape orientation.
+	Landscape bool
+	// PrintBackground prints background colours and images, like the print dialog's "Background graphics".
+	PrintBackground bool
+	// PageRanges selects pages to print, e.g. "1-5, 8, 11-13".  Empty means every page.
+	PageRanges string
+	// Scale scales the rendering; zero means 1.
+	Scale float64
+	// PreferCSSPageSize lets an @page size rule in the page's CSS win over PaperWidth and PaperHeight.
+	PreferCSSPageSize bool
+}
+
+/*
+PDFMargins are the page margins, in inches, for [PDFOptions].
+*/
+type PDFMargins struct {
+	Top, Bottom, Left, Right float64
+}
+
+func (o PDFOptions) params() *page.PrintToPDFParams {
+	params := page.PrintToPDF().
+		WithLandscape(o.Landscape).
+		WithPrintBackground(o.PrintBackground).
+		WithPreferCSSPageSize(o.PreferCSSPageSize)
+	if o.PaperWidth > 0 {
+		params = params.WithPaperWidth(o.PaperWidth)
+	}
+	if o.PaperHeight > 0 {
+		params = params.WithPaperHeight(o.PaperHeight)
+	}
+	if o.Margins != nil {
+		params = params.WithMarginTop(o.Margins.Top).WithMarginBottom(o.Margins.Bottom).WithMarginLeft(o.Margins.Left).WithMarginRight(o.Margins.Right)
+	}
+	if o.PageRanges != "" {
+		params = params.WithPageRanges(o.PageRanges)
+	}
+	if o.Scale > 0 {
+		params = params.WithScale(o.Scale)
+	}
+	return params
+}
+
+/*
+PrintToPDF(options) prints the current page to PDF - through the same print pipeline, and the same print stylesheets, as the browser's print dialog - and returns the PDF's bytes:
+
+	pdf := b.PrintToPDF(biloba.PDFOptions{PrintBackground: true})
+	Ω(pdf).Should(b.HavePDFPageCount(1))
+	Ω(pdf).Should(b.HavePDFText(ContainSubstring("Total due: $1,250.00")))
+
+It is a waiting command bounded by its own ~30s default deadline; override that with [Biloba.WithTimeout] or abort it with [Biloba.WithContext] (WithPolling and Immediate are not supported).
+
+Read https://onsi.github.io/biloba/#printing-to-pdf to learn more
+*/
+func (b *Biloba) PrintToPDF(options PDFOptions) []byte {
+	b.gt.Helper()
+	b.guardConfig("PrintToPDF", knobTimeout, knobContext)
+	pdf, err := b.printToPDF(options)
+	if err != nil {
+		b.gt.Fatalf("Failed to print to PDF:\n%s", err.Error())
+	}
+	return pdf
+}
+
+func (b *Biloba) printToPDF(options PDFOptions) ([]byte, error) {
+	ctx, cancel := b.waitingContext(pdfPrintTimeout)
+	defer cancel()
+	var pdf []byte
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		pdf, _, err = options.params().Do(ctx)
+		return err
+	}))
+	return pdf, err
+}
+
+/*
+CapturePDFToFile(path, options) prints the current page to PDF (see [Biloba.PrintToPDF]) and writes it to path, returning the absolute path.  The directory is created if it does not already exist, and the path is printed to the test output so it appears in failure output.
+
+Read https://onsi.github.io/biloba/#printing-to-pdf to learn more
+*/
+func (b *Biloba) CapturePDFToFile(path string, options PDFOptions) string {
+	b.gt.Helper()
+	b.guardConfig("CapturePDFToFile", knobTimeout, knobContext)
+	pdf, err := b.printToPDF(options)
+	if err != nil {
+		b.gt.Fatalf("Failed to print to PDF:\n%s", err.Error())
+		return ""
+	}
+	return b.writeArtifactToFile("PDF", pdf, path)
+}
+
+/*
+HavePDFPageCount(n) is a Gomega matcher that passes if a PDF has n pages.  Apply it to the []byte [Biloba.PrintToPDF] returns, or to the tab itself to print it (with the default [PDFOptions]) on every attempt:
+
+	Ω(b.PrintToPDF(biloba.PDFOptions{})).Should(b.HavePDFPageCount(2))
+	Eventually(b).Should(b.HavePDFPageCount(2))
+
+It returns a [ValueMatcher], so you can keep the page count with .Capture(&n).
+
+Read https://onsi.github.io/biloba/#printing-to-pdf to learn more
+*/
+func (b *Biloba) HavePDFPageCount(n int) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HavePDFPageCount")
+	data := map[string]any{"Expected": n}
+	return capturableResult(gcustom.MakeMatcher(func(actual any) (bool, error) {
+		pdf, err := b.pdfSubject("HavePDFPageCount", actual)
+		if err != nil {
+			return false, err
+		}
+		count, err := pdfPageCount(pdf)
+		if err != nil {
+			return false, fmt.Errorf("HavePDFPageCount could not read the PDF: %w", err)
+		}
+		data["Result"] = count
+		return count == n, nil
+	}).WithTemplate("Expected the PDF {{.To}} have {{.Data.Expected}} page(s), but it has {{.Data.Result}}", data), data)
+}
+
+/*
+HavePDFText(expected) is a Gomega matcher that passes if the text of a PDF matches expected.  expected can be a string, or a Gomega matcher.  Apply it to the []byte [Biloba.PrintToPDF] returns, or to the tab itself to print it (with the default [PDFOptions]) on every attempt:
+
+	Ω(pdf).Should(b.HavePDFText(ContainSubstring("INV-2041")))
+	Eventually(b).Should(b.HavePDFText(MatchRegexp(`Total due:\s+\$1,250\.00`)))
+
+The text is extracted in reading order, one line per line of printed text, with the pages separated by a form feed ("\f") - so a string expected has to match the whole document, and ContainSubstring is usually what you want.  Extraction recovers words and lines, not layout: columns of a table come out as the words of each row, separated by spaces.
+
+It returns a [ValueMatcher], so you can keep the extracted text with .Capture(&text).
+
+Read https://onsi.github.io/biloba/#printing-to-pdf to learn more
+*/
+func (b *Biloba) HavePDFText(expected any) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HavePDFText")
+	matcher := matcherOrEqual(expected)
+	data := map[string]any{"Matcher": matcher}
+	return capturableResult(gcustom.MakeMatcher(func(actual any) (bool, error) {
+		pdf, err := b.pdfSubject("HavePDFText", actual)
+		if err != nil {
+			return false, err
+		}
+		pages, err := pdfPageTexts(pdf)
+		if err != nil {
+			return false, fmt.Errorf("HavePDFText could not read the PDF: %w", err)
+		}
+		data["Result"] = strings.Join(pages, "\f")
+		return matcher.Match(data["Result"])
+	}).WithTemplate("HavePDFText:\n{{if .Failure}}{{.Data.Matcher.FailureMessage .Data.Result}}{{else}}{{.Data.Matcher.NegatedFailureMessage .Data.Result}}{{end}}", data), data)
+}
+
+// pdfSubject resolves what a PDF matcher was applied to: PDF bytes as they are, or a tab to print now.
+func (b *Biloba) pdfSubject(method string, actual any) ([]byte, error) {
+	switch x := actual.(type) {
+	case []byte:
+		return x, nil
+	case *Biloba:
+		return x.printToPDF(PDFOptions{})
+	}
+	return nil, fmt.Errorf("%s takes the []byte PrintToPDF returns, or a tab to print.  Got %T", method, actual)
+}
@@ -0,0 +1,82 @@
+package biloba_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/onsi/biloba"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Printing to PDF", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/invoice.html")
+		Eventually("#hello").Should(b.Exist())
+	})
+
+	It("prints the page, with its print stylesheet, to a PDF", func() {
+		pdf := b.PrintToPDF(biloba.PDFOptions{})
+		Ω(string(pdf[:5])).Should(Equal("%PDF-"))
+		Ω(pdf).Should(b.HavePDFPageCount(3))
+		Ω(pdf).Should(b.HavePDFText(SatisfyAll(
+			ContainSubstring("Invoice INV-2041"),
+			ContainSubstring("Widget 2 $1,250.00"),
+			ContainSubstring("Total due: $1,250.00"),
+			Not(ContainSubstring("Print this page")),
+		)))
+	})
+
+	It("separates pages with a form feed", func() {
+		var text string
+		Ω(b.PrintToPDF(biloba.PDFOptions{})).Should(b.HavePDFText(ContainSubstring("\fTotal due")).Capture(&text))
+		Ω(text).Should(HaveSuffix("\fTerms and conditions"))
+	})
+
+	It("honours page ranges and paper options", func() {
+		pdf := b.PrintToPDF(biloba.PDFOptions{
+			PaperWidth: 8.27, PaperHeight: 11.69,
+			Margins:         &biloba.PDFMargins{},
+			Landscape:       true,
+			PrintBackground: true,
+			PageRanges:      "2-3",
+		})
+		Ω(pdf).Should(b.HavePDFPageCount(2))
+		Ω(pdf).Should(b.HavePDFText(Not(ContainSubstring("INV-2041"))))
+	})
+
+	It("can poll the tab itself", func() {
+		b.SetProperty("#total", "innerText", "$0.00")
+		Eventually(b).Should(b.HavePDFText(ContainSubstring("Total due: $0.00")))
+		Eventually(b).Should(b.HavePDFPageCount(3))
+	})
+
+	It("fails with a useful message", func() {
+		pdf := b.PrintToPDF(biloba.PDFOptions{})
+		matcher := b.HavePDFPageCount(1)
+		Ω(matcher.Match(pdf)).Should(BeFalse())
+		Ω(matcher.FailureMessage(pdf)).Should(Equal("Expected the PDF to have 1 page(s), but it has 3"))
+
+		_, err := b.HavePDFText("x").Match("#total")
+		Ω(err).Should(MatchError("HavePDFText takes the []byte PrintToPDF returns, or a tab to print.  Got string"))
+		_, err = b.HavePDFText("x").Match([]byte("not a pdf"))
+		Ω(err).Should(MatchError(ContainSubstring("HavePDFText could not read the PDF: not a PDF")))
+	})
+
+	It("writes a PDF to a file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "nested", "invoice.pdf")
+		Ω(b.CapturePDFToFile(path, biloba.PDFOptions{})).Should(Equal(path))
+		raw, err := os.ReadFile(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(raw).Should(b.HavePDFPageCount(3))
+		Ω(gt.buffer).Should(gbytes.Say("PDF written to: " + regexp.QuoteMeta(path)))
+	})
+
+	It("rejects poll-config knobs it does not support", func() {
+		b.WithPolling(time.Second).PrintToPDF(biloba.PDFOptions{})
+		ExpectFailures(ContainSubstring("PrintToPDF does not support WithPolling"))
+	})
+})
@@ -0,0 +1,1054 @@
+package biloba
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// This file is a deliberately small PDF reader: just enough to count the pages of, and pull the text out
+// of, what Chrome's print pipeline (Skia's PDF backend) writes.  That output is regular - classic
+// cross-reference tables, FlateDecode content streams, embedded subset fonts with ToUnicode maps - so
+// the reader recovers objects by scanning the file front to back instead of trusting the xref table,
+// and gives up with an error, rather than guessing, on anything outside that envelope (an encrypted
+// document, an unsupported stream filter).  It is not a general-purpose PDF library and is not trying
+// to become one: HavePDFText needs the words in reading order, not a faithful layout.
+
+type pdfName string
+type pdfDict map[pdfName]any
+type pdfRef struct{ num, gen int }
+
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte
+}
+
+type pdfKeyword string
+
+// pdfDocument is a parsed PDF: every object we found, keyed by object number.
+type pdfDocument struct {
+	objects map[int]any
+	trailer pdfDict
+}
+
+// parsePDF reads data into a pdfDocument.
+func parsePDF(data []byte) (*pdfDocument, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("%PDF-")) {
+		return nil, fmt.Errorf("not a PDF: the data does not start with %%PDF-")
+	}
+	doc := &pdfDocument{objects: map[int]any{}}
+	lex := &pdfLexer{data: data}
+	var prev, prevPrev pdfToken
+	for {
+		tok := lex.next()
+		if tok.kind == pdfTokEOF {
+			break
+		}
+		switch {
+		case tok.kind == pdfTokKeyword && tok.keyword == "obj" && prevPrev.isInt() && prev.isInt():
+			value, err := lex.parseValue(lex.next())
+			if err != nil {
+				return nil, fmt.Errorf("object %d: %w", int(prevPrev.number), err)
+			}
+			if dict, ok := value.(pdfDict); ok && lex.peekKeyword("stream") {
+				lex.next()
+				value = pdfStream{dict: dict, raw: lex.streamData(dict)}
+			}
+			doc.objects[int(prevPrev.number)] = value
+			tok = pdfToken{}
+		case tok.kind == pdfTokKeyword && tok.keyword == "trailer":
+			value, err := lex.parseValue(lex.next())
+			if err != nil {
+				return nil, fmt.Errorf("trailer: %w", err)
+			}
+			if dict, ok := value.(pdfDict); ok {
+				doc.trailer = dict
+			}
+			tok = pdfToken{}
+		}
+		prevPrev, prev = prev, tok
+	}
+	if err := doc.unpackObjectStreams(); err != nil {
+		return nil, err
+	}
+	if doc.trailer == nil {
+		// a cross-reference stream carries the trailer keys in its own dictionary
+		for _, obj := range doc.objects {
+			if s, ok := obj.(pdfStream); ok && s.dict["Type"] == pdfName("XRef") {
+				doc.trailer = s.dict
+			}
+		}
+	}
+	if doc.trailer != nil && doc.trailer["Encrypt"] != nil {
+		return nil, fmt.Errorf("the PDF is encrypted")
+	}
+	return doc, nil
+}
+
+// unpackObjectStreams adds the objects compressed into /Type /ObjStm streams.  Chrome does not write
+// them today, but a reader that silently lost every object in one would report an empty document.
+func (doc *pdfDocument) unpackObjectStreams() error {
+	for _, obj := range doc.objects {
+		s, ok := obj.(pdfStream)
+		if !ok || s.dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		data, err := doc.decodeStream(s)
+		if err != nil {
+			return err
+		}
+		n, first := doc.int(s.dict["N"]), doc.int(s.dict["First"])
+		header := &pdfLexer{data: data}
+		for range n {
+			num, offset := header.next(), header.next()
+			if !num.isInt() || !offset.isInt() || first+int(offset.number) > len(data) {
+				return fmt.Errorf("malformed object stream")
+			}
+			if _, exists := doc.objects[int(num.number)]; exists {
+				continue
+			}
+			body := &pdfLexer{data: data, pos: first + int(offset.number)}
+			value, err := body.parseValue(body.next())
+			if err != nil {
+				return fmt.Errorf("object %d: %w", int(num.number), err)
+			}
+			doc.objects[int(num.number)] = value
+		}
+	}
+	return nil
+}
+
+func (doc *pdfDocument) resolve(v any) any {
+	for range 32 { // a reference cycle is malformed; bound the chase rather than spin
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = doc.objects[ref.num]
+	}
+	return nil
+}
+
+func (doc *pdfDocument) dict(v any) pdfDict {
+	switch x := doc.resolve(v).(type) {
+	case pdfDict:
+		return x
+	case pdfStream:
+		return x.dict
+	}
+	return nil
+}
+
+func (doc *pdfDocument) array(v any) []any {
+	a, _ := doc.resolve(v).([]any)
+	return a
+}
+
+func (doc *pdfDocument) number(v any) (float64, bool) {
+	f, ok := doc.resolve(v).(float64)
+	return f, ok
+}
+
+func (doc *pdfDocument) int(v any) int {
+	f, _ := doc.number(v)
+	return int(f)
+}
+
+// decodeStream returns a stream's decoded bytes.  FlateDecode is the only filter Chrome uses.
+func (doc *pdfDocument) decodeStream(s pdfStream) ([]byte, error) {
+	filters := []any{}
+	switch f := doc.resolve(s.dict["Filter"]).(type) {
+	case pdfName:
+		filters = append(filters, f)
+	case []any:
+		filters = f
+	}
+	data := s.raw
+	for _, f := range filters {
+		switch doc.resolve(f) {
+		case pdfName("FlateDecode"):
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("FlateDecode: %w", err)
+			}
+			decoded, err := io.ReadAll(r)
+			if err != nil && len(decoded) == 0 {
+				return nil, fmt.Errorf("FlateDecode: %w", err)
+			}
+			data = decoded
+		default:
+			return nil, fmt.Errorf("unsupported stream filter %v", f)
+		}
+	}
+	return data, nil
+}
+
+// pages returns the page dictionaries in document order, each with the Resources it inherits from the
+// page tree folded in.
+func (doc *pdfDocument) pages() ([]pdfDict, error) {
+	root := doc.dict(doc.trailer["Root"])
+	if root == nil {
+		for _, obj := range doc.objects {
+			if d, ok := obj.(pdfDict); ok && d["Type"] == pdfName("Catalog") {
+				root = d
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("the PDF has no document catalog")
+	}
+	pages := []pdfDict{}
+	var walk func(node pdfDict, resources any, depth int)
+	walk = func(node pdfDict, resources any, depth int) {
+		if node == nil || depth > 64 {
+			return
+		}
+		if r, ok := node["Resources"]; ok {
+			resources = r
+		}
+		if node["Type"] == pdfName("Page") || node["Kids"] == nil {
+			page := pdfDict{}
+			for k, v := range node {
+				page[k] = v
+			}
+			page["Resources"] = resources
+			pages = append(pages, page)
+			return
+		}
+		for _, kid := range doc.array(node["Kids"]) {
+			walk(doc.dict(kid), resources, depth+1)
+		}
+	}
+	walk(doc.dict(root["Pages"]), nil, 0)
+	return pages, nil
+}
+
+// pdfPageTexts returns the text of each page of a PDF, in order.
+func pdfPageTexts(data []byte) ([]string, error) {
+	doc, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := doc.pages()
+	if err != nil {
+		return nil, err
+	}
+	texts := []string{}
+	for i, page := range pages {
+		content := []byte{}
+		contents := doc.resolve(page["Contents"])
+		parts := []any{contents}
+		if a, ok := contents.([]any); ok {
+			parts = a
+		}
+		for _, part := range parts {
+			s, ok := doc.resolve(part).(pdfStream)
+			if !ok {
+				continue
+			}
+			decoded, err := doc.decodeStream(s)
+			if err != nil {
+				return nil, fmt.Errorf("page %d: %w", i+1, err)
+			}
+			content = append(append(content, decoded...), '\n')
+		}
+		ex := &pdfTextExtractor{doc: doc, fonts: map[any]*pdfFont{}}
+		ex.run(content, doc.dict(page["Resources"]), pdfIdentity, 0)
+		texts = append(texts, ex.text())
+	}
+	return texts, nil
+}
+
+// pdfPageCount returns the number of pages in a PDF.
+func pdfPageCount(data []byte) (int, error) {
+	doc, err := parsePDF(data)
+	if err != nil {
+		return 0, err
+	}
+	pages, err := doc.pages()
+	return len(pages), err
+}
+
+// pdfMatrix is a PDF affine transform [a b c d e f].
+type pdfMatrix [6]float64
+
+var pdfIdentity = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+// times returns m x n: apply m, then n.
+func (m pdfMatrix) times(n pdfMatrix) pdfMatrix {
+	return pdfMatrix{
+		m[0]*n[0] + m[1]*n[2], m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2], m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4], m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func pdfTranslate(x, y float64) pdfMatrix { return pdfMatrix{1, 0, 0, 1, x, y} }
+
+// pdfFont is what the extractor needs from a font: how to split a string into character codes, what
+// each code means, and how far each one advances.
+type pdfFont struct {
+	codeBytes    int
+	toUnicode    map[string]string
+	widths       map[int]float64
+	defaultWidth float64
+}
+
+func (f *pdfFont) glyphs(s []byte) (codes []int, texts []string) {
+	for i := 0; i < len(s); i += f.codeBytes {
+		end := min(i+f.codeBytes, len(s))
+		raw := s[i:end]
+		code := 0
+		for _, c := range raw {
+			code = code<<8 | int(c)
+		}
+		text, ok := f.toUnicode[string(raw)]
+		if !ok {
+			if f.codeBytes == 1 {
+				text = string(rune(code)) // no map on a simple font: read it as Latin-1, which WinAnsi nearly is
+			} else {
+				text = ""
+			}
+		}
+		codes, texts = append(codes, code), append(texts, text)
+	}
+	return codes, texts
+}
+
+func (f *pdfFont) width(code int) float64 {
+	if w, ok := f.widths[code]; ok {
+		return w
+	}
+	return f.defaultWidth
+}
+
+// pdfTextState is the part of the graphics state that decides where text lands.  q/Q save and restore
+// all of it (strictly only the CTM and the text-state parameters are saved, but BT resets the text
+// matrices anyway, so saving them too changes nothing).
+type pdfTextState struct {
+	ctm, tm, tlm                        pdfMatrix
+	font                                *pdfFont
+	fontSize, charSpace, wordSpace, hsc float64
+	leading, rise                       float64
+}
+
+// pdfTextExtractor interprets a content stream's text operators and lays the text out as lines.  It
+// tracks where each run of glyphs starts and ends in device space: a run that starts on a different
+// baseline begins a new line, and one that starts noticeably to the right of where the last one ended
+// gets a space.  That is how the words in a Chrome PDF come apart - Skia positions runs, it does not
+// always draw the space glyphs between them.
+type pdfTextExtractor struct {
+	doc   *pdfDocument
+	fonts map[any]*pdfFont
+	out   strings.Builder
+
+	pdfTextState
+	stack []pdfTextState
+
+	started      bool
+	lastX, lastY float64
+	lastSize     float64
+}
+
+func (ex *pdfTextExtractor) text() string {
+	lines := strings.Split(ex.out.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+func (ex *pdfTextExtractor) run(content []byte, resources pdfDict, ctm pdfMatrix, depth int) {
+	if depth > 8 {
+		return
+	}
+	ex.ctm, ex.tm, ex.tlm = ctm, pdfIdentity, pdfIdentity
+	ex.hsc = 1
+	lex := &pdfLexer{data: content}
+	operands := []any{}
+	for {
+		tok := lex.next()
+		if tok.kind == pdfTokEOF {
+			return
+		}
+		if tok.kind != pdfTokKeyword || tok.keyword == "true" || tok.keyword == "false" || tok.keyword == "null" {
+			value, err := lex.parseValue(tok)
+			if err != nil {
+				return
+			}
+			operands = append(operands, value)
+			continue
+		}
+		ex.operator(tok.keyword, operands, resources, lex, depth)
+		operands = operands[:0]
+	}
+}
+
+func pdfNumbers(operands []any, n int) ([]float64, bool) {
+	if len(operands) < n {
+		return nil, false
+	}
+	out := make([]float64, n)
+	for i, op := range operands[len(operands)-n:] {
+		f, ok := op.(float64)
+		if !ok {
+			return nil, false
+		}
+		out[i] = f
+	}
+	return out, true
+}
+
+func (ex *pdfTextExtractor) operator(op string, operands []any, resources pdfDict, lex *pdfLexer, depth int) {
+	switch op {
+	case "q":
+		ex.stack = append(ex.stack, ex.pdfTextState)
+	case "Q":
+		if n := len(ex.stack); n > 0 {
+			ex.pdfTextState, ex.stack = ex.stack[n-1], ex.stack[:n-1]
+		}
+	case "cm":
+		if m, ok := pdfNumbers(operands, 6); ok {
+			ex.ctm = pdfMatrix(m).times(ex.ctm)
+		}
+	case "BT":
+		ex.tm, ex.tlm = pdfIdentity, pdfIdentity
+	case "Tf":
+		if len(operands) >= 2 {
+			if name, ok := operands[len(operands)-2].(pdfName); ok {
+				ex.font = ex.loadFont(resources, name)
+			}
+			ex.fontSize, _ = operands[len(operands)-1].(float64)
+		}
+	case "Tc":
+		if n, ok := pdfNumbers(operands, 1); ok {
+			ex.charSpace = n[0]
+		}
+	case "Tw":
+		if n, ok := pdfNumbers(operands, 1); ok {
+			ex.wordSpace = n[0]
+		}
+	case "Tz":
+		if n, ok := pdfNumbers(operands, 1); ok {
+			ex.hsc = n[0] / 100
+		}
+	case "TL":
+		if n, ok := pdfNumbers(operands, 1); ok {
+			ex.leading = n[0]
+		}
+	case "Ts":
+		if n, ok := pdfNumbers(operands, 1); ok {
+			ex.rise = n[0]
+		}
+	case "Td", "TD":
+		if n, ok := pdfNumbers(operands, 2); ok {
+			if op == "TD" {
+				ex.leading = -n[1]
+			}
+			ex.tlm = pdfTranslate(n[0], n[1]).times(ex.tlm)
+			ex.tm = ex.tlm
+		}
+	case "Tm":
+		if m, ok := pdfNumbers(operands, 6); ok {
+			ex.tlm = pdfMatrix(m)
+			ex.tm = ex.tlm
+		}
+	case "T*":
+		ex.nextLine()
+	case "Tj":
+		if len(operands) > 0 {
+			ex.show(operands[len(operands)-1])
+		}
+	case "'", "\"":
+		if op == "\"" {
+			if n, ok := pdfNumbers(operands[:max(len(operands)-1, 0)], 2); ok {
+				ex.wordSpace, ex.charSpace = n[0], n[1]
+			}
+		}
+		ex.nextLine()
+		if len(operands) > 0 {
+			ex.show(operands[len(operands)-1])
+		}
+	case "TJ":
+		if len(operands) == 0 {
+			return
+		}
+		a, _ := operands[len(operands)-1].([]any)
+		for _, item := range a {
+			if adjust, ok := item.(float64); ok {
+				ex.tm = pdfTranslate(-adjust/1000*ex.fontSize*ex.hsc, 0).times(ex.tm)
+				continue
+			}
+			ex.show(item)
+		}
+	case "Do":
+		if len(operands) == 0 {
+			return
+		}
+		name, _ := operands[len(operands)-1].(pdfName)
+		xobj, ok := ex.doc.resolve(ex.doc.dict(resources["XObject"])[name]).(pdfStream)
+		if !ok || xobj.dict["Subtype"] != pdfName("Form") {
+			return
+		}
+		data, err := ex.doc.decodeStream(xobj)
+		if err != nil {
+			return
+		}
+		matrix := pdfIdentity
+		if m := ex.doc.array(xobj.dict["Matrix"]); len(m) == 6 {
+			for i := range m {
+				matrix[i], _ = ex.doc.number(m[i])
+			}
+		}
+		formResources := ex.doc.dict(xobj.dict["Resources"])
+		if formResources == nil {
+			formResources = resources
+		}
+		saved, savedStack := ex.pdfTextState, ex.stack
+		ex.stack = nil
+		ex.run(data, formResources, matrix.times(ex.ctm), depth+1)
+		ex.pdfTextState, ex.stack = saved, savedStack
+	case "BI":
+		lex.skipInlineImage()
+	}
+}
+
+func (ex *pdfTextExtractor) nextLine() {
+	ex.tlm = pdfTranslate(0, -ex.leading).times(ex.tlm)
+	ex.tm = ex.tlm
+}
+
+// show lays out one string operand at the current text position and advances past it.
+func (ex *pdfTextExtractor) show(operand any) {
+	s, ok := operand.([]byte)
+	if !ok || ex.font == nil {
+		return
+	}
+	codes, texts := ex.font.glyphs(s)
+	trm := pdfMatrix{ex.fontSize * ex.hsc, 0, 0, ex.fontSize, 0, ex.rise}.times(ex.tm).times(ex.ctm)
+	x, y := trm[4], trm[5]
+	size := math.Hypot(trm[2], trm[3])
+	if size == 0 {
+		size = 1
+	}
+	run := strings.Join(texts, "")
+	if run != "" {
+		if ex.started {
+			switch {
+			case math.Abs(y-ex.lastY) > 0.5*math.Max(size, ex.lastSize):
+				ex.out.WriteString("\n")
+			case x-ex.lastX > 0.2*size && !strings.HasSuffix(ex.out.String(), " ") && !strings.HasPrefix(run, " "):
+				ex.out.WriteString(" ")
+			}
+		}
+		ex.out.WriteString(run)
+		ex.started = true
+	}
+	for _, code := range codes {
+		advance := ex.font.width(code)/1000*ex.fontSize + ex.charSpace
+		if ex.font.codeBytes == 1 && code == ' ' {
+			advance += ex.wordSpace
+		}
+		ex.tm = pdfTranslate(advance*ex.hsc, 0).times(ex.tm)
+	}
+	if run != "" {
+		end := pdfMatrix{ex.fontSize * ex.hsc, 0, 0, ex.fontSize, 0, ex.rise}.times(ex.tm).times(ex.ctm)
+		ex.lastX, ex.lastY, ex.lastSize = end[4], y, size
+	}
+}
+
+// loadFont reads the font resource called name, caching it for the rest of the page.
+func (ex *pdfTextExtractor) loadFont(resources pdfDict, name pdfName) *pdfFont {
+	ref := ex.doc.dict(resources["Font"])[name]
+	key := any(ref)
+	if _, isRef := ref.(pdfRef); !isRef {
+		key = name
+	}
+	if f, ok := ex.fonts[key]; ok {
+		return f
+	}
+	dict := ex.doc.dict(ref)
+	f := &pdfFont{codeBytes: 1, widths: map[int]float64{}, defaultWidth: 0}
+	if dict == nil {
+		ex.fonts[key] = f
+		return f
+	}
+	if dict["Subtype"] == pdfName("Type0") {
+		f.codeBytes = 2
+		f.defaultWidth = 1000
+		if descendants := ex.doc.array(dict["DescendantFonts"]); len(descendants) > 0 {
+			cid := ex.doc.dict(descendants[0])
+			if dw, ok := ex.doc.number(cid["DW"]); ok {
+				f.defaultWidth = dw
+			}
+			ex.readCIDWidths(f, ex.doc.array(cid["W"]))
+		}
+	} else {
+		first := ex.doc.int(dict["FirstChar"])
+		for i, w := range ex.doc.array(dict["Widths"]) {
+			f.widths[first+i], _ = ex.doc.number(w)
+		}
+	}
+	if s, ok := ex.doc.resolve(dict["ToUnicode"]).(pdfStream); ok {
+		if data, err := ex.doc.decodeStream(s); err == nil {
+			f.toUnicode, f.codeBytes = parseToUnicode(data, f.codeBytes)
+		}
+	}
+	ex.fonts[key] = f
+	return f
+}
+
+// readCIDWidths reads a CIDFont's /W array: entries are either "c [w1 w2 ...]" (consecutive codes from
+// c) or "cfirst clast w" (one width for a range).
+func (ex *pdfTextExtractor) readCIDWidths(f *pdfFont, w []any) {
+	for i := 0; i < len(w); {
+		first, ok := ex.doc.number(w[i])
+		if !ok || i+1 >= len(w) {
+			return
+		}
+		if list := ex.doc.array(w[i+1]); list != nil {
+			for j, width := range list {
+				f.widths[int(first)+j], _ = ex.doc.number(width)
+			}
+			i += 2
+			continue
+		}
+		last, ok := ex.doc.number(w[i+1])
+		if !ok || i+2 >= len(w) {
+			return
+		}
+		width, _ := ex.doc.number(w[i+2])
+		for c := int(first); c <= int(last) && c-int(first) < 1<<16; c++ {
+			f.widths[c] = width
+		}
+		i += 3
+	}
+}
+
+// parseToUnicode reads the bfchar and bfrange sections of a ToUnicode CMap.  It also reports the code
+// length from the codespace range, falling back to codeBytes when there isn't one.
+func parseToUnicode(data []byte, codeBytes int) (map[string]string, int) {
+	out := map[string]string{}
+	lex := &pdfLexer{data: data}
+	operands := []any{}
+	section := ""
+	for {
+		tok := lex.next()
+		if tok.kind == pdfTokEOF {
+			return out, codeBytes
+		}
+		if tok.kind == pdfTokKeyword {
+			switch tok.keyword {
+			case "begincodespacerange", "beginbfchar", "beginbfrange":
+				section = tok.keyword
+			case "endcodespacerange":
+				if len(operands) > 0 {
+					if low, ok := operands[0].([]byte); ok && len(low) > 0 {
+						codeBytes = len(low)
+					}
+				}
+				section = ""
+			case "endbfchar":
+				for i := 0; i+1 < len(operands); i += 2 {
+					src, _ := operands[i].([]byte)
+					dst, _ := operands[i+1].([]byte)
+					out[string(src)] = decodeUTF16BE(dst)
+				}
+				section = ""
+			case "endbfrange":
+				for i := 0; i+2 < len(operands); i += 3 {
+					lo, _ := operands[i].([]byte)
+					hi, _ := operands[i+1].([]byte)
+					addBFRange(out, lo, hi, operands[i+2])
+				}
+				section = ""
+			}
+			operands = operands[:0]
+			continue
+		}
+		value, err := lex.parseValue(tok)
+		if err != nil {
+			return out, codeBytes
+		}
+		if section != "" {
+			operands = append(operands, value)
+		}
+	}
+}
+
+func addBFRange(out map[string]string, lo, hi []byte, dst any) {
+	if len(lo) == 0 || len(lo) != len(hi) {
+		return
+	}
+	start, end := bytesToInt(lo), bytesToInt(hi)
+	if end < start || end-start > 1<<16 {
+		return
+	}
+	for code := start; code <= end; code++ {
+		src := intToBytes(code, len(lo))
+		switch d := dst.(type) {
+		case []byte:
+			// the destination's last UTF-16 unit counts up along with the source code
+			next := append([]byte{}, d...)
+			if n := len(next); n >= 2 {
+				v := int(next[n-2])<<8 | int(next[n-1]) + (code - start)
+				next[n-2], next[n-1] = byte(v>>8), byte(v)
+			}
+			out[string(src)] = decodeUTF16BE(next)
+		case []any:
+			if i := code - start; i < len(d) {
+				if b, ok := d[i].([]byte); ok {
+					out[string(src)] = decodeUTF16BE(b)
+				}
+			}
+		}
+	}
+}
+
+func bytesToInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func intToBytes(v int, n int) []byte {
+	out := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		out[i] = byte(v)
+		v >>= 8
+	}
+	return out
+}
+
+func decodeUTF16BE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// ---- lexing ---------------------------------------------------------------------------------------
+
+type pdfTokenKind int
+
+const (
+	pdfTokEOF pdfTokenKind = iota
+	pdfTokNumber
+	pdfTokName
+	pdfTokString
+	pdfTokKeyword
+	pdfTokDictStart
+	pdfTokDictEnd
+	pdfTokArrayStart
+	pdfTokArrayEnd
+)
+
+type pdfToken struct {
+	kind    pdfTokenKind
+	number  float64
+	integer bool
+	str     []byte
+	keyword string
+}
+
+func (t pdfToken) isInt() bool { return t.kind == pdfTokNumber && t.integer }
+
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func pdfIsSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func pdfIsDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if pdfIsSpace(c) {
+			l.pos++
+		} else if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		} else {
+			return
+		}
+	}
+}
+
+func (l *pdfLexer) next() pdfToken {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return pdfToken{kind: pdfTokEOF}
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return pdfToken{kind: pdfTokDictStart}
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return pdfToken{kind: pdfTokDictEnd}
+	case c == '[':
+		l.pos++
+		return pdfToken{kind: pdfTokArrayStart}
+	case c == ']':
+		l.pos++
+		return pdfToken{kind: pdfTokArrayEnd}
+	case c == '<':
+		return l.hexString()
+	case c == '(':
+		return l.literalString()
+	case c == '/':
+		l.pos++
+		return pdfToken{kind: pdfTokName, str: []byte(l.name())}
+	case c == '{' || c == '}' || c == ')' || c == '>':
+		l.pos++
+		return pdfToken{kind: pdfTokKeyword, keyword: string(c)}
+	}
+	start := l.pos
+	for l.pos < len(l.data) && !pdfIsSpace(l.data[l.pos]) && !pdfIsDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if f, err := strconv.ParseFloat(word, 64); err == nil && (word[0] == '-' || word[0] == '+' || word[0] == '.' || (word[0] >= '0' && word[0] <= '9')) {
+		return pdfToken{kind: pdfTokNumber, number: f, integer: !strings.ContainsAny(word, ".eE")}
+	}
+	return pdfToken{kind: pdfTokKeyword, keyword: word}
+}
+
+func (l *pdfLexer) name() string {
+	out := []byte{}
+	for l.pos < len(l.data) && !pdfIsSpace(l.data[l.pos]) && !pdfIsDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		out = append(out, c)
+		l.pos++
+	}
+	return string(out)
+}
+
+func (l *pdfLexer) hexString() pdfToken {
+	l.pos++ // <
+	digits := []byte{}
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !pdfIsSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // >
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		v, _ := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		out = append(out, byte(v))
+	}
+	return pdfToken{kind: pdfTokString, str: out}
+}
+
+func (l *pdfLexer) literalString() pdfToken {
+	l.pos++ // (
+	out := []byte{}
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfToken{kind: pdfTokString, str: out}
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				continue
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return pdfToken{kind: pdfTokString, str: out}
+}
+
+func (l *pdfLexer) peekKeyword(keyword string) bool {
+	saved := l.pos
+	tok := l.next()
+	l.pos = saved
+	return tok.kind == pdfTokKeyword && tok.keyword == keyword
+}
+
+// parseValue turns tok (and whatever follows it) into a value: float64, bool, nil, pdfName, []byte,
+// []any, pdfDict, pdfRef or pdfKeyword.
+func (l *pdfLexer) parseValue(tok pdfToken) (any, error) {
+	switch tok.kind {
+	case pdfTokNumber:
+		if tok.integer {
+			saved := l.pos
+			gen, r := l.next(), l.next()
+			if gen.isInt() && r.kind == pdfTokKeyword && r.keyword == "R" {
+				return pdfRef{num: int(tok.number), gen: int(gen.number)}, nil
+			}
+			l.pos = saved
+		}
+		return tok.number, nil
+	case pdfTokName:
+		return pdfName(tok.str), nil
+	case pdfTokString:
+		return tok.str, nil
+	case pdfTokArrayStart:
+		out := []any{}
+		for {
+			t := l.next()
+			if t.kind == pdfTokArrayEnd {
+				return out, nil
+			}
+			if t.kind == pdfTokEOF {
+				return nil, fmt.Errorf("unterminated array")
+			}
+			v, err := l.parseValue(t)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	case pdfTokDictStart:
+		out := pdfDict{}
+		for {
+			t := l.next()
+			if t.kind == pdfTokDictEnd {
+				return out, nil
+			}
+			if t.kind == pdfTokEOF {
+				return nil, fmt.Errorf("unterminated dictionary")
+			}
+			if t.kind != pdfTokName {
+				continue // tolerate junk rather than lose the whole dictionary
+			}
+			v, err := l.parseValue(l.next())
+			if err != nil {
+				return nil, err
+			}
+			out[pdfName(t.str)] = v
+		}
+	case pdfTokKeyword:
+		switch tok.keyword {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return pdfKeyword(tok.keyword), nil
+	case pdfTokEOF:
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	return nil, fmt.Errorf("unexpected token")
+}
+
+// streamData reads the bytes of a stream whose "stream" keyword has just been consumed, and leaves the
+// lexer after "endstream".  /Length is trusted when it is a direct number that lands on endstream;
+// otherwise (an indirect length, or a wrong one) the data runs to the next endstream.
+func (l *pdfLexer) streamData(dict pdfDict) []byte {
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+	start := l.pos
+	if length, ok := dict["Length"].(float64); ok && length >= 0 && start+int(length) <= len(l.data) {
+		end := start + int(length)
+		rest := bytes.TrimLeft(l.data[end:min(end+16, len(l.data))], " \r\n")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			l.pos = end
+			l.next()
+			return l.data[start:end]
+		}
+	}
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		l.pos = len(l.data)
+		return l.data[start:]
+	}
+	l.pos = start + end + len("endstream")
+	return bytes.TrimRight(l.data[start:start+end], "\r\n")
+}
+
+// skipInlineImage skips an inline image's data, which is binary and would otherwise be lexed as
+// operators.  It is called just after BI, and stops after EI.
+func (l *pdfLexer) skipInlineImage() {
+	id := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if id < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += id + 2
+	for l.pos < len(l.data) {
+		ei := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if ei < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		at := l.pos + ei
+		l.pos = at + 2
+		if at > 0 && pdfIsSpace(l.data[at-1]) && (l.pos >= len(l.data) || pdfIsSpace(l.data[l.pos])) {
+			return
+		}
+	}
+}
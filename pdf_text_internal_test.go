@@ -0,0 +1,146 @@
+package biloba
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like visual_diff_internal_test.go: they exercise the pure-Go PDF text
+// extractor on small hand-built documents shaped like what Chrome's print pipeline writes.  No browser.
+
+// buildPDF lays objects out as a classic PDF, numbering them from 1; object 1 is the catalog.
+func buildPDF(objects ...string) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func flateStream(data string) string {
+	compressed := &bytes.Buffer{}
+	w := zlib.NewWriter(compressed)
+	w.Write([]byte(data))
+	w.Close()
+	return fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String())
+}
+
+func plainStream(data string) string {
+	return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data)
+}
+
+const testToUnicode = `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+9 beginbfchar
+<0001> <0049>
+<0002> <006E>
+<0003> <0076>
+<0004> <006F>
+<0005> <0069>
+<0006> <0063>
+<0007> <0065>
+<0008> <0020>
+<000E> <00660069>
+endbfchar
+2 beginbfrange
+<0009> <000B> <0031>
+<000C> <000D> [<0041> <0042>]
+endbfrange
+endcmap
+CMapName currentdict /CMap defineresource pop
+end
+end`
+
+// testInvoicePDF is two pages.  Page 1 is drawn the way Skia draws it - a flipped CTM, a Type0 font
+// with Identity-H codes, a ToUnicode map and a /W width table, one Tm per run.  Page 2 uses a simple
+// font, literal strings and a TJ array, and its content is split across two streams.
+func testInvoicePDF() []byte {
+	widths := strings.TrimSpace(strings.Repeat("500 ", 95))
+	return buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 5 0 R] /Count 2 >>",
+		"<< /Type /Pages /Parent 2 0 R /Kids [4 0 R] /Count 1 /Resources << /Font << /F1 7 0 R >> >> >>",
+		"<< /Type /Page /Parent 3 0 R /MediaBox [0 0 612 792] /Contents 6 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F2 10 0 R >> >> /Contents [11 0 R 12 0 R] >>",
+		flateStream(strings.Join([]string{
+			"q 1 0 0 -1 0 792 cm",
+			"BT /F1 12 Tf 1 0 0 -1 72 72 Tm <000100020003000400050006000700080009000A000B> Tj ET", // "Invoice 123"
+			"BT /F1 12 Tf 1 0 0 -1 300 72 Tm <0009> Tj ET",                                        // same baseline, well to the right
+			"BT /F1 12 Tf 1 0 0 -1 72 90 Tm <00010002> Tj ET",                                     // the next line
+			"BT /F1 12 Tf 1 0 0 -1 86.4 90 Tm <0003> Tj ET",                                       // starts exactly where "In" ended
+			"BT /F1 12 Tf 1 0 0 -1 72 110 Tm <000C000D000E> Tj ET",
+			"Q",
+		}, "\n")),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /AAAAAA+Inter /Encoding /Identity-H /DescendantFonts [8 0 R] /ToUnicode 9 0 R >>",
+		"<< /Type /Font /Subtype /CIDFontType2 /DW 1000 /W [1 [600 600 600 600 600 600 600 250] 9 11 500] >>",
+		flateStream(testToUnicode),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /FirstChar 32 /Widths ["+widths+"] >>",
+		plainStream(`BT /F2 10 Tf 72 700 Td (Total \(due\)) Tj`),
+		flateStream("0 -14 Td [(A) -100 (B) -2000 (C)] TJ ET"),
+	)
+}
+
+func TestPDFPageTexts(t *testing.T) {
+	g := NewWithT(t)
+	texts, err := pdfPageTexts(testInvoicePDF())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(texts).To(HaveExactElements(
+		"Invoice 123 1\nInv\nABfi",
+		"Total (due)\nAB C",
+	))
+}
+
+func TestPDFPageCount(t *testing.T) {
+	g := NewWithT(t)
+	count, err := pdfPageCount(testInvoicePDF())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(2))
+}
+
+func TestPDFFormXObjects(t *testing.T) {
+	g := NewWithT(t)
+	pdf := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> /XObject << /X1 6 0 R >> >> /Contents 4 0 R >>",
+		plainStream("BT /F1 10 Tf 72 700 Td (Header) Tj ET q 1 0 0 1 0 -100 cm /X1 Do Q"),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /XObject /Subtype /Form /BBox [0 0 612 792] /Length 31 >>\nstream\nBT /F1 10 Tf 72 700 Td (Body) Tj ET\nendstream",
+	)
+	texts, err := pdfPageTexts(pdf)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(texts).To(HaveExactElements("Header\nBody"))
+}
+
+func TestPDFErrors(t *testing.T) {
+	g := NewWithT(t)
+	_, err := pdfPageTexts([]byte("<html>not a pdf</html>"))
+	g.Expect(err).To(MatchError(ContainSubstring("not a PDF")))
+
+	pdf := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		"<< /Length 4 /Filter /LZWDecode >>\nstream\nabcd\nendstream",
+	)
+	_, err = pdfPageTexts(pdf)
+	g.Expect(err).To(MatchError(ContainSubstring("page 1: unsupported stream filter LZWDecode")))
+}
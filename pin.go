@@ -0,0 +1,95 @@
+package biloba
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/onsi/gomega/gcustom"
+)
+
+var pinCounter int64
+
+/*
+ElementHandle is one particular DOM node, as [Biloba.Pin] found it.  Pass it anywhere Biloba takes a
+selector and it means that node and no other: it never re-resolves to a look-alike.  Once the node
+leaves the page - the framework re-renders it, or the row is removed - every use of the handle fails
+at once, saying when the node was detached, and whether the selector that pinned it now matches a
+different node (it was replaced).
+
+Read https://onsi.github.io/biloba/#pinning-an-element to learn more
+*/
+type ElementHandle struct {
+	id            string
+	selector      any
+	backendNodeID cdp.BackendNodeID
+	pinnedAt      time.Time
+}
+
+// String describes the handle by the selector that pinned it.
+func (h ElementHandle) String() string {
+	return fmt.Sprintf("pinned(%s)", describeSelector(h.selector))
+}
+
+// BackendNodeID returns the id the DevTools protocol knows the pinned node by, for reaching the node
+// through CDP yourself.
+func (h ElementHandle) BackendNodeID() cdp.BackendNodeID {
+	return h.backendNodeID
+}
+
+// PinnedAt returns when the handle was pinned.
+func (h ElementHandle) PinnedAt() time.Time {
+	return h.pinnedAt
+}
+
+func (h ElementHandle) encode() string {
+	return "p" + h.id
+}
+
+/*
+Pin(selector) finds the element selector matches and returns an [ElementHandle] bound to that node:
+
+	row := b.Pin(b.ByRole("row").ContainingText("Order #1002"))
+	b.Click(b.ByRole("button").WithName("Ship").Within(row))
+	Eventually(row).Should(b.HaveInnerText(ContainSubstring("Shipped")))
+
+A selector is looked up afresh on every use, so a row that the framework throws away and re-renders
+with the same text passes every assertion - even when the re-render is the bug.  A handle is bound to
+the one node: when that node is detached, the next use of the handle fails straight away, rather than
+at the end of a poll:
+
+	the pinned element (role=row containsText"Order #1002") was replaced at 14:03:05.120 UTC,
+	1.25s after it was pinned - its selector now matches a different element: role=row name="Order #1002"
+
+Exist(), HasElement and Count still answer for a handle, so Eventually(row).ShouldNot(b.Exist()) waits
+for the node to go.  Pin polls until selector matches, and honors WithTimeout, WithPolling, WithContext and
+Immediate.  A pin lasts as long as the document: a navigation or reload leaves the handle pointing at
+nothing.
+
+Read https://onsi.github.io/biloba/#pinning-an-element to learn more
+*/
+func (b *Biloba) Pin(selector any) ElementHandle {
+	b.gt.Helper()
+	handle := ElementHandle{id: fmt.Sprintf("pin-%d", atomic.AddInt64(&pinCounter, 1)), selector: selector}
+	encoded, err := encodeSelector(selector)
+	if err != nil {
+		b.gt.Fatalf("Failed to pin element:\n%s", err.Error())
+		return handle
+	}
+	matcher := gcustom.MakeMatcher(func(sel any) (bool, error) {
+		return b.runBilobaHandler("pin", sel, handle.id, describeSelector(selector), encoded).MatcherResult()
+	}).WithMessage("exist, to be pinned")
+	if !b.pollOrImmediate(selector, matcher) {
+		return handle
+	}
+	handle.pinnedAt = time.Now()
+	session, err := b.documentSession()
+	if err == nil {
+		handle.backendNodeID, err = b.backendNodeID(session, handle)
+	}
+	if err != nil {
+		b.gt.Fatalf("Failed to pin element:\n%s", err.Error())
+	}
+	return handle
+}
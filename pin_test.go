@@ -0,0 +1,77 @@
+package biloba_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pinning an element", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/pin.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	It("returns a handle that works anywhere a selector does", func() {
+		row := b.Pin(b.ByRole("row").ContainingText("Order #1002"))
+		Ω(row.BackendNodeID()).ShouldNot(BeZero())
+		Ω(row.PinnedAt()).Should(BeTemporally("~", time.Now(), time.Second))
+		Ω(row.String()).Should(Equal(`pinned(role=row containsText"Order #1002")`))
+
+		Ω(row).Should(b.HaveInnerText(ContainSubstring("Pending")))
+		b.Click(b.ByRole("button").WithName("Ship").Within(row))
+		Ω(row).Should(b.HaveInnerText(ContainSubstring("Shipped")))
+		Ω(b.GetProperty(row, "id")).Should(Equal("r2"))
+		Ω(b.HasElement(row)).Should(BeTrue())
+		Ω(b.Count(row)).Should(Equal(1))
+	})
+
+	It("fails fast, saying when, once the node is replaced", func() {
+		row := b.Pin("#r2")
+		b.Click("#rerender")
+		Eventually(func() bool { return b.HasElement(row) }).Should(BeFalse())
+
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		start := time.Now()
+		g.Eventually(row).WithTimeout(5 * time.Second).Should(b.HaveInnerText(ContainSubstring("Pending")))
+		Ω(time.Since(start)).Should(BeNumerically("<", time.Second))
+		Ω(failure).Should(MatchRegexp(`the pinned element \(#r2\) was replaced at \d\d:\d\d:\d\d\.\d\d\d UTC, \d+\.\d\ds after it was pinned - its selector now matches a different element`))
+		Ω("#r2").Should(b.HaveInnerText(ContainSubstring("Pending")))
+	})
+
+	It("fails fast once the node is detached", func() {
+		row := b.Pin("#r2")
+		b.Click("#remove")
+		Eventually(row).ShouldNot(b.Exist())
+
+		b.Click(row)
+		ExpectFailures(MatchRegexp(`the pinned element \(#r2\) was detached at \d\d:\d\d:\d\d\.\d\d\d UTC`))
+	})
+
+	It("fails fast when a detached handle scopes a locator", func() {
+		row := b.Pin("#r2")
+		b.Click("#remove")
+		Eventually(row).ShouldNot(b.Exist())
+		Ω(b.Count(row)).Should(Equal(0))
+
+		start := time.Now()
+		b.WithTimeout(5 * time.Second).Click(b.ByRole("button").WithName("Ship").Within(row))
+		ExpectFailures(MatchRegexp(`the pinned element \(#r2\) was detached at \d\d:\d\d:\d\d\.\d\d\d UTC`))
+		Ω(time.Since(start)).Should(BeNumerically("<", time.Second))
+	})
+
+	It("polls until the selector matches, and fails when it never does", func() {
+		b.WithTimeout(100 * time.Millisecond).Pin("#r3")
+		ExpectFailures(ContainSubstring("exist, to be pinned"))
+	})
+
+	It("points at nothing once the page navigates", func() {
+		row := b.Pin("#r1")
+		b.Navigate(fixtureServer + "/pin.html")
+		Eventually("#heading").Should(b.Exist())
+		b.Immediate().GetInnerText(row)
+		ExpectFailures(ContainSubstring("is not in this document - it was pinned in another tab or frame, or before the page navigated"))
+	})
+})
@@ -35,6 +35,23 @@ var _ = Describe("Prepare resets per-spec state so it does not leak between spec
 		Expect(b).To(b.HaveNumSessionStorageItems(0))
 	})
 
+	It("resets CPU throttling", func() {
+		b.ThrottleCPU(4)
+		Expect(b.CPUThrottleForTest()).To(Equal(4.0))
+
+		b.Prepare()
+		Expect(b.CPUThrottleForTest()).To(Equal(1.0))
+	})
+
+	It("clears emulated media", func() {
+		b.EmulateMedia(biloba.MediaFeatures{ReducedMotion: "reduce"}, biloba.PrintMedia())
+		Expect(b.Run(`window.matchMedia("print").matches`)).To(BeTrue())
+
+		b.Prepare()
+		Expect(b.Run(`window.matchMedia("print").matches`)).To(BeFalse())
+		Expect(b.Run(`window.matchMedia("(prefers-reduced-motion: reduce)").matches`)).To(BeFalse())
+	})
+
 	It("closes tabs opened during the spec", func() {
 		b.NewTab()
 		b.NewTab()
@@ -52,6 +52,8 @@ type matchTrail struct {
 	lastAt    time.Duration // elapsed at the most recent match
 	matched   int           // how many samples resolved the selector
 	unmatched bool          // the most recent sample did NOT resolve it
+	view      *Biloba       // the view that looked the selector up - it may be a Frame() view
+	selector  any           // the selector itself, so a failure can explain it (see neverMatched)
 }
 
 // recordProbe appends value to this tab's trajectory under key, when the suite has opted into
@@ -91,11 +93,14 @@ func (p *probeRecorder) record(key string, value any, now time.Time) {
 // recordMatch folds one poll sample's presence verdict into this tab's match trail, when the suite has
 // opted into trajectory recording (BilobaConfigPollTrajectory).  Cheap no-op otherwise; it rides
 // runBilobaHandler, which already knows whether the selector resolved (biloba.js's `found`).
-func (b *Biloba) recordMatch(key, display string, found bool) {
+func (b *Biloba) recordMatch(key string, selector any, found bool) {
 	if !b.pollTrajectory || b.probes == nil {
 		return
 	}
-	b.probes.recordMatch(key, display, found, time.Now())
+	b.probes.recordMatch(key, fmt.Sprintf("%v", selector), found, time.Now())
+	b.probes.mu.Lock()
+	b.probes.match.view, b.probes.match.selector = b, selector
+	b.probes.mu.Unlock()
 }
 
 func (p *probeRecorder) recordMatch(key, display string, found bool, now time.Time) {
@@ -130,6 +135,18 @@ func (p *probeRecorder) renderDetachedNode() string {
 		p.match.display, p.match.matched, roundDuration(p.match.firstAt), roundDuration(p.match.lastAt))
 }
 
+// neverMatched returns the selector the current trail follows, and the view that looked it up, when
+// no sample ever resolved it - the case the detached-node signal leaves alone, and the one
+// ExplainSelector answers.
+func (p *probeRecorder) neverMatched() (*Biloba, any, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.match.view == nil || p.match.matched > 0 || !p.match.unmatched {
+		return nil, nil, false
+	}
+	return p.match.view, p.match.selector, true
+}
+
 // resetMatch drops the match trail so a diagnosis can't leak from one spec into the next.
 func (p *probeRecorder) resetMatch() {
 	p.mu.Lock()
@@ -137,6 +154,17 @@ func (p *probeRecorder) resetMatch() {
 	p.match = matchTrail{}
 }
 
+// windowStart returns when the current poll began - the later of the value series and the match trail,
+// since whichever began last is the Eventually that was running - or the zero time when neither has.
+func (p *probeRecorder) windowStart() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.match.start.After(p.start) {
+		return p.match.start
+	}
+	return p.start
+}
+
 // render returns the human-readable trajectory for the current series, or "" when nothing was recorded.
 func (p *probeRecorder) render() string {
 	p.mu.Lock()
@@ -1,5 +1,7 @@
 package biloba
 
+import "encoding/json"
+
 func newProperties(input any) Properties {
 	x := input.(map[string]any)
 	return x
@@ -199,3 +201,12 @@ func toStringSlice(input any) []string {
 	}
 	return out
 }
+
+// remarshal decodes a JSON-shaped result from biloba.js into a typed value.
+func remarshal(from any, to any) error {
+	raw, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, to)
+}
@@ -285,9 +285,9 @@ func (b *Biloba) realisticScrollWheel(selector any, deltaX, deltaY float64) erro
 // realisticSetValue implements SetValue for realistic mode.  Text inputs are focused with a real
 // click, cleared, typed with real CDP key events, then blurred to fire change (matching SetValue's
 // value-set contract); checkboxes are toggled with a real click only when not already in the
-// desired state.  Radio groups, <select>, and multi-selects fall back to the fast JS path - native
-// pickers can't be driven by a real pointer (Playwright's selectOption sets them programmatically
-// too).
+// desired state; contenteditable editors are focused with a real click and typed over.  Radio
+// groups, <select>, and multi-selects fall back to the fast JS path - native pickers can't be driven
+// by a real pointer (Playwright's selectOption sets them programmatically too).
 func (b *Biloba) realisticSetValue(selector any, value any) (bool, error) {
 	kind := b.runBilobaHandler("inputKind", selector)
 	if kind.Error() != nil {
@@ -322,6 +322,12 @@ func (b *Biloba) realisticSetValue(selector any, value any) (bool, error) {
 			return false, r.Error()
 		}
 		return true, nil
+	case "editor":
+		ok, err := b.realisticClick(selector, pointerConfig{}) // real click to focus
+		if err != nil || !ok {
+			return ok, err
+		}
+		return b.setEditorValue(selector, value)
 	default: // radio, select, multi-select
 		return b.runBilobaHandler("setValue", selector, value).MatcherResult()
 	}
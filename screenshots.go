@@ -208,21 +208,28 @@ func (b *Biloba) CaptureScreenshotToFile(path string) string {
 // failure output and is readable by tools that render PNGs), and returns the absolute path.  It
 // fails the spec on any error.
 func (b *Biloba) writeScreenshotToFile(img []byte, path string) string {
+	b.gt.Helper()
+	return b.writeArtifactToFile("screenshot", img, path)
+}
+
+// writeArtifactToFile is writeScreenshotToFile for any kind of capture (a screenshot, a PDF); kind
+// names it in the messages.
+func (b *Biloba) writeArtifactToFile(kind string, data []byte, path string) string {
 	b.gt.Helper()
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		b.gt.Fatalf("Failed to resolve screenshot path %q:\n%s", path, err.Error())
+		b.gt.Fatalf("Failed to resolve %s path %q:\n%s", kind, path, err.Error())
 		return ""
 	}
 	if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
-		b.gt.Fatalf("Failed to create screenshot directory %q:\n%s", filepath.Dir(absPath), err.Error())
+		b.gt.Fatalf("Failed to create %s directory %q:\n%s", kind, filepath.Dir(absPath), err.Error())
 		return ""
 	}
-	if err := os.WriteFile(absPath, img, 0644); err != nil {
-		b.gt.Fatalf("Failed to write screenshot to %q:\n%s", absPath, err.Error())
+	if err := os.WriteFile(absPath, data, 0644); err != nil {
+		b.gt.Fatalf("Failed to write %s to %q:\n%s", kind, absPath, err.Error())
 		return ""
 	}
-	b.gt.Printf("Screenshot written to: %s\n", absPath)
+	b.gt.Printf("%s written to: %s\n", strings.ToUpper(kind[:1])+kind[1:], absPath)
 	return absPath
 }
 
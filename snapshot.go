@@ -0,0 +1,345 @@
+package biloba
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+)
+
+/*
+MatchOutlineSnapshot(name) is a Gomega matcher that passes once the subject's DOM outline (the text
+[Biloba.Outline] returns) is identical to the committed snapshot stored under name.  Apply it to the tab
+to snapshot the whole page, or to a selector to snapshot one element and its subtree:
+
+	Eventually(b).Should(b.MatchOutlineSnapshot("home"))
+	Eventually("#sidebar").Should(b.MatchOutlineSnapshot("sidebar"))
+
+Snapshots share HaveScreenshot's baselines directory (see [BilobaConfigScreenshotBaselinesDir]) and are
+stored there as <name>.outline.txt.  They follow the same rules, too: a MISSING snapshot fails loudly
+rather than being written and passed, and BILOBA_UPDATE_SCREENSHOTS=1 (re)writes snapshots from a
+settled page.  A mismatch fails with a unified diff of the snapshot against what the page has now, and
+writes the full actual text next to the failure screenshots.
+
+The snapshot is never truncated the way Outline's output is.
+
+Read https://onsi.github.io/biloba/#text-snapshots to learn more
+*/
+func (b *Biloba) MatchOutlineSnapshot(name string) types.GomegaMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("MatchOutlineSnapshot")
+	return b.textSnapshotMatcher(outlineSnapshot, name)
+}
+
+/*
+MatchA11ySnapshot(name) is MatchOutlineSnapshot for the accessibility tree: it passes once the
+subject's [Biloba.A11yOutline] text is identical to the committed snapshot stored under name, as
+<name>.a11y.txt.  Apply it to the tab for the whole page, or to a selector for one element's subtree:
+
+	Eventually("nav").Should(b.MatchA11ySnapshot("primary-nav"))
+
+Because the accessibility tree leaves out presentational wrappers and class names, an a11y snapshot
+stays put through restyling and refactoring that a DOM outline would flag, and changes when what a
+screen reader hears changes.
+
+Read https://onsi.github.io/biloba/#text-snapshots to learn more
+*/
+func (b *Biloba) MatchA11ySnapshot(name string) types.GomegaMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("MatchA11ySnapshot")
+	return b.textSnapshotMatcher(a11ySnapshot, name)
+}
+
+// textSnapshotKind is what distinguishes one kind of text snapshot from another: the matcher's name
+// for errors, what it snapshots for messages, the extension its baselines are stored under, and how a
+// subject's text is captured (selector is nil for the whole document).
+type textSnapshotKind struct {
+	method  string
+	noun    string
+	ext     string
+	capture func(tab *Biloba, selector any) (string, error)
+}
+
+var outlineSnapshot = textSnapshotKind{
+	method:  "MatchOutlineSnapshot",
+	noun:    "outline",
+	ext:     ".outline.txt",
+	capture: (*Biloba).outlineOf,
+}
+
+var a11ySnapshot = textSnapshotKind{
+	method:  "MatchA11ySnapshot",
+	noun:    "accessibility outline",
+	ext:     ".a11y.txt",
+	capture: (*Biloba).a11yOutlineOf,
+}
+
+func (b *Biloba) textSnapshotMatcher(kind textSnapshotKind, name string) types.GomegaMatcher {
+	data := map[string]any{"Name": name, "Noun": kind.noun}
+	return gcustom.MakeMatcher(func(actual any) (bool, error) {
+		tab, selector, err := textSnapshotSubject(b, kind, actual)
+		if err != nil {
+			return false, err
+		}
+		data["Subject"] = describeSnapshotSubject(selector)
+		rel, err := baselineFilePath(kind.method, kind.noun, name, "", kind.ext)
+		if err != nil {
+			return false, gomega.StopTrying(err.Error())
+		}
+		baselinePath := absOrAsIs(filepath.Join(b.visualBaselinesDir(), rel))
+		artifactPath := absOrAsIs(filepath.Join(b.visualArtifactsDir(), sanitizeForFilename(name)+strings.TrimSuffix(kind.ext, ".txt")+".actual.txt"))
+
+		if b.root.updateScreenshots {
+			text, err := settledTextSnapshot(b, kind, tab, selector, name)
+			if err != nil {
+				return false, err
+			}
+			return updateTextSnapshot(b, kind, name, baselinePath, text)
+		}
+
+		text, err := kind.capture(tab, selector)
+		if err != nil {
+			return false, err
+		}
+		raw, readErr := os.ReadFile(baselinePath)
+		if os.IsNotExist(readErr) {
+			// As with HaveScreenshot: writing the snapshot here and passing would let a spec that has
+			// never compared anything read green in CI, and no amount of polling conjures the file.
+			actualPath := writeVisualArtifact(artifactPath, []byte(text))
+			return false, gomega.StopTrying(missingTextSnapshotMessage(kind, name, baselinePath, actualPath))
+		}
+		if readErr != nil {
+			return false, gomega.StopTrying(fmt.Sprintf("Failed to read the %s snapshot %q:\n  %s\n%s", kind.noun, name, baselinePath, readErr.Error()))
+		}
+		// A checkout with autocrlf on hands us CRLF line endings the page never produced.
+		baseline := strings.ReplaceAll(string(raw), "\r\n", "\n")
+		if baseline == text {
+			return true, nil
+		}
+		data["Baseline"] = baselinePath
+		data["Actual"] = writeVisualArtifact(artifactPath, []byte(text))
+		data["Diff"] = unifiedDiff("snapshot", "actual", baseline, text)
+		return false, nil
+	}).WithTemplate("{{if .Failure}}Expected the {{.Data.Noun}} of {{.Data.Subject}} to match the {{printf \"%q\" .Data.Name}} snapshot.\n\n  Snapshot: {{.Data.Baseline}}\n  Actual:   {{.Data.Actual}}\n\n{{.Data.Diff}}\nIf the change is intended, re-run with BILOBA_UPDATE_SCREENSHOTS=1 to update the snapshot.{{else}}Expected the {{.Data.Noun}} of {{.Data.Subject}} NOT to match the {{printf \"%q\" .Data.Name}} snapshot, but it did.{{end}}", data)
+}
+
+// textSnapshotSubject resolves what the assertion was applied to, the way screenshotMatcher.subject
+// does: the tab means the whole document, anything else has to be a selector.
+func textSnapshotSubject(b *Biloba, kind textSnapshotKind, actual any) (*Biloba, any, error) {
+	if tab, ok := actual.(*Biloba); ok {
+		return tab, nil, nil
+	}
+	if _, err := encodeSelector(actual); err != nil {
+		return nil, nil, gomega.StopTrying(fmt.Sprintf("%s takes either a selector (a CSS string, an XPath, or a Locator) to snapshot one element, or the tab itself to snapshot the whole page.  Got:\n%s", kind.method, format.Object(actual, 1)))
+	}
+	return b, actual, nil
+}
+
+func describeSnapshotSubject(selector any) string {
+	if selector == nil {
+		return "the page"
+	}
+	return fmt.Sprintf("%v", selector)
+}
+
+// settledTextSnapshot is update mode's settle for text snapshots: it captures on screenshotSettleGap's
+// schedule until screenshotSettleStreak captures in a row agree, so a snapshot is not written from a
+// page that is still rendering.  A page that never settles still gets its snapshot written, with a
+// loud note - the next normal run will fail on its own.
+func settledTextSnapshot(b *Biloba, kind textSnapshotKind, tab *Biloba, selector any, name string) (string, error) {
+	var previous string
+	streak := 0
+	for attempt := range screenshotSettleAttempts {
+		if attempt > 0 {
+			time.Sleep(screenshotSettleGap(attempt - 1))
+		}
+		current, err := kind.capture(tab, selector)
+		if err != nil {
+			return "", err
+		}
+		if attempt > 0 && current == previous {
+			streak++
+		} else {
+			streak = 1
+		}
+		if streak >= screenshotSettleStreak {
+			return current, nil
+		}
+		previous = current
+	}
+	b.gt.Printf("The %s snapshot %q never settled: no %d captures in a row matched across %d captures.\nBiloba wrote the last one, but a snapshot of a page that is still changing will fail on every later run.\n", kind.noun, name, screenshotSettleStreak, screenshotSettleAttempts)
+	return previous, nil
+}
+
+// updateTextSnapshot is the BILOBA_UPDATE_SCREENSHOTS path: write and pass, printing the diff when an
+// existing snapshot changed so the update can be reviewed from the test output as well as from git.
+func updateTextSnapshot(b *Biloba, kind textSnapshotKind, name string, path string, text string) (bool, error) {
+	raw, readErr := os.ReadFile(path)
+	switch {
+	case readErr != nil:
+		b.gt.Printf("Wrote a new %s snapshot for %q:\n  %s\n", kind.noun, name, path)
+	default:
+		if previous := strings.ReplaceAll(string(raw), "\r\n", "\n"); previous != text {
+			b.gt.Printf("Updated the %s snapshot for %q:\n  %s\n%s", kind.noun, name, path, unifiedDiff("snapshot", "actual", previous, text))
+		}
+	}
+	if err := writeFileAtomically(path, []byte(text)); err != nil {
+		return false, gomega.StopTrying(fmt.Sprintf("Failed to write the %s snapshot for %q:\n  %s\n%s", kind.noun, name, path, err.Error()))
+	}
+	return true, nil
+}
+
+// missingTextSnapshotMessage is missingBaselineMessage for text snapshots.
+func missingTextSnapshotMessage(kind textSnapshotKind, name string, baselinePath string, actualPath string) string {
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "There is no %s snapshot for %q.\n\nExpected to find one at:\n  %s\n", kind.noun, name, baselinePath)
+	if actualPath != "" {
+		fmt.Fprintf(out, "\nThe %s Biloba just captured was written to:\n  %s\n", kind.noun, actualPath)
+	}
+	fmt.Fprintf(out, "\nReview it, then create the snapshot by re-running with:\n  BILOBA_UPDATE_SCREENSHOTS=1\n\nBiloba will not create a missing snapshot and pass: a spec that has never compared anything would read green in CI.")
+	return out.String()
+}
+
+// unifiedDiffContext is how many unchanged lines surround each hunk, as in diff -u.
+const unifiedDiffContext = 3
+
+// unifiedDiffMaxLines caps the diff in a failure message; the full actual text is written to disk.
+const unifiedDiffMaxLines = 200
+
+// unifiedDiff renders the line diff from one text to another in unified format, or "" when they are
+// equal.  Snapshots are small - Outline-sized - so a plain LCS over the lines that differ, after the
+// common prefix and suffix are trimmed, is plenty.
+func unifiedDiff(fromLabel string, toLabel string, from string, to string) string {
+	if from == to {
+		return ""
+	}
+	a, b := diffLines(from), diffLines(to)
+	ops := diffOps(a, b)
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", fromLabel, toLabel)
+	written := 0
+	for start := 0; start < len(ops); {
+		// find the next change, then extend the hunk while the changes are close enough to share context
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		last := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				if i-last-1 > 2*unifiedDiffContext {
+					break
+				}
+				last = i
+			}
+		}
+		lo, hi := max(first-unifiedDiffContext, start), min(last+unifiedDiffContext+1, len(ops))
+		fromStart, toStart, fromCount, toCount := ops[lo].a, ops[lo].b, 0, 0
+		for _, op := range ops[lo:hi] {
+			if op.kind != '+' {
+				fromCount++
+			}
+			if op.kind != '-' {
+				toCount++
+			}
+		}
+		fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(fromStart, fromCount), hunkRange(toStart, toCount))
+		for _, op := range ops[lo:hi] {
+			if written == unifiedDiffMaxLines {
+				fmt.Fprintf(out, "... [diff truncated]\n")
+				return out.String()
+			}
+			fmt.Fprintf(out, "%c%s\n", op.kind, op.line)
+			written++
+		}
+		start = hi
+	}
+	return out.String()
+}
+
+// diffOp is one line of a diff: ' ' kept, '-' removed, '+' added.  a and b are the 0-based positions
+// the line sits at (or would be inserted at) in each text.
+type diffOp struct {
+	kind byte
+	line string
+	a, b int
+}
+
+func diffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func diffOps(a []string, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of ma[i:] and mb[j:]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := []diffOp{}
+	for i := range prefix {
+		ops = append(ops, diffOp{' ', a[i], i, i})
+	}
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			ops = append(ops, diffOp{' ', ma[i], prefix + i, prefix + j})
+			i, j = i+1, j+1
+		case i < len(ma) && (j == len(mb) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', ma[i], prefix + i, prefix + j})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', mb[j], prefix + i, prefix + j})
+			j++
+		}
+	}
+	for k := range suffix {
+		ops = append(ops, diffOp{' ', a[len(a)-suffix+k], len(a) - suffix + k, len(b) - suffix + k})
+	}
+	return ops
+}
+
+// hunkRange formats one side of a hunk header the way diff -u does: 1-based, with the count omitted
+// when it is 1, and an empty range named by the line before it.
+func hunkRange(start int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,79 @@
+package biloba
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like visual_diff_internal_test.go: they exercise the unified diff that
+// MatchOutlineSnapshot and MatchA11ySnapshot fail with.  No browser.
+
+func lines(n int, prefix string) string {
+	out := &strings.Builder{}
+	for i := 1; i <= n; i++ {
+		out.WriteString(prefix)
+		out.WriteString(strings.Repeat("x", i))
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+func TestUnifiedDiffOfEqualTextsIsEmpty(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(unifiedDiff("a", "b", "one\ntwo\n", "one\ntwo\n")).To(BeEmpty())
+}
+
+func TestUnifiedDiffShowsAChangedLineInContext(t *testing.T) {
+	g := NewWithT(t)
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	to := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+	g.Expect(unifiedDiff("snapshot", "actual", from, to)).To(Equal(strings.Join([]string{
+		"--- snapshot",
+		"+++ actual",
+		"@@ -2,7 +2,7 @@",
+		" 2",
+		" 3",
+		" 4",
+		"-5",
+		"+five",
+		" 6",
+		" 7",
+		" 8",
+		"",
+	}, "\n")))
+}
+
+func TestUnifiedDiffReportsInsertionsAndDeletionsAtTheEdges(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(unifiedDiff("a", "b", "x\ny\n", "w\nx\ny\n")).To(Equal("--- a\n+++ b\n@@ -1,2 +1,3 @@\n+w\n x\n y\n"))
+	g.Expect(unifiedDiff("a", "b", "x\ny\nz\n", "x\ny\n")).To(Equal("--- a\n+++ b\n@@ -1,3 +1,2 @@\n x\n y\n-z\n"))
+	g.Expect(unifiedDiff("a", "b", "", "x\n")).To(Equal("--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n"))
+}
+
+func TestUnifiedDiffSplitsDistantChangesIntoHunks(t *testing.T) {
+	g := NewWithT(t)
+	from := lines(20, "")
+	to := strings.Replace(strings.Replace(from, "xx\n", "changed-2\n", 1), strings.Repeat("x", 18)+"\n", "changed-18\n", 1)
+	diff := unifiedDiff("a", "b", from, to)
+	g.Expect(strings.Count(diff, "@@ -")).To(Equal(2))
+	g.Expect(diff).To(ContainSubstring("@@ -1,5 +1,5 @@\n x\n-xx\n+changed-2\n"))
+	g.Expect(diff).To(ContainSubstring("@@ -15,6 +15,6 @@\n"))
+}
+
+func TestUnifiedDiffMergesNearbyChangesIntoOneHunk(t *testing.T) {
+	g := NewWithT(t)
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	to := "1\nB\n3\n4\n5\n6\n7\n8\nI\n10\n"
+	diff := unifiedDiff("a", "b", from, to)
+	g.Expect(strings.Count(diff, "@@ -")).To(Equal(1))
+	g.Expect(diff).To(HavePrefix("--- a\n+++ b\n@@ -1,10 +1,10 @@\n"))
+}
+
+func TestUnifiedDiffIsCapped(t *testing.T) {
+	g := NewWithT(t)
+	diff := unifiedDiff("a", "b", lines(300, "old-"), lines(300, "new-"))
+	g.Expect(diff).To(HaveSuffix("... [diff truncated]\n"))
+	g.Expect(strings.Count(diff, "\n")).To(Equal(unifiedDiffMaxLines + 4))
+}
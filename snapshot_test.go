@@ -0,0 +1,145 @@
+package biloba_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+// Like visual_test.go, every spec generates the snapshots it asserts against into a per-spec TempDir.
+var _ = Describe("Text snapshots", func() {
+	var baselinesDir, artifactsDir string
+	var failure string
+	var g Gomega
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		baselinesDir, artifactsDir = filepath.Join(root, "baselines"), filepath.Join(root, "artifacts")
+		DeferCleanup(b.SetVisualDirsForTest(baselinesDir, artifactsDir))
+		failure = ""
+		g = NewGomega(func(message string, callerSkip ...int) { failure = message })
+
+		b.Navigate(fixtureServer + "/snapshot.html")
+		Eventually("#save").Should(b.Exist())
+	})
+
+	read := func(path string) string {
+		GinkgoHelper()
+		raw, err := os.ReadFile(path)
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(raw)
+	}
+
+	Describe("MatchOutlineSnapshot", func() {
+		It("writes the snapshot in update mode, and passes against it afterwards", func() {
+			func() {
+				defer b.SetUpdateScreenshotsForTest(true)()
+				Eventually("#primary").Should(b.MatchOutlineSnapshot("nav"))
+			}()
+			Ω(gt.buffer).Should(gbytes.Say(`Wrote a new outline snapshot for "nav":`))
+			Ω(read(filepath.Join(baselinesDir, "nav.outline.txt"))).Should(HavePrefix(`<nav id="primary">`))
+			Ω(read(filepath.Join(baselinesDir, "nav.outline.txt"))).ShouldNot(ContainSubstring("Snapshots"))
+
+			Eventually("#primary").Should(b.MatchOutlineSnapshot("nav"))
+		})
+
+		It("applies to the tab itself to snapshot the whole page", func() {
+			func() {
+				defer b.SetUpdateScreenshotsForTest(true)()
+				Eventually(b).Should(b.MatchOutlineSnapshot("pages/snapshot"))
+			}()
+			Ω(read(filepath.Join(baselinesDir, "pages", "snapshot.outline.txt"))).Should(ContainSubstring(`<h1>`))
+			Eventually(b).Should(b.MatchOutlineSnapshot("pages/snapshot"))
+		})
+
+		It("fails loudly and immediately when the snapshot is missing", func() {
+			start := time.Now()
+			g.Eventually("#primary").WithTimeout(30 * time.Second).WithPolling(50 * time.Millisecond).Should(b.MatchOutlineSnapshot("nav"))
+			Ω(time.Since(start)).Should(BeNumerically("<", 5*time.Second))
+
+			Ω(failure).Should(SatisfyAll(
+				ContainSubstring(`There is no outline snapshot for "nav"`),
+				ContainSubstring(filepath.Join(baselinesDir, "nav.outline.txt")),
+				ContainSubstring("BILOBA_UPDATE_SCREENSHOTS=1"),
+				ContainSubstring(filepath.Join(artifactsDir, "nav.outline.actual.txt")),
+			))
+			Ω(read(filepath.Join(artifactsDir, "nav.outline.actual.txt"))).Should(HavePrefix(`<nav id="primary">`))
+			Ω(filepath.Join(baselinesDir, "nav.outline.txt")).ShouldNot(BeAnExistingFile())
+		})
+
+		It("shows a unified diff on a mismatch", func() {
+			func() {
+				defer b.SetUpdateScreenshotsForTest(true)()
+				Eventually(b).Should(b.MatchOutlineSnapshot("page"))
+			}()
+			b.Run(`document.getElementById("status").textContent = "Saved"`)
+
+			g.Expect(b).Should(b.MatchOutlineSnapshot("page"))
+			Ω(failure).Should(SatisfyAll(
+				ContainSubstring(`Expected the outline of the page to match the "page" snapshot.`),
+				ContainSubstring("--- snapshot\n+++ actual\n@@ "),
+				ContainSubstring("\n-    Ready\n+    Saved\n"),
+			))
+			Ω(read(filepath.Join(artifactsDir, "page.outline.actual.txt"))).Should(ContainSubstring("Saved"))
+		})
+
+		It("prints the diff when update mode changes a snapshot", func() {
+			func() {
+				defer b.SetUpdateScreenshotsForTest(true)()
+				Eventually("#status").Should(b.MatchOutlineSnapshot("status"))
+				b.Run(`document.getElementById("status").textContent = "Saved"`)
+				Eventually("#status").Should(b.MatchOutlineSnapshot("status"))
+			}()
+			Ω(gt.buffer).Should(gbytes.Say(`Updated the outline snapshot for "status":`))
+			Ω(gt.buffer).Should(gbytes.Say(`-  Ready\n\+  Saved`))
+			Eventually("#status").Should(b.MatchOutlineSnapshot("status"))
+		})
+
+		It("refuses a subject that is not a selector or a tab", func() {
+			g.Expect(3).Should(b.MatchOutlineSnapshot("nav"))
+			Ω(failure).Should(ContainSubstring("MatchOutlineSnapshot takes either a selector"))
+		})
+	})
+
+	Describe("MatchA11ySnapshot", func() {
+		It("snapshots the accessibility tree under an element, without presentational wrappers", func() {
+			func() {
+				defer b.SetUpdateScreenshotsForTest(true)()
+				Eventually("#primary").Should(b.MatchA11ySnapshot("nav"))
+			}()
+			snapshot := read(filepath.Join(baselinesDir, "nav.a11y.txt"))
+			Ω(snapshot).Should(HavePrefix("navigation\n"))
+			Ω(snapshot).Should(ContainSubstring(`  link "Home"`))
+			Ω(snapshot).ShouldNot(ContainSubstring("Snapshots"))
+
+			b.Run(`document.querySelector(".wrapper").className = "restyled"`)
+			Eventually("#primary").Should(b.MatchA11ySnapshot("nav"))
+
+			b.Run(`document.querySelector("a[href='#about']").textContent = "About us"`)
+			g.Expect("#primary").Should(b.MatchA11ySnapshot("nav"))
+			Ω(failure).Should(SatisfyAll(
+				ContainSubstring(`Expected the accessibility outline of #primary to match the "nav" snapshot.`),
+				ContainSubstring(`-  link "About"`),
+				ContainSubstring(`+  link "About us"`),
+			))
+		})
+
+		It("applies to the tab itself to snapshot the whole page", func() {
+			func() {
+				defer b.SetUpdateScreenshotsForTest(true)()
+				Eventually(b).Should(b.MatchA11ySnapshot("page"))
+			}()
+			Ω(read(filepath.Join(baselinesDir, "page.a11y.txt"))).Should(HavePrefix(`RootWebArea "Snapshot Testpage"`))
+			Eventually(b).Should(b.MatchA11ySnapshot("page"))
+		})
+
+		It("keeps polling for an element that is not there yet", func() {
+			g.Eventually("#late").WithTimeout(200 * time.Millisecond).Should(b.MatchA11ySnapshot("late"))
+			Ω(failure).Should(ContainSubstring("could not find DOM element matching selector"))
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package biloba
+
+/*
+Strict() returns a view of this tab that is strict about selectors: a single-element action or getter
+whose selector matches more than one element fails instead of quietly taking the first match:
+
+	sb := b.Strict()
+	sb.Click(sb.ByRole("button").WithName("Save"))   // fails if there are two "Save" buttons
+
+The failure lists every match, each described as a locator (and a CSS path to tell identical ones
+apart), so you can see which selector to tighten:
+
+	selector matched 2 elements, but a strict view requires exactly one: role=button name="Save"
+	  1. role=button name="Save" (css=#editor > button)
+	  2. role=button name="Save" (css=#settings > button)
+
+Strictness covers everything that acts on or reads one element - Click, SetValue, GetValue,
+HaveInnerText, BeVisible and so on.  Selectors that are about a set (Exist, HaveCount, the ...Each
+family) are unaffected.  A polling matcher keeps polling through an ambiguous moment - two "Save"
+buttons while one animates out - and fails only if the ambiguity outlasts it.
+
+Strict(false) returns a lenient view of a suite made strict with [BilobaConfigStrictSelectors].  Like
+[Biloba.Realistic], the view shares this tab's state and composes with the other views.
+
+Read https://onsi.github.io/biloba/#strict-selectors to learn more
+*/
+func (b *Biloba) Strict(enabled ...bool) *Biloba {
+	sb := *b
+	strict := boolArg(enabled)
+	sb.strict = &strict
+	return &sb
+}
+
+// isStrict reports whether this view's single-element lookups must be unambiguous: its own Strict()
+// setting if it has one, the suite's BilobaConfigStrictSelectors otherwise.
+func (b *Biloba) isStrict() bool {
+	if b.strict != nil {
+		return *b.strict
+	}
+	return b.root != nil && b.root.strictSelectors
+}
+
+// bilobaInvocation is the script that calls the _biloba primitive name with args.  The call goes
+// through _biloba.invoke, which reports a detached pin met anywhere in a selector - as a Within scope,
+// say - as the pin's error; a strict view routes it through _biloba.strictly, which also has one() and
+// poll() refuse an ambiguous selector.
+func (b *Biloba) bilobaInvocation(name string, args ...any) string {
+	if b.isStrict() {
+		return b.JSFunc("_biloba.strictly").Invoke(append([]any{name}, args...)...)
+	}
+	return b.JSFunc("_biloba.invoke").Invoke(append([]any{name}, args...)...)
+}
@@ -0,0 +1,71 @@
+package biloba_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Strict selectors", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/strict.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	It("is lenient by default, taking the first match", func() {
+		b.Click(b.ByRole("button").WithName("Save"))
+		Expect("#saved").To(b.HaveInnerText("editor"))
+	})
+
+	It("fails an action whose selector is ambiguous, listing the matches", func() {
+		b.Strict().Click(b.ByRole("button").WithName("Save"))
+		ExpectFailures(SatisfyAll(
+			ContainSubstring(`selector matched 2 elements, but a strict view requires exactly one: role=button name="Save"`),
+			ContainSubstring(`1. role=button name="Save" (css=#editor > button)`),
+			ContainSubstring(`2. role=button name="Save" (css=#settings > button)`),
+		))
+		Expect("#saved").To(b.HaveInnerText("nothing"))
+	})
+
+	It("fails a getter whose selector is ambiguous", func() {
+		b.Strict().GetValue(".title")
+		ExpectFailures(ContainSubstring("selector matched 2 elements, but a strict view requires exactly one: .title"))
+	})
+
+	It("describes elements with no role and name by their CSS path, and caps the list", func() {
+		b.Strict().GetInnerText(".note")
+		ExpectFailures(SatisfyAll(
+			ContainSubstring("selector matched 3 elements"),
+			ContainSubstring("1. css=body > div:nth-of-type(1)"),
+			ContainSubstring("3. css=body > div:nth-of-type(3)"),
+		))
+	})
+
+	It("fails a matcher whose selector is ambiguous", func() {
+		sb := b.Strict()
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect(".save").To(sb.HaveInnerText("Save"))
+		Expect(failure).To(ContainSubstring("selector matched 2 elements, but a strict view requires exactly one: .save"))
+	})
+
+	It("is happy with a selector that matches exactly one element", func() {
+		sb := b.Strict()
+		sb.Click(sb.ByRole("button").WithName("Save").Within("#settings"))
+		Expect("#saved").To(sb.HaveInnerText("settings"))
+		Expect(sb.GetValue("#editor .title")).To(Equal("Draft"))
+	})
+
+	It("leaves selectors about a set of elements alone", func() {
+		sb := b.Strict()
+		Expect(".save").To(sb.Exist())
+		Expect(".save").To(sb.HaveCount(2))
+		Expect(sb.CurrentInnerTextForEach(".note")).To(HaveExactElements("one", "two", "three"))
+	})
+
+	It("composes with the other views, and can be turned back off", func() {
+		b.Strict().Realistic().Click(".save")
+		ExpectFailures(ContainSubstring("selector matched 2 elements"))
+		b.Strict().Strict(false).Click(".save")
+		Expect("#saved").To(b.HaveInnerText("editor"))
+	})
+})
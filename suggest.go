@@ -0,0 +1,152 @@
+package biloba
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+LocatorSuggestion is one of the locators [Biloba.SuggestLocators] found for an element: the Locator
+itself, ready to use, and the Go code that builds it.
+*/
+type LocatorSuggestion struct {
+	Locator Locator
+	Code    string
+}
+
+func (s LocatorSuggestion) String() string {
+	return s.Code
+}
+
+/*
+SuggestLocators(selector) returns robust [Locator]s for the element selector matches, most robust first,
+each checked to find that element and nothing else on the page:
+
+	for _, s := range b.SuggestLocators("#app > div > div:nth-child(3) > button") {
+		fmt.Println(s.Code)
+	}
+
+	b.ByTestID("save-button")
+	b.ByRole("button").WithName("Save")
+	b.ByText("Save")
+
+Suggestions are ranked the way locators age: a test id first, then role and accessible name, then the
+label of a form control, then visible text, then placeholder, alt text and title.  A locator that is
+ambiguous on its own (two "Save" buttons) is scoped Within the closest ancestor that has a unique
+locator of its own - a test id, or a named dialog, form, region and so on.  When nothing pins the
+element down, the one suggestion is its CSS path, as a [Biloba.ByCSS] locator: a sign the element
+could use a test id or an accessible name.
+
+SuggestLocators acts immediately and does not poll.  To pick elements by clicking on them instead,
+see [Biloba.PickLocators].
+
+Read https://onsi.github.io/biloba/#suggesting-locators to learn more
+*/
+func (b *Biloba) SuggestLocators(selector any) []LocatorSuggestion {
+	b.gt.Helper()
+	b.guardConfig("SuggestLocators")
+	r := b.runBilobaHandler("suggestLocators", selector, TestIDAttribute)
+	if r.Error() != nil {
+		b.gt.Fatalf("Failed to suggest locators:\n%s", r.Error())
+		return nil
+	}
+	suggestions, err := b.decodeSuggestions(r.Result)
+	if err != nil {
+		b.gt.Fatalf("Failed to suggest locators:\n%s", err.Error())
+	}
+	return suggestions
+}
+
+/*
+PickLocators(ctx) turns the browser into a locator picker until ctx is done: Alt-click (Option-click
+on macOS) any element in this tab and the Go code for its [Biloba.SuggestLocators] is printed to the
+terminal.  The click itself is swallowed, so picking a link or a submit button doesn't act on it.
+
+It is meant for an interactive session, with BILOBA_INTERACTIVE set so the browser is visible - in a
+focused spec:
+
+	It("finds me a locator", func(ctx SpecContext) {
+		b.Navigate("http://localhost:8080/checkout")
+		b.PickLocators(ctx)
+	}, NodeTimeout(time.Hour))
+
+When a spec fails under BILOBA_INTERACTIVE, Biloba already runs the picker while it waits for ^C.
+
+Read https://onsi.github.io/biloba/#suggesting-locators to learn more
+*/
+func (b *Biloba) PickLocators(ctx context.Context) {
+	b.gt.Helper()
+	b.guardConfig("PickLocators")
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		r := b.runBilobaFunc("takePicked", TestIDAttribute)
+		if r.Error() != nil || r.Result == nil {
+			continue // most likely mid-navigation; the next tick re-installs the picker
+		}
+		suggestions, err := b.decodeSuggestions(r.Result)
+		if err != nil || len(suggestions) == 0 {
+			continue
+		}
+		fmt.Println(b.gt.F("{{green}}{{bold}}Locators for the picked element, most robust first:{{/}}"))
+		for _, s := range suggestions {
+			fmt.Println(b.gt.Fi(1, "%s", s.Code))
+		}
+	}
+}
+
+// suggestedLocator is a Locator as biloba.js's suggestFor describes it: in the shape Locator.payload
+// encodes, with a scope as a nested suggestion.
+type suggestedLocator struct {
+	By      string            `json:"by"`
+	Role    string            `json:"role"`
+	Value   string            `json:"value"`
+	Name    string            `json:"name"`
+	NameSet bool              `json:"nameSet"`
+	Within  *suggestedLocator `json:"within"`
+}
+
+func (b *Biloba) decodeSuggestions(result any) ([]LocatorSuggestion, error) {
+	raw := []suggestedLocator{}
+	if err := remarshal(result, &raw); err != nil {
+		return nil, err
+	}
+	suggestions := make([]LocatorSuggestion, len(raw))
+	for i, s := range raw {
+		l, code := s.locator(b)
+		suggestions[i] = LocatorSuggestion{Locator: l, Code: code}
+	}
+	return suggestions, nil
+}
+
+// locator rebuilds the suggestion with the By* constructors, alongside the Go code that
+// builds it.
+func (s suggestedLocator) locator(b *Biloba) (Locator, string) {
+	var l Locator
+	var code string
+	switch s.By {
+	case "testid":
+		l, code = b.ByTestID(s.Value), fmt.Sprintf("b.ByTestID(%q)", s.Value)
+	case "role":
+		l, code = b.ByRole(s.Role), fmt.Sprintf("b.ByRole(%q)", s.Role)
+		if s.NameSet {
+			l, code = l.WithName(s.Name), code+fmt.Sprintf(".WithName(%q)", s.Name)
+		}
+	case "css":
+		l, code = b.ByCSS(s.Value), fmt.Sprintf("b.ByCSS(%q)", s.Value)
+	default:
+		l = Locator{by: s.By, value: s.Value, valueMode: "exact"}
+		code = fmt.Sprintf("b.%s(%q)", locatorConstructors[s.By], s.Value)
+	}
+	if s.Within != nil {
+		scope, scopeCode := s.Within.locator(b)
+		l, code = l.Within(scope), code+".Within("+scopeCode+")"
+	}
+	return l, code
+}
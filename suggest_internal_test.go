@@ -0,0 +1,37 @@
+package biloba
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like explain_internal_test.go: they check that SuggestLocators rebuilds
+// what biloba.js suggests as the Locator and the Go code the By* constructors would.  No browser.
+
+func TestDecodeSuggestions(t *testing.T) {
+	g := NewWithT(t)
+	b := &Biloba{}
+	suggestions, err := b.decodeSuggestions([]any{
+		map[string]any{"by": "testid", "value": "save-button", "valueMode": "exact", "attr": "data-testid"},
+		map[string]any{"by": "role", "role": "button", "nameSet": true, "name": `Say "hi"`, "nameMode": "exact",
+			"within": map[string]any{"by": "role", "role": "region", "nameSet": true, "name": "Drafts", "nameMode": "exact"}},
+		map[string]any{"by": "placeholder", "value": "you@example.com", "valueMode": "exact"},
+		map[string]any{"by": "role", "role": "main"},
+		map[string]any{"by": "css", "value": "body > div:nth-of-type(3)"},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(suggestions).To(HaveLen(5))
+
+	g.Expect(suggestions[0].Code).To(Equal(`b.ByTestID("save-button")`))
+	g.Expect(suggestions[0].Locator).To(Equal(b.ByTestID("save-button")))
+
+	g.Expect(suggestions[1].Code).To(Equal(`b.ByRole("button").WithName("Say \"hi\"").Within(b.ByRole("region").WithName("Drafts"))`))
+	g.Expect(suggestions[1].Locator).To(Equal(b.ByRole("button").WithName(`Say "hi"`).Within(b.ByRole("region").WithName("Drafts"))))
+
+	g.Expect(suggestions[2].String()).To(Equal(`b.ByPlaceholder("you@example.com")`))
+	g.Expect(suggestions[2].Locator).To(Equal(b.ByPlaceholder("you@example.com")))
+
+	g.Expect(suggestions[3].Code).To(Equal(`b.ByRole("main")`))
+	g.Expect(suggestions[4].Locator).To(Equal(b.ByCSS("body > div:nth-of-type(3)")))
+}
@@ -0,0 +1,72 @@
+package biloba_test
+
+import (
+	"github.com/onsi/biloba"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuggestLocators", func() {
+	codes := func(suggestions []biloba.LocatorSuggestion) []string {
+		out := []string{}
+		for _, s := range suggestions {
+			out = append(out, s.Code)
+		}
+		return out
+	}
+
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/suggest.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	It("ranks the unique locators for an element, test id first", func() {
+		Ω(codes(b.SuggestLocators("#save"))).Should(HaveExactElements(
+			`b.ByTestID("save-button")`,
+			`b.ByRole("button").WithName("Save")`,
+			`b.ByText("Save")`,
+		))
+	})
+
+	It("suggests the label and placeholder of a form control", func() {
+		Ω(codes(b.SuggestLocators("#email"))).Should(HaveExactElements(
+			`b.ByRole("textbox").WithName("Email")`,
+			`b.ByLabel("Email")`,
+			`b.ByPlaceholder("you@example.com")`,
+		))
+	})
+
+	It("suggests alt text and title", func() {
+		Ω(codes(b.SuggestLocators("#logo"))).Should(HaveExactElements(
+			`b.ByRole("img").WithName("Acme logo")`,
+			`b.ByAltText("Acme logo")`,
+			`b.ByTitle("Home")`,
+		))
+	})
+
+	It("scopes an ambiguous locator within the closest uniquely-named ancestor", func() {
+		suggestions := b.SuggestLocators("#delete-published")
+		Ω(codes(suggestions)).Should(HaveExactElements(
+			`b.ByRole("button").WithName("Delete").Within(b.ByRole("region").WithName("Published"))`,
+			`b.ByText("Delete").Within(b.ByRole("region").WithName("Published"))`,
+		))
+		b.Click(suggestions[0].Locator)
+		Ω("#clicked").Should(b.HaveInnerText("published"))
+	})
+
+	It("returns locators that find the element", func() {
+		for _, s := range b.SuggestLocators("#save") {
+			Ω(s.Locator).Should(b.HaveCount(1))
+			Ω(b.GetProperty(s.Locator, "id")).Should(Equal("save"))
+		}
+	})
+
+	It("falls back to the CSS path when nothing else pins the element down", func() {
+		Ω(codes(b.SuggestLocators(".anonymous"))).Should(HaveExactElements(`b.ByCSS("body > div:nth-of-type(3) > div > span")`))
+	})
+
+	It("fails when the selector matches nothing", func() {
+		b.SuggestLocators("#nothing")
+		ExpectFailures(ContainSubstring("Failed to suggest locators"))
+	})
+})
@@ -0,0 +1,228 @@
+package biloba
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+)
+
+/*
+Table is a data table as [Biloba.GetTable] reads it: the text of its header cells, one per column, and
+the text of every cell in every body row.  Cells that span several columns or rows have their text
+repeated in each slot they cover, so every row is as wide as Headers.  Headers is nil for a table
+without header cells.
+*/
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+/*
+Records returns the body rows as a [SliceOfProperties], each row keyed by its columns' header text:
+
+	orders := b.GetTable("#orders").Records()
+	Ω(orders[0].GetString("Status")).Should(Equal("Shipped"))
+
+Columns without header text are left out.
+*/
+func (t Table) Records() SliceOfProperties {
+	records := SliceOfProperties{}
+	for _, row := range t.Rows {
+		records = append(records, t.record(row))
+	}
+	return records
+}
+
+func (t Table) record(row []string) Properties {
+	record := Properties{}
+	for i, header := range t.Headers {
+		if header != "" && i < len(row) {
+			record[header] = row[i]
+		}
+	}
+	return record
+}
+
+// String renders the table as aligned text, one row per line, with a rule under the headers.
+func (t Table) String() string {
+	lines := [][]string{}
+	if t.Headers != nil {
+		lines = append(lines, t.Headers)
+	}
+	lines = append(lines, t.Rows...)
+	widths := []int{}
+	for _, line := range lines {
+		for i, cell := range line {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	out := &strings.Builder{}
+	for n, line := range lines {
+		cells := make([]string, len(line))
+		for i, cell := range line {
+			cells[i] = cell + strings.Repeat(" ", widths[i]-len([]rune(cell)))
+		}
+		fmt.Fprintf(out, "%s\n", strings.TrimRight(strings.Join(cells, " | "), " "))
+		if n == 0 && t.Headers != nil {
+			rules := make([]string, len(widths))
+			for i, w := range widths {
+				rules[i] = strings.Repeat("-", w)
+			}
+			fmt.Fprintf(out, "%s\n", strings.Join(rules, "-+-"))
+		}
+	}
+	return out.String()
+}
+
+/*
+GetTable(selector) reads the table matching selector - an HTML <table>, or an element with ARIA role
+grid, table or treegrid built from row, columnheader, rowheader, cell and gridcell elements - into a
+[Table] of header and cell text:
+
+	table := b.GetTable("#orders")
+	Ω(table.Headers).Should(Equal([]string{"Order", "Customer", "Status"}))
+	Ω(table.Rows[0]).Should(Equal([]string{"#1001", "Ada", "Shipped"}))
+
+	Ω(table.Records()[0].GetString("Customer")).Should(Equal("Ada"))
+
+Cells are read as their visible text, whitespace-collapsed.  colspan and rowspan (aria-colspan and
+aria-rowspan in a grid) are resolved: a spanning cell's text fills every slot it covers, so row i,
+column j is always Rows[i][j].  The header rows are the leading rows made entirely of header cells
+(<th>, or role columnheader); a table with more than one combines them into one header per column,
+"Q1 / Jan".  Rows that aren't rendered - a filtered-out row with display:none - are skipped.
+
+Like [Biloba.GetProperty], GetTable polls until the table exists.  To wait for its contents, use
+[Biloba.HaveTableRows] or [Biloba.HaveTableRow].
+
+Read https://onsi.github.io/biloba/#tables to learn more
+*/
+func (b *Biloba) GetTable(selector any) Table {
+	b.gt.Helper()
+	var table Table
+	matcher := gcustom.MakeMatcher(func(sel any) (bool, error) {
+		t, err := b.readTable(sel)
+		if err != nil {
+			return false, err
+		}
+		table = t
+		return true, nil
+	}).WithMessage("be a table")
+	b.pollOrImmediate(selector, matcher)
+	return table
+}
+
+/*
+HaveTableRows(rows...) is a Gomega matcher that passes once the body rows of the table matching the
+selector it is applied to are exactly rows, in order.  Each row is a []string of cell text, or a Gomega
+matcher for the row:
+
+	Eventually("#orders").Should(b.HaveTableRows(
+		[]string{"#1001", "Ada", "Shipped"},
+		[]string{"#1002", "Grace", "Pending"},
+	))
+
+Pass a single Gomega matcher to match the [][]string of rows as a whole:
+
+	Eventually("#orders").Should(b.HaveTableRows(HaveLen(20)))
+	Eventually("#orders").Should(b.HaveTableRows(ContainElement([]string{"#1002", "Grace", "Pending"})))
+
+Tables are read as [Biloba.GetTable] reads them, and the failure message prints the table.  It returns
+a [ValueMatcher], so you can keep the rows that satisfied the assertion with .Capture(&rows).
+
+Read https://onsi.github.io/biloba/#tables to learn more
+*/
+func (b *Biloba) HaveTableRows(rows ...any) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveTableRows")
+	var matcher types.GomegaMatcher
+	if len(rows) == 1 {
+		if m, ok := rows[0].(types.GomegaMatcher); ok {
+			matcher = m
+		}
+	}
+	if matcher == nil {
+		matcher = gomega.HaveExactElements(rows...)
+	}
+	data := map[string]any{"Matcher": matcher}
+	return capturableResult(gcustom.MakeMatcher(func(selector any) (bool, error) {
+		table, err := b.readTable(selector)
+		if err != nil {
+			return false, err
+		}
+		data["Result"] = table.Rows
+		data["Table"] = table
+		return matcher.Match(table.Rows)
+	}).WithTemplate("HaveTableRows for {{.Actual}}:\n{{if .Failure}}{{.Data.Matcher.FailureMessage .Data.Result}}{{else}}{{.Data.Matcher.NegatedFailureMessage .Data.Result}}{{end}}\nThe table was:\n{{.Data.Table}}", data), data)
+}
+
+/*
+HaveTableRow(columns) is a Gomega matcher that passes once the table matching the selector it is
+applied to has a body row whose cells, looked up by header text, match columns.  Each value is the
+cell's expected text, or a Gomega matcher for it; columns you leave out can hold anything:
+
+	Eventually("#orders").Should(b.HaveTableRow(map[string]any{"Customer": "Grace", "Status": "Shipped"}))
+	Eventually("#orders").Should(b.HaveTableRow(map[string]any{"Order": HavePrefix("#10"), "Status": "Pending"}))
+
+A column name that isn't one of the table's headers fails immediately.  Tables are read as
+[Biloba.GetTable] reads them, and the failure message prints the table.  It returns a [ValueMatcher],
+so you can keep the first matching row, as [Properties] keyed by header, with .Capture(&row).
+
+Read https://onsi.github.io/biloba/#tables to learn more
+*/
+func (b *Biloba) HaveTableRow(columns map[string]any) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveTableRow")
+	matchers := map[string]types.GomegaMatcher{}
+	for column, expected := range columns {
+		matchers[column] = matcherOrEqual(expected)
+	}
+	data := map[string]any{"Columns": format.Object(columns, 1)}
+	return capturableResult(gcustom.MakeMatcher(func(selector any) (bool, error) {
+		table, err := b.readTable(selector)
+		if err != nil {
+			return false, err
+		}
+		data["Table"] = table
+		for column := range matchers {
+			if !slices.Contains(table.Headers, column) {
+				return false, gomega.StopTrying(fmt.Sprintf("HaveTableRow: %q is not a column of %s.  The table was:\n%s", column, describeSnapshotSubject(selector), table))
+			}
+		}
+		delete(data, "Result")
+		for _, row := range table.Rows {
+			record := table.record(row)
+			matched := true
+			for column, matcher := range matchers {
+				if ok, err := matcher.Match(record[column]); err != nil || !ok {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				data["Result"] = record
+				return true, nil
+			}
+		}
+		return false, nil
+	}).WithTemplate("Expected {{.Actual}} {{.To}} have a table row with:\n{{.Data.Columns}}\nThe table was:\n{{.Data.Table}}", data), data)
+}
+
+func (b *Biloba) readTable(selector any) (Table, error) {
+	r := b.runBilobaHandler("getTable", selector)
+	if r.Error() != nil {
+		return Table{}, r.Error()
+	}
+	table := Table{}
+	if err := remarshal(r.Result, &table); err != nil {
+		return Table{}, err
+	}
+	return table, nil
+}
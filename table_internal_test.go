@@ -0,0 +1,37 @@
+package biloba
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// Plain testing-based units, like explain_internal_test.go: they check how a Table renders and keys
+// its rows by header.  No browser.
+
+func TestTableString(t *testing.T) {
+	g := NewWithT(t)
+	table := Table{
+		Headers: []string{"Order", "Customer", "Status"},
+		Rows:    [][]string{{"#1001", "Ada", "Shipped"}, {"#1002", "Grace", "Pending"}},
+	}
+	g.Expect(table.String()).To(Equal("Order | Customer | Status\n" +
+		"------+----------+--------\n" +
+		"#1001 | Ada      | Shipped\n" +
+		"#1002 | Grace    | Pending\n"))
+
+	g.Expect(Table{Rows: [][]string{{"a", "bb"}, {"ccc", ""}}}.String()).To(Equal("a   | bb\nccc |\n"))
+}
+
+func TestTableRecords(t *testing.T) {
+	g := NewWithT(t)
+	table := Table{
+		Headers: []string{"Name", "", "Team"},
+		Rows:    [][]string{{"Ada", "x", "Core"}, {"Grace", "y", "Tools"}},
+	}
+	g.Expect(table.Records()).To(Equal(SliceOfProperties{
+		{"Name": "Ada", "Team": "Core"},
+		{"Name": "Grace", "Team": "Tools"},
+	}))
+	g.Expect(Table{Rows: [][]string{{"a"}}}.Records()).To(Equal(SliceOfProperties{{}}))
+}
@@ -0,0 +1,120 @@
+package biloba_test
+
+import (
+	"github.com/onsi/biloba"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tables", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/table.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	Describe("GetTable", func() {
+		It("reads the headers and the rendered body rows", func() {
+			table := b.GetTable("#orders")
+			Ω(table.Headers).Should(Equal([]string{"Order", "Customer", "Status"}))
+			Ω(table.Rows).Should(Equal([][]string{
+				{"#1001", "Ada", "Shipped"},
+				{"#1002", "Grace", "Pending"},
+			}))
+		})
+
+		It("returns the rows keyed by header", func() {
+			records := b.GetTable("#orders").Records()
+			Ω(records).Should(HaveLen(2))
+			Ω(records[1].GetString("Customer")).Should(Equal("Grace"))
+			Ω(records[1]).Should(Equal(biloba.Properties{"Order": "#1002", "Customer": "Grace", "Status": "Pending"}))
+		})
+
+		It("resolves colspan and rowspan, and combines several header rows", func() {
+			table := b.GetTable("#spans")
+			Ω(table.Headers).Should(Equal([]string{"Region", "Q1 / Jan", "Q1 / Feb"}))
+			Ω(table.Rows).Should(Equal([][]string{
+				{"North", "1", "2"},
+				{"North", "3", "4"},
+				{"South", "n/a", "n/a"},
+			}))
+		})
+
+		It("reads a table without headers", func() {
+			table := b.GetTable("#headless")
+			Ω(table.Headers).Should(BeNil())
+			Ω(table.Rows).Should(Equal([][]string{{"a", "b"}, {"c", "d"}}))
+		})
+
+		It("reads ARIA grids", func() {
+			table := b.GetTable(b.ByRole("grid"))
+			Ω(table.Headers).Should(Equal([]string{"Name", "Role", "Team"}))
+			Ω(table.Rows).Should(Equal([][]string{
+				{"Ada", "Engineer", "Core"},
+				{"Grace", "On leave", "On leave"},
+			}))
+		})
+
+		It("fails when the element is not a table", func() {
+			b.Immediate().GetTable("#not-a-table")
+			ExpectFailures(ContainSubstring("element is not a <table>, or an element with role grid, table or treegrid"))
+		})
+	})
+
+	Describe("HaveTableRows", func() {
+		It("matches the body rows in order", func() {
+			Ω("#orders").Should(b.HaveTableRows(
+				[]string{"#1001", "Ada", "Shipped"},
+				[]string{"#1002", "Grace", "Pending"},
+			))
+			Ω("#orders").ShouldNot(b.HaveTableRows([]string{"#1001", "Ada", "Shipped"}))
+		})
+
+		It("applies a single matcher to all the rows, and polls", func() {
+			b.Click("#ship")
+			Eventually("#orders").Should(b.HaveTableRows(ContainElement([]string{"#1002", "Grace", "Shipped"})))
+			Ω("#orders").Should(b.HaveTableRows(HaveLen(2)))
+		})
+
+		It("prints the table when it fails", func() {
+			var failure string
+			g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+			g.Expect("#orders").Should(b.HaveTableRows(HaveLen(3)))
+			Ω(failure).Should(SatisfyAll(
+				ContainSubstring("HaveTableRows for #orders"),
+				ContainSubstring("Order | Customer | Status\n------+----------+--------\n#1001 | Ada      | Shipped\n"),
+			))
+		})
+
+		It("captures the rows", func() {
+			var rows [][]string
+			Ω("#spans").Should(b.HaveTableRows(HaveLen(3)).Capture(&rows))
+			Ω(rows[2]).Should(Equal([]string{"South", "n/a", "n/a"}))
+		})
+	})
+
+	Describe("HaveTableRow", func() {
+		It("matches a row by some of its columns", func() {
+			Ω("#orders").Should(b.HaveTableRow(map[string]any{"Customer": "Grace", "Status": "Pending"}))
+			Ω("#orders").ShouldNot(b.HaveTableRow(map[string]any{"Customer": "Linus"}))
+			Ω(b.ByRole("grid")).Should(b.HaveTableRow(map[string]any{"Name": "Ada", "Team": HavePrefix("Co")}))
+		})
+
+		It("polls", func() {
+			b.Click("#ship")
+			Eventually("#orders").Should(b.HaveTableRow(map[string]any{"Customer": "Grace", "Status": "Shipped"}))
+		})
+
+		It("captures the matching row", func() {
+			var row biloba.Properties
+			Ω("#orders").Should(b.HaveTableRow(map[string]any{"Order": HaveSuffix("01")}).Capture(&row))
+			Ω(row.GetString("Customer")).Should(Equal("Ada"))
+		})
+
+		It("fails immediately on a column the table doesn't have", func() {
+			var failure string
+			g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+			g.Eventually("#orders").Should(b.HaveTableRow(map[string]any{"Price": "$10"}))
+			Ω(failure).Should(ContainSubstring(`HaveTableRow: "Price" is not a column of #orders`))
+		})
+	})
+})
@@ -89,16 +89,25 @@ func (b *Biloba) performSetUpload(selector any, paths []string) (bool, error) {
 		return false, err
 	}
 
+	// the node handle belongs to the session hosting this view's document, which for a cross-origin
+	// Frame() view is the frame's own target - so the file chooser is set there too
+	session, err := b.documentSession()
+	if err != nil {
+		return false, err
+	}
+	if err := b.resolveAXQueries(encoded); err != nil {
+		return false, err
+	}
 	var node *runtime.RemoteObject
-	script := b.JSFunc("_biloba.node").Invoke(encoded)
-	if err := chromedp.Run(b.Context, chromedp.Evaluate(script, &node)); err != nil {
+	script := b.bilobaInvocation("node", encoded)
+	if err := session.evaluate(script, &node); err != nil {
 		return false, err
 	}
 	if node == nil || node.ObjectID == "" {
 		return false, nil
 	}
 
-	if err := chromedp.Run(b.Context, dom.SetFileInputFiles(paths).WithObjectID(node.ObjectID)); err != nil {
+	if err := chromedp.Run(session.ctx, dom.SetFileInputFiles(paths).WithObjectID(node.ObjectID)); err != nil {
 		return false, err
 	}
 	return true, nil
@@ -13,7 +13,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
 	"github.com/onsi/gomega"
@@ -65,15 +64,16 @@ func screenshotSettleGap(n int) time.Duration {
 
 // screenshotConfig is the resolved configuration for one HaveScreenshot assertion.
 type screenshotConfig struct {
-	masks        []any
-	tolerance    screenshotTolerance
-	animated     bool
-	colorSchemes []string
+	masks     []any
+	tolerance screenshotTolerance
+	animated  bool
+	variants  []mediaEmulation
 }
 
 /*
 ScreenshotOption configures [Biloba.HaveScreenshot].  The options are [Biloba.Mask],
-[Biloba.Tolerance], [Biloba.ChannelTolerance], [Biloba.Animated], and [Biloba.InColorSchemes]:
+[Biloba.Tolerance], [Biloba.ChannelTolerance], [Biloba.Animated], [Biloba.InColorSchemes], and
+[Biloba.InMediaVariants]:
 
 	Eventually(".receipt").Should(b.HaveScreenshot("receipt", b.Mask(".timestamp"), b.Tolerance(0.001)))
 
@@ -165,7 +165,38 @@ anything about the other scheme.  Biloba warns when two schemes capture byte-ide
 Read https://onsi.github.io/biloba/#visual-assertions to learn more about visual assertions
 */
 func (b *Biloba) InColorSchemes(schemes ...string) ScreenshotOption {
-	return func(c *screenshotConfig) { c.colorSchemes = append(c.colorSchemes, schemes...) }
+	return func(c *screenshotConfig) {
+		for _, scheme := range schemes {
+			c.variants = append(c.variants, newMediaEmulation(MediaFeatures{ColorScheme: scheme}))
+		}
+	}
+}
+
+/*
+InMediaVariants(variants...) is a [ScreenshotOption] for [Biloba.HaveScreenshot] that generalises
+[Biloba.InColorSchemes] to any emulated media: the subject is captured and compared once per variant,
+and every variant must match for the assertion to pass.  Each variant is a [MediaOption] - a
+[MediaFeatures] value or [PrintMedia]:
+
+	Eventually(b).Should(b.HaveScreenshot("checkout", b.InMediaVariants(
+		biloba.MediaFeatures{ForcedColors: "active"},
+		biloba.MediaFeatures{Contrast: "more"},
+		biloba.PrintMedia(),
+	)))
+
+Each variant gets its own baseline, named after what it emulates: checkout-forced-colors-active.png,
+checkout-contrast-more.png, checkout-print.png.  A variant is layered on top of whatever
+[Biloba.EmulateMedia] already set on the tab, and the tab goes back to exactly that once the capture
+is taken.  Like InColorSchemes, Biloba warns when two variants capture byte-identical images.
+
+Read https://onsi.github.io/biloba/#visual-assertions to learn more about visual assertions
+*/
+func (b *Biloba) InMediaVariants(variants ...MediaOption) ScreenshotOption {
+	return func(c *screenshotConfig) {
+		for _, variant := range variants {
+			c.variants = append(c.variants, newMediaEmulation(variant))
+		}
+	}
 }
 
 /*
@@ -200,8 +231,8 @@ rendered diff next to the failure screenshots and says what moved, where, and by
 human is driving a terminal that can render images, the diff is also drawn inline underneath that
 diagnosis (see [BilobaConfigInlineScreenshots]); the words are printed either way.
 
-Configure it with [Biloba.Mask], [Biloba.Tolerance], [Biloba.ChannelTolerance], [Biloba.Animated], and
-[Biloba.InColorSchemes].
+Configure it with [Biloba.Mask], [Biloba.Tolerance], [Biloba.ChannelTolerance], [Biloba.Animated],
+[Biloba.InColorSchemes], and [Biloba.InMediaVariants].
 
 Read https://onsi.github.io/biloba/#visual-assertions to learn more about visual assertions
 */
@@ -212,10 +243,10 @@ func (b *Biloba) HaveScreenshot(name string, options ...ScreenshotOption) types.
 	for _, option := range options {
 		option(&cfg)
 	}
-	if len(cfg.colorSchemes) == 0 {
-		// The empty scheme means "don't emulate prefers-color-scheme at all": one capture, one
+	if len(cfg.variants) == 0 {
+		// The zero variant means "don't emulate anything on top of the tab": one capture, one
 		// baseline, whatever the page renders on its own.
-		cfg.colorSchemes = []string{""}
+		cfg.variants = []mediaEmulation{{}}
 	}
 	m := &screenshotMatcher{b: b, name: name, cfg: cfg}
 	m.CustomGomegaMatcher = gcustom.MakeMatcher(m.match)
@@ -240,26 +271,26 @@ type screenshotMatcher struct {
 	name string
 	cfg  screenshotConfig
 
-	// The last failing comparison, kept so FailureMessage can render it.  scheme names which colour
-	// scheme it belongs to; actual is the masked PNG that produced it.  lock guards all three: match
+	// The last failing comparison, kept so FailureMessage can render it.  variant names which media
+	// variant it belongs to; actual is the masked PNG that produced it.  lock guards all three: match
 	// writes them, FailureMessage reads them, and the two run on different goroutines.
-	lock   sync.Mutex
-	scheme string
-	diff   *screenshotDiff
-	actual []byte
+	lock    sync.Mutex
+	variant mediaEmulation
+	diff    *screenshotDiff
+	actual  []byte
 
 	// warned keys the one-shot warnings this matcher has already printed.  Everything here is a
 	// diagnosis of the SETUP rather than of the page, so it is the same on every poll attempt: printed
 	// per attempt it would bury the failure it is trying to explain.
 	warned map[string]bool
-	// captures holds this attempt's capture per colour scheme, so two schemes that render identically
-	// can be caught.  Reset at the top of every attempt - the comparison is between schemes within one
+	// captures holds this attempt's capture per media variant, so two variants that render identically
+	// can be caught.  Reset at the top of every attempt - the comparison is between variants within one
 	// attempt, never across attempts.
-	captures map[string][]byte
+	captures map[mediaEmulation][]byte
 }
 
-// match runs one full attempt: every configured colour scheme is captured and compared, and they must
-// all match.  A scheme that differs comes back as (false, nil) so the caller's Eventually keeps
+// match runs one full attempt: every configured media variant is captured and compared, and they must
+// all match.  A variant that differs comes back as (false, nil) so the caller's Eventually keeps
 // waiting for the page to settle; only a condition that can never come true (a missing baseline, an
 // undecodable PNG) aborts the poll with StopTrying.
 func (m *screenshotMatcher) match(actual any) (bool, error) {
@@ -267,15 +298,15 @@ func (m *screenshotMatcher) match(actual any) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	m.record("", nil, nil)
-	m.captures = map[string][]byte{}
-	for _, scheme := range m.cfg.colorSchemes {
-		pass, err := m.matchScheme(tab, selector, scheme)
+	m.record(mediaEmulation{}, nil, nil)
+	m.captures = map[mediaEmulation][]byte{}
+	for _, variant := range m.cfg.variants {
+		pass, err := m.matchVariant(tab, selector, variant)
 		if err != nil {
 			return false, err
 		}
 		if !pass {
-			return false, nil // the first failing scheme is the one we diagnose
+			return false, nil // the first failing variant is the one we diagnose
 		}
 	}
 	return true, nil
@@ -295,9 +326,9 @@ func (m *screenshotMatcher) subject(actual any) (*Biloba, any, error) {
 	return m.b, actual, nil
 }
 
-func (m *screenshotMatcher) matchScheme(tab *Biloba, selector any, scheme string) (bool, error) {
-	label := m.label(scheme)
-	baselinePath, err := m.baselinePath(scheme)
+func (m *screenshotMatcher) matchVariant(tab *Biloba, selector any, variant mediaEmulation) (bool, error) {
+	label := m.label(variant)
+	baselinePath, err := m.baselinePath(variant)
 	if err != nil {
 		return false, gomega.StopTrying(err.Error())
 	}
@@ -305,28 +336,28 @@ func (m *screenshotMatcher) matchScheme(tab *Biloba, selector any, scheme string
 	if m.b.root.updateScreenshots {
 		// Update mode has no Eventually behind it, so it has to do its own settling - see
 		// captureSettled.
-		img, err := m.captureSettled(tab, selector, scheme, label)
+		img, err := m.captureSettled(tab, selector, variant, label)
 		if err != nil {
 			return false, m.refuseToWrite(label, err)
 		}
-		m.noteCapture(scheme, img)
+		m.noteCapture(variant, img)
 		baseline, readErr := os.ReadFile(baselinePath)
 		return m.updateBaseline(baselinePath, baseline, readErr, img, label)
 	}
 
-	img, clippedNote, err := tab.captureForComparison(selector, m.cfg, scheme)
+	img, clippedNote, err := tab.captureForComparison(selector, m.cfg, variant)
 	m.warnClipped(clippedNote)
 	if err != nil {
 		return false, err
 	}
-	m.noteCapture(scheme, img)
+	m.noteCapture(variant, img)
 	baseline, readErr := os.ReadFile(baselinePath)
 
 	if os.IsNotExist(readErr) {
 		// Writing the baseline here and passing would mean a spec that has never compared anything
 		// reads green in CI.  So this is a failure - and a StopTrying one, since no amount of polling
 		// is going to conjure the file.
-		actualPath := writeVisualArtifact(m.artifactPath(scheme, "actual"), img)
+		actualPath := writeVisualArtifact(m.artifactPath(variant, "actual"), img)
 		return false, gomega.StopTrying(missingBaselineMessage(label, baselinePath, actualPath))
 	}
 	if readErr != nil {
@@ -340,7 +371,7 @@ func (m *screenshotMatcher) matchScheme(tab *Biloba, selector any, scheme string
 	if diff.Match {
 		return true, nil
 	}
-	m.record(scheme, diff, img)
+	m.record(variant, diff, img)
 	return false, nil
 }
 
@@ -357,23 +388,23 @@ func (m *screenshotMatcher) refuseToWrite(label string, err error) error {
 	return err
 }
 
-// noteCapture records this attempt's capture for a colour scheme and warns when two schemes rendered
+// noteCapture records this attempt's capture for a media variant and warns when two variants rendered
 // identically.  Two identical captures under two names is the quietest way a visual assertion can go
 // vacuous: prefers-color-scheme emulation only reaches an app while that app is following the system,
 // so a suite with a manual theme override (or a leftover localStorage value) writes the same picture
 // to home-light.png and home-dark.png.  Both baselines look right, both comparisons pass, and the
 // filename is the only thing claiming the dark rendering was ever exercised.
-func (m *screenshotMatcher) noteCapture(scheme string, img []byte) {
-	if len(m.cfg.colorSchemes) < 2 || m.captures == nil || len(img) == 0 {
+func (m *screenshotMatcher) noteCapture(variant mediaEmulation, img []byte) {
+	if len(m.cfg.variants) < 2 || m.captures == nil || len(img) == 0 {
 		return
 	}
 	for other, previous := range m.captures {
 		if bytes.Equal(previous, img) {
-			m.warnOnce("identical-schemes", "Warning: %q captured byte-identical images under prefers-color-scheme %q and %q.\nBoth baselines will be written and both comparisons will pass, but only one rendering was ever exercised - the other assertion cannot fail.\nprefers-color-scheme emulation only reaches an app while that app is following the system scheme; check that nothing in this spec (or a leftover stored preference) has pinned the theme.\n", m.name, other, scheme)
+			m.warnOnce("identical-variants", "Warning: %q captured byte-identical images under %s and %s.\nBoth baselines will be written and both comparisons will pass, but only one rendering was ever exercised - the other assertion cannot fail.\nMedia emulation only reaches an app whose styles actually follow the media query; check that nothing in this spec (or a leftover stored preference, such as a pinned theme) overrides it.\n", m.name, other.describe(), variant.describe())
 			return
 		}
 	}
-	m.captures[scheme] = img
+	m.captures[variant] = img
 }
 
 // warnClipped surfaces captureForComparison's partial-clip note, once per assertion.
@@ -402,16 +433,16 @@ func (m *screenshotMatcher) warnOnce(key string, format string, args ...any) {
 
 // record and lastFailure are the only access to the shared comparison state - see the note on
 // screenshotMatcher for why FailureMessage can run concurrently with match.
-func (m *screenshotMatcher) record(scheme string, diff *screenshotDiff, actual []byte) {
+func (m *screenshotMatcher) record(variant mediaEmulation, diff *screenshotDiff, actual []byte) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	m.scheme, m.diff, m.actual = scheme, diff, actual
+	m.variant, m.diff, m.actual = variant, diff, actual
 }
 
-func (m *screenshotMatcher) lastFailure() (string, *screenshotDiff, []byte) {
+func (m *screenshotMatcher) lastFailure() (mediaEmulation, *screenshotDiff, []byte) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	return m.scheme, m.diff, m.actual
+	return m.variant, m.diff, m.actual
 }
 
 // captureSettled captures until screenshotSettleStreak captures in a row compare equal under the
@@ -425,7 +456,7 @@ func (m *screenshotMatcher) lastFailure() (string, *screenshotDiff, []byte) {
 // The comparison uses the configured tolerance so ordinary antialiasing noise cannot keep the page
 // from ever counting as settled, and it runs on the images captureForComparison already masked: a
 // clock that has been masked out must not be what stops the page from settling.
-func (m *screenshotMatcher) captureSettled(tab *Biloba, selector any, scheme string, label string) ([]byte, error) {
+func (m *screenshotMatcher) captureSettled(tab *Biloba, selector any, variant mediaEmulation, label string) ([]byte, error) {
 	start := time.Now()
 	var previous []byte
 	streak := 1 // a lone capture is a run of one; it takes screenshotSettleStreak of them to settle
@@ -433,7 +464,7 @@ func (m *screenshotMatcher) captureSettled(tab *Biloba, selector any, scheme str
 		if attempt > 0 {
 			time.Sleep(screenshotSettleGap(attempt - 1))
 		}
-		current, clippedNote, err := tab.captureForComparison(selector, m.cfg, scheme)
+		current, clippedNote, err := tab.captureForComparison(selector, m.cfg, variant)
 		m.warnClipped(clippedNote)
 		if err != nil {
 			return nil, err
@@ -479,22 +510,22 @@ func (m *screenshotMatcher) updateBaseline(path string, previous []byte, readErr
 func (m *screenshotMatcher) FailureMessage(actual any) string {
 	// Snapshot the shared state and let go of the lock: everything below is image analysis and disk
 	// I/O, and holding the lock across it would stall the poll that is producing it.
-	scheme, diff, img := m.lastFailure()
+	variant, diff, img := m.lastFailure()
 	if diff == nil {
 		return fmt.Sprintf("Expected the screenshot to match the %q baseline.", m.name)
 	}
 	// This is where the expensive half of the comparison happens - once, on the attempt that reports,
 	// rather than on every failing poll attempt.
 	diff.analyze()
-	label := m.label(scheme)
-	paths := screenshotPaths{Actual: writeVisualArtifact(m.artifactPath(scheme, "actual"), img)}
-	if baselinePath, err := m.baselinePath(scheme); err == nil {
+	label := m.label(variant)
+	paths := screenshotPaths{Actual: writeVisualArtifact(m.artifactPath(variant, "actual"), img)}
+	if baselinePath, err := m.baselinePath(variant); err == nil {
 		paths.Baseline = baselinePath
 	}
 	var diffPNG []byte
 	if encoded, err := diff.encodeDiffPNG(); err == nil && encoded != nil {
 		diffPNG = encoded
-		paths.Diff = writeVisualArtifact(m.artifactPath(scheme, "diff"), diffPNG)
+		paths.Diff = writeVisualArtifact(m.artifactPath(variant, "diff"), diffPNG)
 	}
 	return diff.diagnose(label, paths) + m.inlineDiffImage(diffPNG)
 }
@@ -526,16 +557,16 @@ func (m *screenshotMatcher) NegatedFailureMessage(actual any) string {
 }
 
 // label names the comparison the way a failure message should: the baseline name, plus the colour
-// scheme when there is more than one thing called by that name.
-func (m *screenshotMatcher) label(scheme string) string {
-	if scheme == "" {
+// media variant when there is more than one thing called by that name.
+func (m *screenshotMatcher) label(variant mediaEmulation) string {
+	if variant.isZero() {
 		return m.name
 	}
-	return fmt.Sprintf("%s (prefers-color-scheme: %s)", m.name, scheme)
+	return fmt.Sprintf("%s (%s)", m.name, variant.describe())
 }
 
-func (m *screenshotMatcher) baselinePath(scheme string) (string, error) {
-	rel, err := baselineRelativePath(m.name, scheme)
+func (m *screenshotMatcher) baselinePath(variant mediaEmulation) (string, error) {
+	rel, err := baselineRelativePath(m.name, variant.suffix())
 	if err != nil {
 		return "", err
 	}
@@ -545,24 +576,24 @@ func (m *screenshotMatcher) baselinePath(scheme string) (string, error) {
 // artifactPath is where the actual/diff PNG for this comparison goes.  Artifacts are flat files in the
 // (gitignored) screenshots directory, so a name with "/" in it collapses to a single sanitized
 // filename rather than growing a directory tree nobody committed.
-func (m *screenshotMatcher) artifactPath(scheme string, kind string) string {
+func (m *screenshotMatcher) artifactPath(variant mediaEmulation, kind string) string {
 	name := sanitizeForFilename(m.name)
-	if scheme != "" {
-		name += "-" + sanitizeForFilename(scheme)
+	if suffix := variant.suffix(); suffix != "" {
+		name += "-" + sanitizeForFilename(suffix)
 	}
 	return absOrAsIs(filepath.Join(m.b.visualArtifactsDir(), fmt.Sprintf("%s.%s.png", name, kind)))
 }
 
-// captureForComparison performs one visual capture: emulate the colour scheme, freeze rendering,
+// captureForComparison performs one visual capture: emulate the media variant, freeze rendering,
 // capture, read the mask geometry while the page is still in exactly the state that was captured, and
 // paint the masks in.  Everything it turns on is turned off by a defer - a freeze stylesheet or an
-// emulated colour scheme left behind on an error path would silently corrupt every later assertion in
+// emulated media variant left behind on an error path would silently corrupt every later assertion in
 // this tab.
 //
 // It also reports a clippedNote: the non-fatal half of the clipped-capture check, empty unless an
 // ancestor cut part of the element out of its own capture.  It comes back rather than being printed
 // here because the caller polls, and a warning about the setup should be said once.
-func (b *Biloba) captureForComparison(selector any, cfg screenshotConfig, scheme string) (img []byte, clippedNote string, err error) {
+func (b *Biloba) captureForComparison(selector any, cfg screenshotConfig, variant mediaEmulation) (img []byte, clippedNote string, err error) {
 	// Web fonts first, before anything is captured.  The poll protects the COMPARISON - a capture taken
 	// mid-swap simply doesn't match and the next attempt tries again - but it cannot protect the WRITE,
 	// which passes on the first settled capture it gets.  And a settle cannot see a change that has not
@@ -572,16 +603,20 @@ func (b *Biloba) captureForComparison(selector any, cfg screenshotConfig, scheme
 	if r := b.runBilobaFuncAsync("fontsReady"); r.Error() != nil {
 		return nil, "", r.Error()
 	}
-	if scheme != "" {
-		if err := b.emulateColorScheme(scheme); err != nil {
+	if !variant.isZero() {
+		// The variant is layered on whatever EmulateMedia set, and the tab goes back to exactly that
+		// afterwards - restoring to "nothing emulated" would quietly undo the spec's own EmulateMedia.
+		base := b.media.tab
+		emulated := variant.over(base)
+		if err := b.emulateMedia(emulated); err != nil {
 			return nil, "", err
 		}
 		defer func() {
-			if err := b.emulateColorScheme(""); err != nil {
-				// A dropped clear used to be silent, and the override outlives Navigate: every later
-				// assertion in this tab would have rendered in the emulated scheme with no signal.  Say
+			if err := b.emulateMedia(base); err != nil {
+				// A dropped restore used to be silent, and the override outlives Navigate: every later
+				// assertion in this tab would have rendered in the emulated variant with no signal.  Say
 				// so, and leave the flag set so the next Prepare() tries again.
-				b.gt.Printf("Failed to clear the emulated prefers-color-scheme (%s) after a screenshot capture:\n  %s\nThis tab keeps rendering as %s until the next b.Prepare().\n", scheme, err.Error(), scheme)
+				b.gt.Printf("Failed to restore the emulated media after a screenshot capture under %s:\n  %s\nThis tab keeps rendering with %s until the next b.Prepare().\n", emulated.describe(), err.Error(), emulated.describe())
 			}
 		}()
 	}
@@ -644,46 +679,6 @@ func (b *Biloba) captureForComparison(selector any, cfg screenshotConfig, scheme
 	return masked, clippedNote, err
 }
 
-// emulateColorScheme overrides prefers-color-scheme for this tab; the empty scheme clears the
-// override.  It keeps b.colorSchemeEmulated honest so Prepare() knows whether there is anything to
-// clean up: the flag goes up BEFORE the command that sets the override (a command that reports an
-// error may still have landed) and only comes down when a clear actually succeeds.
-func (b *Biloba) emulateColorScheme(scheme string) error {
-	ctx, cancel := b.waitingContext(screenshotCaptureTimeout)
-	defer cancel()
-	params := emulation.SetEmulatedMedia()
-	if scheme != "" {
-		params = params.WithFeatures([]*emulation.MediaFeature{{Name: "prefers-color-scheme", Value: scheme}})
-		b.setColorSchemeEmulated(true)
-	}
-	err := chromedp.Run(ctx, params)
-	if scheme == "" && err == nil {
-		b.setColorSchemeEmulated(false)
-	}
-	return err
-}
-
-func (b *Biloba) setColorSchemeEmulated(emulated bool) {
-	if b.colorSchemeEmulated != nil {
-		*b.colorSchemeEmulated = emulated
-	}
-}
-
-// clearLeakedColorSchemeEmulation is Prepare()'s half of the colour-scheme teardown, called from
-// resetBrowsingState.  emulation.SetEmulatedMedia is a TARGET-level override: it survives Navigate,
-// so the one capture whose deferred clear did not land (an expired capture timeout, a cancelled
-// context) would otherwise leave every later spec in this process rendering in dark mode.  Gated on
-// the flag rather than run unconditionally - Prepare runs before every spec, and in the overwhelming
-// case there is no override and this costs nothing.
-func (b *Biloba) clearLeakedColorSchemeEmulation() {
-	if b.colorSchemeEmulated == nil || !*b.colorSchemeEmulated {
-		return
-	}
-	if err := b.emulateColorScheme(""); err != nil {
-		b.gt.Printf("Failed to clear a leaked emulated prefers-color-scheme during Prepare():\n  %s\n", err.Error())
-	}
-}
-
 // fullPageScreenshot captures the whole document and also reports its width in CSS pixels.  The two
 // come from the same round trip so they describe the same layout; the width is what maskRects divides
 // by to recover the device scale factor.
@@ -754,6 +749,10 @@ func (b *Biloba) maskRects(masks []any, img []byte, originX, originY, cssWidth f
 func (b *Biloba) runBilobaFunc(name string, args ...any) *bilobaJSResponse {
 	b.ensureBiloba()
 	result := &bilobaJSResponse{}
+	if err := b.resolveAXQueries(args...); err != nil {
+		result.Err = err.Error()
+		return result
+	}
 	if _, err := b.RunErr(b.JSFunc("_biloba."+name).Invoke(args...), result); err != nil {
 		result.Err = err.Error()
 	}
@@ -765,6 +764,10 @@ func (b *Biloba) runBilobaFunc(name string, args ...any) *bilobaJSResponse {
 func (b *Biloba) runBilobaFuncAsync(name string, args ...any) *bilobaJSResponse {
 	b.ensureBiloba()
 	result := &bilobaJSResponse{}
+	if err := b.resolveAXQueries(args...); err != nil {
+		result.Err = err.Error()
+		return result
+	}
 	if _, err := b.runErr(b.JSFunc("_biloba."+name).Invoke(args...), true, result); err != nil {
 		result.Err = err.Error()
 	}
@@ -795,28 +798,34 @@ func (b *Biloba) visualArtifactsDir() string {
 // the way failure-screenshot filenames are.  An absolute name or a ".." segment is refused rather than
 // sanitized: quietly rewriting a path that tries to leave the baselines directory would hide the
 // mistake instead of reporting it.
-func baselineRelativePath(name string, scheme string) (string, error) {
+func baselineRelativePath(name string, suffix string) (string, error) {
+	return baselineFilePath("HaveScreenshot", "screenshot", name, suffix, ".png")
+}
+
+// baselineFilePath is baselineRelativePath for any kind of baseline: method and noun name the matcher
+// and what it compares in the errors, and ext is the file extension the baseline is stored under.
+func baselineFilePath(method string, noun string, name string, suffix string, ext string) (string, error) {
 	if strings.TrimSpace(name) == "" {
-		return "", fmt.Errorf("HaveScreenshot needs a name to look its baseline up by, but was given an empty one")
+		return "", fmt.Errorf("%s needs a name to look its baseline up by, but was given an empty one", method)
 	}
 	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
-		return "", fmt.Errorf("the screenshot name %q must be relative to the baselines directory", name)
+		return "", fmt.Errorf("the %s name %q must be relative to the baselines directory", noun, name)
 	}
 	segments := strings.Split(name, "/")
 	for i, segment := range segments {
 		if segment == "" || segment == "." || segment == ".." {
-			return "", fmt.Errorf(`the screenshot name %q may not contain empty, "." or ".." path segments`, name)
+			return "", fmt.Errorf(`the %s name %q may not contain empty, "." or ".." path segments`, noun, name)
 		}
 		segments[i] = sanitizeForFilename(segment)
 		if segments[i] == "" {
-			return "", fmt.Errorf("the screenshot name %q has a path segment with no usable filename characters in it", name)
+			return "", fmt.Errorf("the %s name %q has a path segment with no usable filename characters in it", noun, name)
 		}
 	}
 	last := len(segments) - 1
-	if scheme != "" {
-		segments[last] += "-" + sanitizeForFilename(scheme)
+	if suffix != "" {
+		segments[last] += "-" + sanitizeForFilename(suffix)
 	}
-	segments[last] += ".png"
+	segments[last] += ext
 	return filepath.Join(segments...), nil
 }
 
@@ -374,8 +374,8 @@ func TestScreenshotMatcherFailureMessageRacesMatch(t *testing.T) {
 		for range 200 {
 			d, err := compareScreenshots(baseData, actData, screenshotTolerance{})
 			g.Expect(err).NotTo(HaveOccurred())
-			m.record("", nil, nil)
-			m.record("dark", d, actData)
+			m.record(mediaEmulation{}, nil, nil)
+			m.record(newMediaEmulation(MediaFeatures{ColorScheme: "dark"}), d, actData)
 		}
 	}()
 	for range 200 { // stands in for the progress reporter
@@ -637,3 +637,25 @@ func TestDiagnoseAmplitudeVerdict(t *testing.T) {
 		g.Expect(strings.Contains(d.diagnose("x", screenshotPaths{}), "rasterisation")).To(Equal(expected), "delta %d", delta)
 	}
 }
+
+func TestMediaVariantBaselineNames(t *testing.T) {
+	g := NewWithT(t)
+	for variant, expected := range map[mediaEmulation]string{
+		{}: "home.png",
+		newMediaEmulation(MediaFeatures{ColorScheme: "dark"}):                       "home-dark.png",
+		newMediaEmulation(MediaFeatures{ForcedColors: "active"}):                    "home-forced-colors-active.png",
+		newMediaEmulation(MediaFeatures{ReducedMotion: "reduce", Contrast: "more"}): "home-reduced-motion-reduce-contrast-more.png",
+		newMediaEmulation(PrintMedia()):                                             "home-print.png",
+		newMediaEmulation(MediaFeatures{ColorScheme: "dark"}, PrintMedia()):         "home-dark-print.png",
+	} {
+		rel, err := baselineRelativePath("home", variant.suffix())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(rel).To(Equal(expected))
+	}
+
+	g.Expect(newMediaEmulation(MediaFeatures{ForcedColors: "active"}, PrintMedia()).describe()).To(Equal("forced-colors: active, media: print"))
+
+	// a variant layers over what the tab already emulates rather than replacing it
+	base := newMediaEmulation(MediaFeatures{ReducedMotion: "reduce", ColorScheme: "light"})
+	g.Expect(newMediaEmulation(MediaFeatures{ColorScheme: "dark"}).over(base)).To(Equal(newMediaEmulation(MediaFeatures{ReducedMotion: "reduce", ColorScheme: "dark"})))
+}
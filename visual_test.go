@@ -715,6 +715,40 @@ var _ = Describe("Visual assertions", func() {
 		})
 	})
 
+	Describe("media variants", func() {
+		BeforeEach(func() {
+			b.Navigate(fixtureServer + "/visual.html")
+			Eventually("#media").Should(b.Exist())
+		})
+
+		It("writes and compares one baseline per variant", func() {
+			variants := b.InMediaVariants(biloba.MediaFeatures{Contrast: "more"}, biloba.MediaFeatures{ReducedMotion: "reduce"}, biloba.PrintMedia())
+			generate("#media", "media", variants)
+			Ω(baseline("media-contrast-more")).Should(BeAnExistingFile())
+			Ω(baseline("media-reduced-motion-reduce")).Should(BeAnExistingFile())
+			Ω(baseline("media-print")).Should(BeAnExistingFile())
+			Ω(baseline("media")).ShouldNot(BeAnExistingFile())
+			Eventually("#media").Should(b.HaveScreenshot("media", variants))
+		})
+
+		It("names the variant that failed", func() {
+			generate("#media", "media", b.InMediaVariants(biloba.PrintMedia()))
+			b.Run(`document.getElementById("media").style.outline = "3px solid red"`)
+			matcher := b.HaveScreenshot("media", b.InMediaVariants(biloba.PrintMedia()))
+			Ω("#media").ShouldNot(matcher)
+			Ω(matcher.FailureMessage("#media")).Should(ContainSubstring("media (media: print)"))
+		})
+
+		It("layers each variant on the tab's EmulateMedia and restores it afterwards", func() {
+			b.EmulateMedia(biloba.MediaFeatures{ReducedMotion: "reduce"})
+			generate("#media", "ambient")
+			generate("#media", "media", b.InMediaVariants(biloba.MediaFeatures{Contrast: "more"}))
+			Ω(b.Run(`window.matchMedia("(prefers-contrast: more)").matches`)).Should(BeFalse())
+			Ω(b.Run(`window.matchMedia("(prefers-reduced-motion: reduce)").matches`)).Should(BeTrue())
+			Ω("#media").Should(b.HaveScreenshot("ambient"))
+		})
+	})
+
 	Describe("the rendering freeze", func() {
 		BeforeEach(func() {
 			b.Navigate(fixtureServer + "/visual_animated.html")
@@ -0,0 +1,275 @@
+package biloba
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+)
+
+var watchCounter int64
+
+// maxWatchEvents is how many events a watch keeps before it keeps only appearances (see biloba.js).
+const maxWatchEvents = 1000
+
+/*
+WatchEvent is one thing a [Watch] saw happen to an element matching its selector: it "appeared" (was
+rendered and visible), "disappeared" (was removed or hidden), or its "text" changed.  At is how long
+after [Biloba.StartWatching] it happened, Element describes the element as a locator, and Text is its
+visible text at that moment (its last text, for a disappearance).
+*/
+type WatchEvent struct {
+	Kind    string
+	At      time.Duration
+	Element string
+	Text    string
+}
+
+func (e WatchEvent) String() string {
+	return fmt.Sprintf("+%ss %s %s %q", roundDuration(e.At), e.Kind, e.Element, e.Text)
+}
+
+/*
+Watch records, in the page, every appearance, disappearance and text change of the elements matching
+a selector - see [Biloba.StartWatching].  Assert on it with [Biloba.HaveAppeared],
+[Biloba.HaveNeverAppeared] and [Biloba.HaveShownText], or read its [Watch.Events].
+
+Read https://onsi.github.io/biloba/#watching-for-transient-elements to learn more
+*/
+type Watch struct {
+	b        *Biloba
+	id       string
+	selector any
+}
+
+// String describes the watch by its selector.
+func (w *Watch) String() string {
+	return fmt.Sprintf("watch(%s)", describeSelector(w.selector))
+}
+
+// GomegaString keeps failure messages to the selector, rather than the tab the watch runs in.
+func (w *Watch) GomegaString() string {
+	return w.String()
+}
+
+/*
+StartWatching(selector) starts recording what happens to the elements matching selector, from now on,
+and returns the [Watch] to assert on:
+
+	banner := b.StartWatching(b.ByRole("alert"))
+	b.Click(b.ByRole("button").WithName("Save"))
+	Eventually("#status").Should(b.HaveInnerText("Saved"))
+	Ω(banner).Should(b.HaveNeverAppeared())
+
+Polling samples the page now and then, so it misses a spinner or a flash message that is up for 50ms -
+and it can never show that something didn't flash by.  A Watch doesn't sample: a MutationObserver in
+the page re-checks the selector after every change to the DOM - open shadow roots included - recording
+each element that appears (matches and is visible), disappears, or changes its visible text.  Only a change that leaves the page
+within the task that made it - and so is never rendered - goes unseen.  Visibility that changes without
+touching the DOM (a CSS animation, a media query) is seen at the next DOM change.
+
+Elements that already match when the watch starts are recorded as appearing at +0s.  A watch keeps its
+first 1000 events; after that it keeps only appearances, and counts the events it dropped.  A watch
+lasts as long as the document - a navigation ends it - or until [Watch.Stop].  StartWatching acts
+immediately and does not poll.
+
+Read https://onsi.github.io/biloba/#watching-for-transient-elements to learn more
+*/
+func (b *Biloba) StartWatching(selector any) *Watch {
+	b.gt.Helper()
+	b.guardConfig("StartWatching")
+	w := &Watch{b: b, id: fmt.Sprintf("watch-%d", atomic.AddInt64(&watchCounter, 1)), selector: selector}
+	r := b.runBilobaHandler("startWatching", selector, w.id)
+	if r.Error() != nil {
+		b.gt.Fatalf("Failed to start watching:\n%s", r.Error())
+	}
+	return w
+}
+
+/*
+Events returns everything the watch has recorded so far, in order - the first 1000 events, then only
+the appearances.
+
+Read https://onsi.github.io/biloba/#watching-for-transient-elements to learn more
+*/
+func (w *Watch) Events() []WatchEvent {
+	w.b.gt.Helper()
+	watched, err := w.events()
+	if err != nil {
+		w.b.gt.Fatalf("Failed to read watch events:\n%s", err.Error())
+	}
+	return watched.Events
+}
+
+/*
+Stop stops the watch recording.  What it recorded stays readable until the page navigates.
+
+Read https://onsi.github.io/biloba/#watching-for-transient-elements to learn more
+*/
+func (w *Watch) Stop() {
+	w.b.gt.Helper()
+	if r := w.b.runBilobaFunc("stopWatching", w.id); r.Error() != nil {
+		w.b.gt.Fatalf("Failed to stop watching:\n%s", r.Error())
+	}
+}
+
+// watchRecord is what biloba.js reports for a watch: the events it kept, and how many it dropped.
+type watchRecord struct {
+	Events  []WatchEvent
+	Dropped int
+}
+
+func (w *Watch) events() (watchRecord, error) {
+	r := w.b.runBilobaFunc("watchEvents", w.id)
+	if r.Error() != nil {
+		return watchRecord{}, r.Error()
+	}
+	raw := struct {
+		Events []struct {
+			Kind    string  `json:"kind"`
+			At      float64 `json:"at"`
+			Element string  `json:"element"`
+			Text    string  `json:"text"`
+		} `json:"events"`
+		Dropped int `json:"dropped"`
+	}{}
+	if err := remarshal(r.Result, &raw); err != nil {
+		return watchRecord{}, err
+	}
+	watched := watchRecord{Events: make([]WatchEvent, len(raw.Events)), Dropped: raw.Dropped}
+	for i, e := range raw.Events {
+		watched.Events[i] = WatchEvent{Kind: e.Kind, At: time.Duration(e.At * float64(time.Millisecond)), Element: e.Element, Text: e.Text}
+	}
+	return watched, nil
+}
+
+// renderWatchEvents renders a watch's events for a failure message, one per line, and says so when the
+// watch dropped some.
+func renderWatchEvents(watched watchRecord) string {
+	if len(watched.Events) == 0 && watched.Dropped == 0 {
+		return "  (nothing)"
+	}
+	lines := make([]string, 0, len(watched.Events)+1)
+	for _, e := range watched.Events {
+		lines = append(lines, "  "+e.String())
+	}
+	if watched.Dropped > 0 {
+		lines = append(lines, fmt.Sprintf("  ...and %s it dropped after the first %d (it kept only appearances from then on)", pluralize(watched.Dropped, "more event"), maxWatchEvents))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// watchMatcher builds a matcher over the events of the *Watch it is applied to.  match returns whether
+// the events pass, and the value a Capture keeps.
+func (b *Biloba) watchMatcher(name string, match func(watchRecord) (bool, any, error), template string, data map[string]any) *ValueMatcher {
+	data["Name"] = name
+	return capturableResult(gcustom.MakeMatcher(func(actual any) (bool, error) {
+		w, ok := actual.(*Watch)
+		if !ok {
+			return false, fmt.Errorf("%s must be applied to the *biloba.Watch that StartWatching returns.  Got:\n%T", name, actual)
+		}
+		watched, err := w.events()
+		if err != nil {
+			return false, err
+		}
+		data["Events"] = renderWatchEvents(watched)
+		delete(data, "Result")
+		matched, result, err := match(watched)
+		if matched {
+			data["Result"] = result
+		}
+		return matched, err
+	}).WithTemplate(template+"\nThe watch saw:\n{{.Data.Events}}", data), data)
+}
+
+/*
+HaveAppeared() is a Gomega matcher that passes once the [Watch] it is applied to has seen an element
+matching its selector appear - however briefly:
+
+	spinner := b.StartWatching(".spinner")
+	b.Click("#refresh")
+	Eventually(spinner).Should(b.HaveAppeared())
+
+It returns a [ValueMatcher]: .Capture(&event) keeps the first appearance, a [WatchEvent].
+
+Read https://onsi.github.io/biloba/#watching-for-transient-elements to learn more
+*/
+func (b *Biloba) HaveAppeared() *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveAppeared")
+	return b.watchMatcher("HaveAppeared", func(watched watchRecord) (bool, any, error) {
+		for _, e := range watched.Events {
+			if e.Kind == "appeared" {
+				return true, e, nil
+			}
+		}
+		return false, nil, nil
+	}, "Expected {{.Actual}} {{.To}} have seen an element appear", map[string]any{})
+}
+
+/*
+HaveNeverAppeared() is a Gomega matcher that passes when the [Watch] it is applied to has never seen an
+element matching its selector appear - the way to show that an error banner didn't flash by:
+
+	errors := b.StartWatching(b.ByRole("alert"))
+	b.Click(b.ByRole("button").WithName("Save"))
+	Eventually("#status").Should(b.HaveInnerText("Saved"))
+	Ω(errors).Should(b.HaveNeverAppeared())
+
+It answers for everything up to the moment it is checked, so check it once the interesting part is over,
+with Ω - or with Consistently, to keep watching a while longer.  The failure lists everything the watch
+saw.  It fails, too, when the watch dropped events: it can no longer vouch for all of them.
+
+Read https://onsi.github.io/biloba/#watching-for-transient-elements to learn more
+*/
+func (b *Biloba) HaveNeverAppeared() types.GomegaMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveNeverAppeared")
+	return b.watchMatcher("HaveNeverAppeared", func(watched watchRecord) (bool, any, error) {
+		if watched.Dropped > 0 {
+			return false, nil, nil
+		}
+		for _, e := range watched.Events {
+			if e.Kind == "appeared" {
+				return false, nil, nil
+			}
+		}
+		return true, nil, nil
+	}, "Expected {{.Actual}} {{.To}} have never seen an element appear", map[string]any{})
+}
+
+/*
+HaveShownText(expected) is a Gomega matcher that passes once the [Watch] it is applied to has seen an
+element matching its selector show text matching expected - a string, or a Gomega matcher - when it
+appeared or as its text changed:
+
+	toast := b.StartWatching(".toast")
+	b.Click("#save")
+	Eventually(toast).Should(b.HaveShownText(ContainSubstring("Saved")))
+
+The toast may be long gone by the time the assertion runs.  It returns a [ValueMatcher]: .Capture(&text)
+keeps the first text that matched.
+
+Read https://onsi.github.io/biloba/#watching-for-transient-elements to learn more
+*/
+func (b *Biloba) HaveShownText(expected any) *ValueMatcher {
+	b.gt.Helper()
+	b.guardBareMatcher("HaveShownText")
+	matcher := matcherOrEqual(expected)
+	data := map[string]any{"Expected": format.Object(expected, 1)}
+	return b.watchMatcher("HaveShownText", func(watched watchRecord) (bool, any, error) {
+		for _, e := range watched.Events {
+			if e.Kind == "disappeared" {
+				continue
+			}
+			if ok, err := matcher.Match(e.Text); err == nil && ok {
+				return true, e.Text, nil
+			}
+		}
+		return false, nil, nil
+	}, "Expected {{.Actual}} {{.To}} have seen an element show text matching:\n{{.Data.Expected}}", data)
+}
@@ -0,0 +1,129 @@
+package biloba_test
+
+import (
+	"time"
+
+	"github.com/onsi/biloba"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watching for transient elements", func() {
+	BeforeEach(func() {
+		b.Navigate(fixtureServer + "/watch.html")
+		Eventually("#heading").Should(b.Exist())
+	})
+
+	It("catches an element that is gone before a poll could see it", func() {
+		flash := b.StartWatching(b.ByRole("alert"))
+		b.Click("#flash")
+		Eventually("#status").Should(b.HaveInnerText("Flashed"))
+		Ω(".flash").ShouldNot(b.Exist())
+
+		var appeared biloba.WatchEvent
+		Ω(flash).Should(b.HaveAppeared().Capture(&appeared))
+		Ω(appeared.Element).Should(Equal(`role=alert name="Saved!"`))
+		Ω(flash).Should(b.HaveShownText("Saved!"))
+		Ω(flash).ShouldNot(b.HaveNeverAppeared())
+
+		events := flash.Events()
+		Ω(events).Should(HaveLen(2))
+		Ω(events[0].Kind).Should(Equal("appeared"))
+		Ω(events[1].Kind).Should(Equal("disappeared"))
+		Ω(events[1].Text).Should(Equal("Saved!"))
+		Ω(events[1].At).Should(BeNumerically(">=", events[0].At+40*time.Millisecond))
+	})
+
+	It("proves something never appeared", func() {
+		flash := b.StartWatching(".flash")
+		b.Click("#count")
+		Eventually("#status").Should(b.HaveInnerText("Counted"))
+		Ω(flash).Should(b.HaveNeverAppeared())
+		Ω(flash.Events()).Should(BeEmpty())
+	})
+
+	It("lists what it saw when HaveNeverAppeared fails", func() {
+		flash := b.StartWatching(".flash")
+		b.Click("#flash")
+		Eventually("#status").Should(b.HaveInnerText("Flashed"))
+
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect(flash).To(b.HaveNeverAppeared())
+		Ω(failure).Should(ContainSubstring("watch(.flash)"))
+		Ω(failure).Should(ContainSubstring("to have never seen an element appear"))
+		Ω(failure).Should(MatchRegexp(`\+\d+\.\d\ds appeared role=alert name="Saved!" "Saved!"`))
+		Ω(failure).Should(MatchRegexp(`\+\d+\.\d\ds disappeared role=alert name="Saved!" "Saved!"`))
+	})
+
+	It("sees inside shadow roots", func() {
+		toast := b.StartWatching(b.ByRole("status"))
+		b.Click("#shadow-flash")
+		Eventually("#status").Should(b.HaveInnerText("Shadow flashed"))
+		Ω(toast).Should(b.HaveShownText("Copied to clipboard"))
+		Ω(toast.Events()).Should(HaveLen(2))
+	})
+
+	It("records what was there when it started, and every text change", func() {
+		counter := b.StartWatching("#counter")
+		b.Click("#count")
+		Eventually("#status").Should(b.HaveInnerText("Counted"))
+
+		var kinds, texts []string
+		for _, e := range counter.Events() {
+			kinds, texts = append(kinds, e.Kind), append(texts, e.Text)
+		}
+		Ω(kinds).Should(Equal([]string{"appeared", "text", "text", "text"}))
+		Ω(texts).Should(Equal([]string{"0", "1", "2", "3"}))
+
+		var text string
+		Ω(counter).Should(b.HaveShownText(MatchRegexp(`[2-9]`)).Capture(&text))
+		Ω(text).Should(Equal("2"))
+	})
+
+	It("treats being shown and hidden as appearing and disappearing", func() {
+		toast := b.StartWatching("#toast")
+		Ω(toast).Should(b.HaveNeverAppeared())
+		b.Click("#toast-button")
+		Eventually("#status").Should(b.HaveInnerText("Toasted"))
+		Ω(toast).Should(b.HaveShownText("Copied"))
+		Ω(b.HasElement("#toast")).Should(BeTrue())
+	})
+
+	It("keeps appearances once it has recorded its limit, and says what it dropped", func() {
+		ticker := b.StartWatching(".ticker")
+		b.Click("#tick")
+		Eventually("#status").Should(b.HaveInnerText("Ticked"))
+
+		events := ticker.Events()
+		Ω(events).Should(HaveLen(1001))
+		Ω(events[1000].Kind).Should(Equal("appeared"))
+		Ω(ticker).Should(b.HaveShownText("Done"))
+
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect(ticker).To(b.HaveNeverAppeared())
+		Ω(failure).Should(ContainSubstring("...and 101 more events it dropped after the first 1000"))
+	})
+
+	It("stops recording when stopped", func() {
+		flash := b.StartWatching(".flash")
+		flash.Stop()
+		b.Click("#flash")
+		Eventually("#status").Should(b.HaveInnerText("Flashed"))
+		Ω(flash).Should(b.HaveNeverAppeared())
+	})
+
+	It("fails when applied to anything but a watch, or after the page navigates", func() {
+		var failure string
+		g := NewGomega(func(message string, callerSkip ...int) { failure = message })
+		g.Expect(".flash").To(b.HaveAppeared())
+		Ω(failure).Should(ContainSubstring("HaveAppeared must be applied to the *biloba.Watch that StartWatching returns"))
+
+		flash := b.StartWatching(".flash")
+		b.Navigate(fixtureServer + "/watch.html")
+		Eventually("#heading").Should(b.Exist())
+		flash.Events()
+		ExpectFailures(ContainSubstring("this watch is not in this document - the page has navigated since StartWatching"))
+	})
+})